@@ -0,0 +1,58 @@
+package client
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// defaultBackoffInterval is the wait before the first retry.
+	defaultBackoffInterval = 100 * time.Millisecond
+
+	// defaultBackoffMultiplier is the factor the wait between retries grows by.
+	defaultBackoffMultiplier = 2.0
+)
+
+// exponentialBackoff computes the waits between the retries of an operation,
+// shared by the health check and the retries of the API calls.
+type exponentialBackoff struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	multiplier  float64
+	jitter      float64
+}
+
+// newExponentialBackoff returns a backoff starting at the given interval, 100ms if zero,
+// and growing by the given multiplier, 2 if zero, up to the given maximum interval, if any.
+func newExponentialBackoff(interval, maxInterval time.Duration, multiplier, jitter float64) *exponentialBackoff {
+	if interval == 0 {
+		interval = defaultBackoffInterval
+	}
+
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	return &exponentialBackoff{
+		interval:    interval,
+		maxInterval: maxInterval,
+		multiplier:  multiplier,
+		jitter:      jitter,
+	}
+}
+
+// next returns the wait before the next retry, randomized by the jitter, and grows the interval.
+func (b *exponentialBackoff) next() time.Duration {
+	wait := b.interval
+	if b.jitter > 0 {
+		delta := b.jitter * float64(wait)
+		wait = time.Duration(float64(wait) - delta + rand.Float64()*2*delta) //nolint:gosec // no need for a secure random number
+	}
+
+	b.interval = time.Duration(float64(b.interval) * b.multiplier)
+	if b.maxInterval > 0 && b.interval > b.maxInterval {
+		b.interval = b.maxInterval
+	}
+
+	return wait
+}
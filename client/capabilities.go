@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+)
+
+// Engine is the flavour of the container engine serving the Docker API.
+type Engine string
+
+const (
+	// EngineDocker is the Docker Engine.
+	EngineDocker Engine = "docker"
+
+	// EngineDockerDesktop is the Docker Engine of Docker Desktop.
+	EngineDockerDesktop Engine = "docker-desktop"
+
+	// EnginePodman is Podman, serving its Docker-compatible API.
+	EnginePodman Engine = "podman"
+)
+
+// containerdSnapshotterDriverType is the driver type reported by the daemons using the containerd image store.
+const containerdSnapshotterDriverType = "io.containerd.snapshotter.v1"
+
+// Capabilities are the capabilities of the docker daemon, probed from its info and version.
+// See [SDKClient.Capabilities].
+type Capabilities struct {
+	// Engine is the flavour of the container engine.
+	Engine Engine
+
+	// ServerVersion is the version of the engine, e.g. "28.5.1".
+	ServerVersion string
+
+	// APIVersion is the maximum version of the API supported by the daemon, e.g. "1.52".
+	APIVersion string
+
+	// OSType is the operating system the containers run on, e.g. "linux" or "windows".
+	OSType string
+
+	// Architecture is the architecture of the docker host, e.g. "x86_64" or "aarch64".
+	Architecture string
+
+	// Rootless reports whether the daemon runs as a non-root user.
+	Rootless bool
+
+	// CgroupVersion is the version of the cgroups of the docker host: 1 or 2, or 0 if unknown.
+	CgroupVersion int
+
+	// CgroupDriver is the cgroup driver of the daemon, e.g. "systemd" or "cgroupfs".
+	CgroupDriver string
+
+	// SecurityOptions are the names of the security options enabled in the daemon,
+	// e.g. "seccomp", "apparmor", "selinux", "userns", "rootless" or "cgroupns".
+	SecurityOptions []string
+
+	// ContainerdImageStore reports whether the daemon stores the images in containerd,
+	// which supports multi-platform images.
+	ContainerdImageStore bool
+}
+
+// HasSecurityOption reports whether the given security option is enabled in the daemon, e.g. "seccomp".
+func (c Capabilities) HasSecurityOption(name string) bool {
+	return slices.Contains(c.SecurityOptions, name)
+}
+
+// Capabilities returns the capabilities of the docker daemon. The result is cached
+// and reused every time Capabilities is called, and it's built on the cached result of Info.
+func (c *sdkClient) Capabilities(ctx context.Context) (Capabilities, error) {
+	c.mtx.RLock()
+	if c.capabilities != nil {
+		defer c.mtx.RUnlock()
+		return *c.capabilities, nil
+	}
+	c.mtx.RUnlock()
+
+	info, err := c.Info(ctx, client.InfoOptions{})
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	version, err := c.ServerVersion(ctx, client.ServerVersionOptions{})
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("server version: %w", err)
+	}
+
+	caps := Capabilities{
+		Engine:        EngineDocker,
+		ServerVersion: info.Info.ServerVersion,
+		APIVersion:    version.APIVersion,
+		OSType:        info.Info.OSType,
+		Architecture:  info.Info.Architecture,
+		CgroupDriver:  info.Info.CgroupDriver,
+	}
+
+	switch {
+	case slices.ContainsFunc(version.Components, func(cv system.ComponentVersion) bool {
+		return strings.HasPrefix(cv.Name, "Podman")
+	}):
+		caps.Engine = EnginePodman
+	case info.Info.OperatingSystem == "Docker Desktop" || strings.HasPrefix(version.Platform.Name, "Docker Desktop"):
+		caps.Engine = EngineDockerDesktop
+	}
+
+	if v, err := strconv.Atoi(info.Info.CgroupVersion); err == nil {
+		caps.CgroupVersion = v
+	}
+
+	for _, opt := range info.Info.SecurityOptions {
+		// each option is a comma-separated list of key=value pairs, e.g. "name=seccomp,profile=builtin"
+		for kv := range strings.SplitSeq(opt, ",") {
+			if name, ok := strings.CutPrefix(kv, "name="); ok {
+				caps.SecurityOptions = append(caps.SecurityOptions, name)
+			}
+		}
+	}
+	caps.Rootless = caps.HasSecurityOption("rootless")
+
+	for _, status := range info.Info.DriverStatus {
+		if status[0] == "driver-type" && status[1] == containerdSnapshotterDriverType {
+			caps.ContainerdImageStore = true
+		}
+	}
+
+	c.mtx.Lock()
+	c.capabilities = &caps
+	c.mtx.Unlock()
+
+	return caps, nil
+}
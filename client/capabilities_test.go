@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// capabilitiesMockCli is a mock implementation of client.APIClient,
+// returning the given info and version, and counting the calls.
+type capabilitiesMockCli struct {
+	mockCli
+
+	info    system.Info
+	version client.ServerVersionResult
+
+	infoCalls    int
+	versionCalls int
+}
+
+func (m *capabilitiesMockCli) Info(_ context.Context, _ client.InfoOptions) (client.SystemInfoResult, error) {
+	m.infoCalls++
+	return client.SystemInfoResult{Info: m.info}, nil
+}
+
+func (m *capabilitiesMockCli) ServerVersion(_ context.Context, _ client.ServerVersionOptions) (client.ServerVersionResult, error) {
+	m.versionCalls++
+	return m.version, nil
+}
+
+func (m *capabilitiesMockCli) ClientVersion() string {
+	return "1.52"
+}
+
+func TestCapabilities(t *testing.T) {
+	capabilities := func(t *testing.T, m *capabilitiesMockCli) Capabilities {
+		t.Helper()
+
+		sdk := newMockClient(t, m)
+
+		caps, err := sdk.Capabilities(context.Background())
+		require.NoError(t, err)
+		return caps
+	}
+
+	t.Run("docker", func(t *testing.T) {
+		m := &capabilitiesMockCli{
+			info: system.Info{
+				ServerVersion:   "28.5.1",
+				OSType:          "linux",
+				Architecture:    "x86_64",
+				CgroupVersion:   "2",
+				CgroupDriver:    "systemd",
+				SecurityOptions: []string{"name=apparmor", "name=seccomp,profile=builtin", "name=cgroupns"},
+				DriverStatus:    [][2]string{{"driver-type", "io.containerd.snapshotter.v1"}},
+			},
+			version: client.ServerVersionResult{APIVersion: "1.51", Components: []system.ComponentVersion{{Name: "Engine"}}},
+		}
+
+		sdk := newMockClient(t, m)
+
+		caps, err := sdk.Capabilities(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, Capabilities{
+			Engine:               EngineDocker,
+			ServerVersion:        "28.5.1",
+			APIVersion:           "1.51",
+			OSType:               "linux",
+			Architecture:         "x86_64",
+			CgroupVersion:        2,
+			CgroupDriver:         "systemd",
+			SecurityOptions:      []string{"apparmor", "seccomp", "cgroupns"},
+			ContainerdImageStore: true,
+		}, caps)
+		require.True(t, caps.HasSecurityOption("seccomp"))
+		require.False(t, caps.HasSecurityOption("selinux"))
+
+		// the capabilities are cached
+		_, err = sdk.Capabilities(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, m.infoCalls)
+		require.Equal(t, 1, m.versionCalls)
+	})
+
+	t.Run("docker-desktop", func(t *testing.T) {
+		caps := capabilities(t, &capabilitiesMockCli{
+			info: system.Info{OperatingSystem: "Docker Desktop", CgroupVersion: "2"},
+		})
+		require.Equal(t, EngineDockerDesktop, caps.Engine)
+		require.False(t, caps.ContainerdImageStore)
+	})
+
+	t.Run("podman-rootless", func(t *testing.T) {
+		caps := capabilities(t, &capabilitiesMockCli{
+			info:    system.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}, CgroupVersion: "1"},
+			version: client.ServerVersionResult{Components: []system.ComponentVersion{{Name: "Podman Engine"}}},
+		})
+		require.Equal(t, EnginePodman, caps.Engine)
+		require.True(t, caps.Rootless)
+		require.Equal(t, 1, caps.CgroupVersion)
+	})
+}
@@ -9,14 +9,25 @@ import (
 	"github.com/moby/moby/client"
 )
 
-// ContainerCreate creates a new container.
+// ContainerCreate creates a new container, once admitted by the admission policy of the client.
 func (c *sdkClient) ContainerCreate(ctx context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
 	if options.Config == nil {
 		return client.ContainerCreateResult{}, errdefs.ErrInvalidArgument.WithMessage("config is nil")
 	}
 
+	if err := c.admit(ctx, AdmissionRequest{
+		Operation:  AdmissionContainerCreate,
+		Image:      options.Config.Image,
+		Config:     options.Config,
+		HostConfig: options.HostConfig,
+	}); err != nil {
+		return client.ContainerCreateResult{}, err
+	}
+
 	// Add the labels that identify this as a container created by the SDK.
-	AddSDKLabels(options.Config.Labels)
+	options.Config.Labels = c.addSDKLabels(options.Config.Labels)
+
+	c.startReaper()
 
 	return c.APIClient.ContainerCreate(ctx, options)
 }
@@ -2,12 +2,16 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"maps"
+	"net"
+	"net/http"
 	"path/filepath"
-	"time"
+	"strings"
 
 	"github.com/moby/moby/client"
 
@@ -31,22 +35,8 @@ var (
 
 	defaultOpts = []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
 
-	defaultHealthCheck = func(ctx context.Context) func(c SDKClient) error {
-		return func(c SDKClient) error {
-			var pingErr error
-			for i := range 3 {
-				if _, pingErr = c.Ping(ctx, client.PingOptions{}); pingErr == nil {
-					return nil
-				}
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(time.Millisecond * time.Duration(i+1) * 100):
-				}
-			}
-			return fmt.Errorf("docker daemon not ready: %w", pingErr)
-		}
-	}
+	// defaultHealthCheck pings the docker daemon up to three times, waiting 100ms and 200ms between them.
+	defaultHealthCheck = HealthCheckPolicy{MaxAttempts: 3}.healthCheck
 )
 
 // New returns a new client for interacting with containers.
@@ -69,8 +59,10 @@ var (
 // The client is safe for concurrent use by multiple goroutines.
 func New(ctx context.Context, options ...ClientOption) (SDKClient, error) {
 	c := &sdkClient{
-		log:         defaultLogger,
-		healthCheck: defaultHealthCheck,
+		log:            defaultLogger,
+		healthCheck:    defaultHealthCheck,
+		reaperDisabled: reaperDisabledFromEnv(),
+		sessionID:      newSessionID(),
 	}
 	for _, opt := range options {
 		if err := opt.Apply(c); err != nil {
@@ -86,6 +78,13 @@ func New(ctx context.Context, options ...ClientOption) (SDKClient, error) {
 		return nil, fmt.Errorf("health check: %w", err)
 	}
 
+	// follow the docker context once the client is healthy
+	if c.contextWatch && c.dialer != nil {
+		if err := c.startWatch(); err != nil {
+			return nil, fmt.Errorf("context watch: %w", err)
+		}
+	}
+
 	return c, nil
 }
 
@@ -96,6 +95,14 @@ func (c *sdkClient) init() error {
 		return c.err
 	}
 
+	if c.contextWatch {
+		if c.dockerHost != "" && c.dockerContext == "" {
+			c.err = errors.New("context watch cannot be used with a docker host")
+			return c.err
+		}
+		c.watchedContext = c.dockerContext
+	}
+
 	// Set the default values for the client:
 	// - log
 	// - dockerHost
@@ -114,6 +121,13 @@ func (c *sdkClient) init() error {
 	// Add all collected Docker options
 	opts = append(opts, c.dockerOpts...)
 
+	// Instrument the API calls with the tracer and meter providers
+	telemetryOpts, err := c.telemetryOpts()
+	if err != nil {
+		return fmt.Errorf("telemetry: %w", err)
+	}
+	opts = append(opts, telemetryOpts...)
+
 	if c.cfg.TLSVerify {
 		// For further information see:
 		// https://docs.docker.com/engine/security/protect-access/#use-tls-https-to-protect-the-docker-daemon-socket
@@ -123,11 +137,43 @@ func (c *sdkClient) init() error {
 			filepath.Join(c.cfg.CertPath, tlsKeyFile),
 		))
 	}
-	if c.cfg.Host != "" {
+	if c.contextWatch {
+		// the TLS material of the docker context is applied by the dialer following it
+		if strings.HasPrefix(c.cfg.Host, dockercontext.SSHSchema) {
+			c.err = errors.New("context watch cannot be used with an ssh docker host")
+			return c.err
+		}
+	} else if c.tlsConfig != nil && !strings.HasPrefix(c.cfg.Host, dockercontext.SSHSchema) {
+		// the TLS material of the docker context takes precedence over the one of the environment
+		opts = append(opts, c.withHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: c.tlsConfig},
+			CheckRedirect: client.CheckRedirect,
+		}))
+	}
+	if strings.HasPrefix(c.cfg.Host, dockercontext.SSHSchema) {
+		sshOpts, err := c.sshOpts(c.cfg.Host)
+		if err != nil {
+			return fmt.Errorf("ssh: %w", err)
+		}
+		opts = append(opts, sshOpts...)
+	} else if c.cfg.Host != "" {
 		// apply the host from the config if it is set
 		opts = append(opts, client.WithHost(c.cfg.Host))
 	}
 
+	if c.transport != nil {
+		// replace the HTTP client once the host is applied, as docker only applies it to its own transport
+		opts = append(opts, c.withHTTPClient(&http.Client{Transport: c.transport}))
+	}
+
+	if c.contextWatch {
+		reconnectOpts, err := c.reconnectOpts(c.cfg.Host)
+		if err != nil {
+			return fmt.Errorf("context watch: %w", err)
+		}
+		opts = append(opts, reconnectOpts...)
+	}
+
 	httpHeaders := make(map[string]string)
 	maps.Copy(httpHeaders, c.extraHeaders)
 
@@ -136,18 +182,55 @@ func (c *sdkClient) init() error {
 
 	opts = append(opts, client.WithHTTPHeaders(httpHeaders))
 
+	// the docker client wraps the transport of its HTTP client with its own instrumentation
+	var transport http.RoundTripper
+	if c.httpClient != nil {
+		transport = c.httpClient.Transport
+	}
+
 	api, err := client.New(opts...)
 	if err != nil {
 		return fmt.Errorf("new client: %w", err)
 	}
 	c.APIClient = api
+	c.instrument(transport)
 	return nil
 }
 
+// withHTTPClient returns the docker client option setting its HTTP client,
+// keeping track of it to instrument its transport.
+func (c *sdkClient) withHTTPClient(httpClient *http.Client) client.Opt {
+	c.httpClient = httpClient
+	return client.WithHTTPClient(httpClient)
+}
+
+// sshOpts returns the options to connect to the given ssh:// docker host, dialing
+// the docker daemon through SSH, using the system ssh binary unless an [SSHDialer]
+// is provided with [WithSSHDialer].
+func (c *sdkClient) sshOpts(host string) ([]client.Opt, error) {
+	if c.sshDialer == nil {
+		dialer, err := NewSSHDialer(host)
+		if err != nil {
+			return nil, err
+		}
+		c.sshDialer = dialer
+	}
+	c.sshHost = host
+
+	dialer := c.sshDialer
+	return []client.Opt{
+		client.WithHost(sshPlaceholderHost),
+		client.WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialDaemon(ctx)
+		}),
+	}, nil
+}
+
 // defaultValues sets the default values for the client.
 // If no logger is provided, the default one is used.
 // If no docker host is provided and no docker context is provided, the current docker host and context are used.
-// If no docker host is provided but a docker context is provided, the docker host from the context is used.
+// If no docker host is provided but a docker context is provided, the docker host from the context is used,
+// with the TLS material of the context, if any.
 // If a docker host is provided, it is used as is.
 func (c *sdkClient) defaultValues() error {
 	if c.log == nil {
@@ -164,20 +247,74 @@ func (c *sdkClient) defaultValues() error {
 			return fmt.Errorf("current context: %w", err)
 		}
 
+		tlsConfig, err := currentContextTLS(currentContext, currentDockerHost)
+		if err != nil {
+			return fmt.Errorf("tls config from context: %w", err)
+		}
+
 		c.dockerHost = currentDockerHost
 		c.dockerContext = currentContext
+		c.tlsConfig = tlsConfig
 
 		return nil
 	}
 
 	if c.dockerContext != "" {
-		dockerHost, err := dockercontext.DockerHostFromContext(c.dockerContext)
+		dockerCtx, err := dockercontext.Inspect(c.dockerContext)
 		if err != nil {
-			return fmt.Errorf("docker host from context: %w", err)
+			return fmt.Errorf("docker host from context: inspect context: %w", err)
 		}
 
-		c.dockerHost = dockerHost
+		// Inspect already validates that the docker endpoint is set
+		c.dockerHost = dockerCtx.Endpoints[dockercontext.DockerEndpoint].Host
+
+		if c.tlsConfig, err = dockerCtx.TLSConfig(); err != nil {
+			return fmt.Errorf("tls config from context: %w", err)
+		}
 	}
 
 	return nil
 }
+
+// currentContextTLS returns the TLS configuration of the given current context, if the
+// current docker host is read from it, and not from the environment or the rootless socket.
+func currentContextTLS(currentContext, currentDockerHost string) (*tls.Config, error) {
+	if currentContext == dockercontext.DefaultContextName {
+		return nil, nil
+	}
+
+	dockerCtx, err := dockercontext.Inspect(currentContext)
+	if err != nil {
+		return nil, fmt.Errorf("inspect context: %w", err)
+	}
+
+	if dockerCtx.Endpoints[dockercontext.DockerEndpoint].Host != currentDockerHost {
+		return nil, nil
+	}
+
+	return dockerCtx.TLSConfig()
+}
+
+// Close stops following the docker context, if the client does, closes the forwarded ports,
+// removing the sidecar container forwarding them, stops the heartbeat of the session,
+// and closes the transport of the client.
+func (c *sdkClient) Close() error {
+	c.stopReaper()
+
+	// the transport is closed even if the watcher could not be closed, or the sidecar could not
+	// be removed, e.g. because the docker daemon is gone: the reaper removes it once the session is dead
+	var errs []error
+	if c.watcher != nil {
+		if err := c.watcher.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close watcher: %w", err))
+		}
+	}
+	if err := c.closeForwarders(); err != nil {
+		errs = append(errs, fmt.Errorf("close forwarders: %w", err))
+	}
+	if err := c.APIClient.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
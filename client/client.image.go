@@ -10,7 +10,18 @@ import (
 // ImageBuild builds an image from a build context and options.
 func (c *sdkClient) ImageBuild(ctx context.Context, context io.Reader, options client.ImageBuildOptions) (client.ImageBuildResult, error) {
 	// Add client labels
-	AddSDKLabels(options.Labels)
+	options.Labels = c.addSDKLabels(options.Labels)
+
+	c.startReaper()
 
 	return c.APIClient.ImageBuild(ctx, context, options)
 }
+
+// ImagePull pulls an image, once admitted by the admission policy of the client.
+func (c *sdkClient) ImagePull(ctx context.Context, ref string, options client.ImagePullOptions) (client.ImagePullResponse, error) {
+	if err := c.admit(ctx, AdmissionRequest{Operation: AdmissionImagePull, Image: ref}); err != nil {
+		return nil, err
+	}
+
+	return c.APIClient.ImagePull(ctx, ref, options)
+}
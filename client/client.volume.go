@@ -9,7 +9,9 @@ import (
 // VolumeCreate creates a new volume.
 func (c *sdkClient) VolumeCreate(ctx context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
 	// Add the labels that identify this as a volume created by the SDK.
-	AddSDKLabels(options.Labels)
+	options.Labels = c.addSDKLabels(options.Labels)
+
+	c.startReaper()
 
 	return c.APIClient.VolumeCreate(ctx, options)
 }
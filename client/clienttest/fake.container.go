@@ -0,0 +1,710 @@
+package clienttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/netip"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+)
+
+// fakeContainer is a container of the fake Docker API.
+type fakeContainer struct {
+	id      string
+	name    string
+	image   string
+	imageID string
+	created time.Time
+
+	config     *container.Config
+	hostConfig *container.HostConfig
+	state      container.State
+	networks   map[string]*network.EndpointSettings
+	ports      network.PortMap
+	mounts     []container.MountPoint
+
+	// memoryUsage is the memory usage reported by the stats of the container, while running.
+	memoryUsage uint64
+
+	// exited is closed when the container exits, and replaced when it starts.
+	exited chan struct{}
+
+	// removed is closed when the container is removed.
+	removed chan struct{}
+}
+
+// running reports whether the container is running, including paused.
+func (c *fakeContainer) running() bool {
+	return c.state.Running
+}
+
+// status returns the human-readable status of the container, e.g. "Up 2 seconds".
+func (c *fakeContainer) status() string {
+	switch c.state.Status {
+	case container.StateCreated:
+		return "Created"
+	case container.StateRunning:
+		return "Up " + since(c.state.StartedAt)
+	case container.StatePaused:
+		return "Up " + since(c.state.StartedAt) + " (Paused)"
+	default:
+		return fmt.Sprintf("Exited (%d) %s ago", c.state.ExitCode, since(c.state.FinishedAt))
+	}
+}
+
+// since returns the time elapsed since the given time, in the format of the Docker API.
+func since(t string) string {
+	started, err := time.Parse(time.RFC3339Nano, t)
+	if err != nil {
+		return "Less than a second"
+	}
+	elapsed := time.Since(started).Round(time.Second)
+	if elapsed < time.Second {
+		return "Less than a second"
+	}
+	return elapsed.String()
+}
+
+// inspect returns the inspect response of the container.
+func (c *fakeContainer) inspect() container.InspectResponse {
+	state := c.state
+	cfg := *c.config
+	cfg.Labels = maps.Clone(c.config.Labels)
+	hostCfg := *c.hostConfig
+
+	return container.InspectResponse{
+		ID:         c.id,
+		Created:    c.created.UTC().Format(time.RFC3339Nano),
+		Path:       firstOrEmpty(cfg.Entrypoint, cfg.Cmd),
+		State:      &state,
+		Image:      c.imageID,
+		Name:       "/" + c.name,
+		Driver:     "overlay2",
+		Platform:   "linux",
+		HostConfig: &hostCfg,
+		Mounts:     append([]container.MountPoint(nil), c.mounts...),
+		Config:     &cfg,
+		NetworkSettings: &container.NetworkSettings{
+			Ports:    maps.Clone(c.ports),
+			Networks: c.endpoints(),
+		},
+	}
+}
+
+// summary returns the summary of the container, as returned by the container list.
+func (c *fakeContainer) summary() container.Summary {
+	s := container.Summary{
+		ID:              c.id,
+		Names:           []string{"/" + c.name},
+		Image:           c.image,
+		ImageID:         c.imageID,
+		Command:         strings.Join(append(append([]string(nil), c.config.Entrypoint...), c.config.Cmd...), " "),
+		Created:         c.created.Unix(),
+		Labels:          maps.Clone(c.config.Labels),
+		State:           c.state.Status,
+		Status:          c.status(),
+		NetworkSettings: &container.NetworkSettingsSummary{Networks: c.endpoints()},
+		Mounts:          append([]container.MountPoint(nil), c.mounts...),
+	}
+	s.HostConfig.NetworkMode = string(c.hostConfig.NetworkMode)
+
+	for port, bindings := range c.ports {
+		for _, b := range bindings {
+			hostPort, _ := strconv.ParseUint(b.HostPort, 10, 16)
+			s.Ports = append(s.Ports, container.PortSummary{
+				IP:          b.HostIP,
+				PrivatePort: port.Num(),
+				PublicPort:  uint16(hostPort),
+				Type:        string(port.Proto()),
+			})
+		}
+	}
+
+	return s
+}
+
+// endpoints returns a copy of the endpoint settings of the container, by network name.
+func (c *fakeContainer) endpoints() map[string]*network.EndpointSettings {
+	endpoints := make(map[string]*network.EndpointSettings, len(c.networks))
+	for name, endpoint := range c.networks {
+		endpoints[name] = endpoint.Copy()
+	}
+	return endpoints
+}
+
+// firstOrEmpty returns the first element of the first non-empty slice.
+func firstOrEmpty(slices ...[]string) string {
+	for _, s := range slices {
+		if len(s) > 0 {
+			return s[0]
+		}
+	}
+	return ""
+}
+
+// findContainerLocked returns the container with the given ID, name, or ID prefix, if any.
+func (f *FakeAPI) findContainerLocked(ref string) *fakeContainer {
+	if c, ok := f.containers[ref]; ok {
+		return c
+	}
+
+	name := strings.TrimPrefix(ref, "/")
+	for _, c := range f.containers {
+		if c.name == name {
+			return c
+		}
+	}
+
+	if ref == "" {
+		return nil
+	}
+	for _, c := range f.containers {
+		if strings.HasPrefix(c.id, ref) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// getContainerLocked returns the container with the given ID or name, or a not found error.
+func (f *FakeAPI) getContainerLocked(ref string) (*fakeContainer, error) {
+	c := f.findContainerLocked(ref)
+	if c == nil {
+		return nil, errdefs.ErrNotFound.WithMessage(errNoSuch("container", ref))
+	}
+	return c, nil
+}
+
+// ContainerCreate creates a container from an existing image, connecting it to its networks,
+// and creating the named volumes it mounts if they don't exist.
+func (f *FakeAPI) ContainerCreate(_ context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	if options.Config == nil {
+		return client.ContainerCreateResult{}, errdefs.ErrInvalidArgument.WithMessage("config is nil")
+	}
+
+	cfg := *options.Config
+	cfg.Labels = maps.Clone(cfg.Labels)
+	if options.Image != "" {
+		cfg.Image = options.Image
+	}
+
+	hostCfg := container.HostConfig{}
+	if options.HostConfig != nil {
+		hostCfg = *options.HostConfig
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	img := f.findImageLocked(cfg.Image)
+	if img == nil {
+		return client.ContainerCreateResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("image", cfg.Image))
+	}
+
+	c := &fakeContainer{
+		id:         newID(),
+		name:       strings.TrimPrefix(options.Name, "/"),
+		image:      cfg.Image,
+		imageID:    img.id,
+		created:    time.Now(),
+		config:     &cfg,
+		hostConfig: &hostCfg,
+		state:      container.State{Status: container.StateCreated},
+		networks:   make(map[string]*network.EndpointSettings),
+		exited:     make(chan struct{}),
+		removed:    make(chan struct{}),
+	}
+	if c.name == "" {
+		c.name = "fake_" + c.id[:12]
+	}
+	if cfg.Labels == nil {
+		cfg.Labels = map[string]string{}
+	}
+
+	if other := f.findContainerLocked(c.name); other != nil && other.name == c.name {
+		return client.ContainerCreateResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf(
+			"Conflict. The container name %q is already in use by container %q. You have to remove (or rename) that container to be able to reuse that name.",
+			"/"+c.name, other.id))
+	}
+
+	if err := f.connectNetworksLocked(c, options.NetworkingConfig); err != nil {
+		return client.ContainerCreateResult{}, err
+	}
+
+	c.mounts = f.mountVolumesLocked(&hostCfg)
+
+	f.containers[c.id] = c
+	return client.ContainerCreateResult{ID: c.id}, nil
+}
+
+// connectNetworksLocked connects a new container to the network of its network mode, "bridge" by default,
+// and to the networks of the networking config. Containers sharing the network of another container, or
+// with the "none" network mode, are not connected to any other network.
+func (f *FakeAPI) connectNetworksLocked(c *fakeContainer, networkingConfig *network.NetworkingConfig) error {
+	mode := string(c.hostConfig.NetworkMode)
+	if mode == "" || mode == "default" {
+		mode = "bridge"
+		c.hostConfig.NetworkMode = container.NetworkMode(mode)
+	}
+	if strings.HasPrefix(mode, "container:") {
+		return nil
+	}
+
+	endpoints := map[string]*network.EndpointSettings{mode: nil}
+	if networkingConfig != nil && mode != "none" && mode != "host" {
+		maps.Copy(endpoints, networkingConfig.EndpointsConfig)
+	}
+
+	for name := range endpoints {
+		if f.findNetworkLocked(name) == nil {
+			return errdefs.ErrNotFound.WithMessage("network " + name + " not found")
+		}
+	}
+
+	for name, settings := range endpoints {
+		nw := f.findNetworkLocked(name)
+		endpoint, err := f.connectLocked(nw, c, settings)
+		if err != nil {
+			return err
+		}
+		c.networks[nw.Name] = endpoint
+	}
+
+	return nil
+}
+
+// mountVolumesLocked returns the mount points of the volumes and binds of the host config,
+// creating the named volumes that don't exist.
+func (f *FakeAPI) mountVolumesLocked(hostCfg *container.HostConfig) []container.MountPoint {
+	var mounts []container.MountPoint
+
+	addVolume := func(name, target string, readOnly bool) {
+		v, ok := f.volumes[name]
+		if !ok {
+			v = f.addVolumeLocked(client.VolumeCreateOptions{Name: name})
+		}
+		mounts = append(mounts, container.MountPoint{
+			Type:        mount.TypeVolume,
+			Name:        name,
+			Source:      v.Mountpoint,
+			Destination: target,
+			Driver:      v.Driver,
+			RW:          !readOnly,
+		})
+	}
+
+	for _, bind := range hostCfg.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) < 2 {
+			continue
+		}
+		readOnly := len(parts) > 2 && strings.Contains(parts[2], "ro")
+
+		if filepath.IsAbs(parts[0]) {
+			mounts = append(mounts, container.MountPoint{
+				Type:        mount.TypeBind,
+				Source:      parts[0],
+				Destination: parts[1],
+				RW:          !readOnly,
+			})
+			continue
+		}
+		addVolume(parts[0], parts[1], readOnly)
+	}
+
+	for _, m := range hostCfg.Mounts {
+		switch m.Type {
+		case mount.TypeVolume:
+			name := m.Source
+			if name == "" {
+				name = newID()
+			}
+			addVolume(name, m.Target, m.ReadOnly)
+		default:
+			mounts = append(mounts, container.MountPoint{
+				Type:        m.Type,
+				Source:      m.Source,
+				Destination: m.Target,
+				RW:          !m.ReadOnly,
+			})
+		}
+	}
+
+	return mounts
+}
+
+// publishPortsLocked binds the published ports of the container to host ports, assigning
+// a free host port to the bindings without one.
+func (f *FakeAPI) publishPortsLocked(c *fakeContainer) {
+	bindings := maps.Clone(c.hostConfig.PortBindings)
+	if bindings == nil {
+		bindings = network.PortMap{}
+	}
+	if c.hostConfig.PublishAllPorts {
+		for port := range c.config.ExposedPorts {
+			if _, ok := bindings[port]; !ok {
+				bindings[port] = []network.PortBinding{{}}
+			}
+		}
+	}
+
+	c.ports = network.PortMap{}
+	for port := range c.config.ExposedPorts {
+		c.ports[port] = nil
+	}
+
+	for port, portBindings := range bindings {
+		for _, b := range portBindings {
+			if b.HostPort == "" || b.HostPort == "0" {
+				b.HostPort = strconv.Itoa(int(f.nextPort))
+				f.nextPort++
+			}
+			if !b.HostIP.IsValid() {
+				b.HostIP = netip.IPv4Unspecified()
+			}
+			c.ports[port] = append(c.ports[port], b)
+		}
+	}
+}
+
+// exitLocked stops the container with the given exit code, removing it if it was created with auto-remove.
+func (f *FakeAPI) exitLocked(c *fakeContainer, exitCode int) {
+	c.state.Status = container.StateExited
+	c.state.Running = false
+	c.state.Paused = false
+	c.state.Pid = 0
+	c.state.ExitCode = exitCode
+	c.state.FinishedAt = now()
+	c.ports = nil
+	close(c.exited)
+
+	if c.hostConfig.AutoRemove {
+		f.removeLocked(c)
+	}
+}
+
+// removeLocked removes the container, disconnecting it from its networks.
+func (f *FakeAPI) removeLocked(c *fakeContainer) {
+	for _, nw := range f.networks {
+		delete(nw.endpoints, c.id)
+	}
+	delete(f.containers, c.id)
+	close(c.removed)
+}
+
+// Exit simulates the exit of the process of the running container with the given ID or name,
+// with the given exit code, e.g. to test the handling of a container that crashes.
+func (f *FakeAPI) Exit(ref string, exitCode int) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return err
+	}
+
+	if !c.running() {
+		return errdefs.ErrConflict.WithMessage(fmt.Sprintf("Container %s is not running", c.id))
+	}
+
+	f.exitLocked(c, exitCode)
+	return nil
+}
+
+// ContainerStart starts the container, publishing its ports. The containers with a healthcheck
+// are healthy once started. Starting a running container is a no-op.
+func (f *FakeAPI) ContainerStart(_ context.Context, ref string, _ client.ContainerStartOptions) (client.ContainerStartResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerStartResult{}, err
+	}
+
+	if c.state.Paused {
+		return client.ContainerStartResult{}, errdefs.ErrConflict.WithMessage("cannot start a paused container, try unpause instead")
+	}
+	if c.running() {
+		return client.ContainerStartResult{}, nil
+	}
+
+	if c.state.Status == container.StateExited {
+		c.exited = make(chan struct{})
+	}
+	c.state = container.State{
+		Status:    container.StateRunning,
+		Running:   true,
+		Pid:       1000 + len(f.containers),
+		StartedAt: now(),
+	}
+	if hc := c.config.Healthcheck; hc != nil && len(hc.Test) > 0 && hc.Test[0] != "NONE" {
+		c.state.Health = &container.Health{Status: container.Healthy}
+	}
+	f.publishPortsLocked(c)
+
+	return client.ContainerStartResult{}, nil
+}
+
+// ContainerStop stops the container, which exits with code 0. Stopping a stopped container is a no-op.
+func (f *FakeAPI) ContainerStop(_ context.Context, ref string, _ client.ContainerStopOptions) (client.ContainerStopResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerStopResult{}, err
+	}
+
+	if c.running() {
+		f.exitLocked(c, 0)
+	}
+
+	return client.ContainerStopResult{}, nil
+}
+
+// ContainerKill kills the running container, which exits with code 137.
+func (f *FakeAPI) ContainerKill(_ context.Context, ref string, _ client.ContainerKillOptions) (client.ContainerKillResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerKillResult{}, err
+	}
+
+	if !c.running() {
+		return client.ContainerKillResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf("cannot kill container: %s: container %s is not running", ref, c.id))
+	}
+
+	f.exitLocked(c, 137)
+	return client.ContainerKillResult{}, nil
+}
+
+// ContainerRestart stops the container, if running, and starts it again.
+func (f *FakeAPI) ContainerRestart(ctx context.Context, ref string, _ client.ContainerRestartOptions) (client.ContainerRestartResult, error) {
+	if _, err := f.ContainerStop(ctx, ref, client.ContainerStopOptions{}); err != nil {
+		return client.ContainerRestartResult{}, err
+	}
+
+	if _, err := f.ContainerStart(ctx, ref, client.ContainerStartOptions{}); err != nil {
+		return client.ContainerRestartResult{}, err
+	}
+
+	return client.ContainerRestartResult{}, nil
+}
+
+// ContainerPause pauses the running container.
+func (f *FakeAPI) ContainerPause(_ context.Context, ref string, _ client.ContainerPauseOptions) (client.ContainerPauseResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerPauseResult{}, err
+	}
+
+	if !c.running() {
+		return client.ContainerPauseResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf("container %s is not running", c.id))
+	}
+	if c.state.Paused {
+		return client.ContainerPauseResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf("container %s is already paused", c.id))
+	}
+
+	c.state.Status = container.StatePaused
+	c.state.Paused = true
+
+	return client.ContainerPauseResult{}, nil
+}
+
+// ContainerUnpause resumes the paused container.
+func (f *FakeAPI) ContainerUnpause(_ context.Context, ref string, _ client.ContainerUnpauseOptions) (client.ContainerUnpauseResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerUnpauseResult{}, err
+	}
+
+	if !c.state.Paused {
+		return client.ContainerUnpauseResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf("container %s is not paused", c.id))
+	}
+
+	c.state.Status = container.StateRunning
+	c.state.Paused = false
+
+	return client.ContainerUnpauseResult{}, nil
+}
+
+// ContainerRemove removes the container. Running containers cannot be removed, unless forced.
+func (f *FakeAPI) ContainerRemove(_ context.Context, ref string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerRemoveResult{}, err
+	}
+
+	if c.running() {
+		if !options.Force {
+			return client.ContainerRemoveResult{}, errdefs.ErrConflict.WithMessage(fmt.Sprintf(
+				"cannot remove container %q: container is %s: stop the container before removing or force remove", "/"+c.name, c.state.Status))
+		}
+
+		// a removed container is not auto-removed
+		c.hostConfig.AutoRemove = false
+		f.exitLocked(c, 137)
+	}
+
+	f.removeLocked(c)
+	return client.ContainerRemoveResult{}, nil
+}
+
+// ContainerInspect returns the container with the given ID or name.
+func (f *FakeAPI) ContainerInspect(_ context.Context, ref string, _ client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerInspectResult{}, err
+	}
+
+	return client.ContainerInspectResult{Container: c.inspect()}, nil
+}
+
+// ContainerList returns the running containers, or all of them, matching the "id", "name",
+// "label", "status", "ancestor" and "network" filters.
+func (f *FakeAPI) ContainerList(_ context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var items []container.Summary
+	for _, c := range f.containers {
+		if !options.All && !c.running() && len(options.Filters["status"]) == 0 {
+			continue
+		}
+
+		if !matchFilters(options.Filters, map[string]matcher{
+			"id":     matchIDPrefix(c.id),
+			"name":   matchRegexp(c.name, "/"+c.name),
+			"label":  matchLabel(c.config.Labels),
+			"status": matchEqual(string(c.state.Status)),
+			"ancestor": func(value string) bool {
+				img := f.findImageLocked(value)
+				return img != nil && img.id == c.imageID
+			},
+			"network": func(value string) bool {
+				nw := f.findNetworkLocked(value)
+				return nw != nil && c.networks[nw.Name] != nil
+			},
+		}) {
+			continue
+		}
+
+		items = append(items, c.summary())
+	}
+
+	return client.ContainerListResult{Items: items}, nil
+}
+
+// ContainerWait waits for the container to meet the given condition: to be stopped, which is the default
+// and returns immediately for stopped containers, to exit the next time, or to be removed.
+func (f *FakeAPI) ContainerWait(ctx context.Context, ref string, options client.ContainerWaitOptions) client.ContainerWaitResult {
+	resultC := make(chan container.WaitResponse, 1)
+	errC := make(chan error, 1)
+
+	f.mtx.Lock()
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		f.mtx.Unlock()
+		errC <- err
+		return client.ContainerWaitResult{Result: resultC, Error: errC}
+	}
+
+	done := c.exited
+	switch options.Condition {
+	case container.WaitConditionRemoved:
+		done = c.removed
+	case container.WaitConditionNextExit:
+	default:
+		if !c.running() {
+			done = make(chan struct{})
+			close(done)
+		}
+	}
+	f.mtx.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			errC <- ctx.Err()
+		case <-done:
+			f.mtx.Lock()
+			exitCode := c.state.ExitCode
+			f.mtx.Unlock()
+			resultC <- container.WaitResponse{StatusCode: int64(exitCode)}
+		}
+	}()
+
+	return client.ContainerWaitResult{Result: resultC, Error: errC}
+}
+
+// SetMemoryUsage sets the memory usage reported by the stats of the container with the given ID or name,
+// while it's running.
+func (f *FakeAPI) SetMemoryUsage(ref string, usage uint64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return err
+	}
+
+	c.memoryUsage = usage
+	return nil
+}
+
+// ContainerStats returns a single stats sample of the container, with the memory usage set with
+// [FakeAPI.SetMemoryUsage] if it's running, even when streaming the stats.
+func (f *FakeAPI) ContainerStats(_ context.Context, ref string, _ client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	c, err := f.getContainerLocked(ref)
+	if err != nil {
+		return client.ContainerStatsResult{}, err
+	}
+
+	stats := container.StatsResponse{
+		ID:   c.id,
+		Name: "/" + c.name,
+		Read: time.Now(),
+	}
+	if c.running() {
+		stats.MemoryStats.Usage = c.memoryUsage
+		stats.MemoryStats.Limit = 8 << 30
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return client.ContainerStatsResult{}, err
+	}
+
+	return client.ContainerStatsResult{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
@@ -0,0 +1,189 @@
+// Package clienttest provides helpers to unit test code built on the SDK without a Docker daemon:
+// an in-memory fake of the Docker API, simulating containers, networks, volumes and images,
+// and a [Recorder] capturing the HTTP exchanges with a real daemon to replay them later.
+package clienttest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+
+	sdkclient "github.com/docker/go-sdk/client"
+)
+
+const (
+	// FakeDaemonHost is the docker host of the fake Docker API.
+	FakeDaemonHost = "unix:///var/run/docker.sock"
+
+	// FakeAPIVersion is the API version of the fake Docker API.
+	FakeAPIVersion = "1.52"
+
+	// FakeServerVersion is the version of the fake Docker daemon.
+	FakeServerVersion = "28.5.0-fake"
+)
+
+var _ client.APIClient = &FakeAPI{}
+
+// FakeAPI is an in-memory fake of the Docker API, which simulates the lifecycle of containers,
+// networks, volumes and images, with the state transitions and errors of a real daemon, e.g.
+// a container must be stopped before removing it, unless forced. The containers don't run any
+// process: they keep running until they are stopped, or until [FakeAPI.Exit] is called.
+//
+// Only the methods used to manage those resources, and the system methods, are implemented:
+// calling any other method of the [client.APIClient] interface panics.
+//
+// FakeAPI is safe for concurrent use by multiple goroutines.
+type FakeAPI struct {
+	client.APIClient
+
+	mtx sync.Mutex
+
+	containers map[string]*fakeContainer
+	networks   map[string]*fakeNetwork
+	volumes    map[string]*volume.Volume
+	images     map[string]*fakeImage
+
+	// nextPort is the next host port assigned to the published ports of the containers.
+	nextPort uint16
+
+	// nextSubnet is the second octet of the subnet of the next network.
+	nextSubnet byte
+}
+
+// NewFakeAPI returns a new fake Docker API, with the default "bridge", "host" and "none"
+// networks, and no containers, volumes or images.
+func NewFakeAPI() *FakeAPI {
+	f := &FakeAPI{
+		containers: make(map[string]*fakeContainer),
+		networks:   make(map[string]*fakeNetwork),
+		volumes:    make(map[string]*volume.Volume),
+		images:     make(map[string]*fakeImage),
+		nextPort:   32768,
+		nextSubnet: 17,
+	}
+
+	for _, driver := range []string{"bridge", "host", "null"} {
+		name := driver
+		if driver == "null" {
+			name = "none"
+		}
+		f.addNetwork(name, client.NetworkCreateOptions{Driver: driver})
+	}
+
+	return f
+}
+
+// NewFakeClient returns a new SDK client backed by the given fake Docker API, applying the given options.
+// The health check of the client pings the fake API, and the removal of the resources of dead sessions
+// is disabled, as with any client using a custom docker client.
+func NewFakeClient(ctx context.Context, fake *FakeAPI, opts ...sdkclient.ClientOption) (sdkclient.SDKClient, error) {
+	return sdkclient.New(ctx, append([]sdkclient.ClientOption{sdkclient.WithDockerAPI(fake)}, opts...)...)
+}
+
+// ClientVersion returns the API version of the fake Docker API.
+func (f *FakeAPI) ClientVersion() string {
+	return FakeAPIVersion
+}
+
+// DaemonHost returns the docker host of the fake Docker API.
+func (f *FakeAPI) DaemonHost() string {
+	return FakeDaemonHost
+}
+
+// Close is a no-op, the state of the fake Docker API is kept.
+func (f *FakeAPI) Close() error {
+	return nil
+}
+
+// Ping returns the API version and the OS type of the fake Docker daemon.
+func (f *FakeAPI) Ping(_ context.Context, _ client.PingOptions) (client.PingResult, error) {
+	return client.PingResult{APIVersion: FakeAPIVersion, OSType: "linux"}, nil
+}
+
+// Info returns the information of the fake Docker daemon, counting its containers and images.
+func (f *FakeAPI) Info(_ context.Context, _ client.InfoOptions) (client.SystemInfoResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	info := system.Info{
+		ID:              "FAKE",
+		Name:            "fake",
+		ServerVersion:   FakeServerVersion,
+		OperatingSystem: "Fake Docker",
+		OSType:          "linux",
+		Architecture:    "x86_64",
+		Driver:          "overlay2",
+		CgroupDriver:    "systemd",
+		CgroupVersion:   "2",
+		NCPU:            4,
+		MemTotal:        8 << 30,
+		Images:          len(f.images),
+		Containers:      len(f.containers),
+	}
+	for _, c := range f.containers {
+		switch c.state.Status {
+		case container.StateRunning:
+			info.ContainersRunning++
+		case container.StatePaused:
+			info.ContainersPaused++
+		default:
+			info.ContainersStopped++
+		}
+	}
+
+	return client.SystemInfoResult{Info: info}, nil
+}
+
+// ServerVersion returns the version of the fake Docker daemon.
+func (f *FakeAPI) ServerVersion(_ context.Context, _ client.ServerVersionOptions) (client.ServerVersionResult, error) {
+	return client.ServerVersionResult{
+		Platform:      client.PlatformInfo{Name: "Fake Docker Engine"},
+		Version:       FakeServerVersion,
+		APIVersion:    FakeAPIVersion,
+		MinAPIVersion: "1.24",
+		Os:            "linux",
+		Arch:          "amd64",
+	}, nil
+}
+
+// newID returns a new random ID, with the length of the IDs of the Docker resources.
+func newID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// now returns the current time in the format used by the Docker API.
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// nextSubnetLocked returns the subnet and gateway of a new network, e.g. 172.18.0.0/16 and 172.18.0.1.
+func (f *FakeAPI) nextSubnetLocked() (netip.Prefix, netip.Addr) {
+	prefix := netip.PrefixFrom(netip.AddrFrom4([4]byte{172, f.nextSubnet, 0, 0}), 16)
+	gateway := netip.AddrFrom4([4]byte{172, f.nextSubnet, 0, 1})
+	f.nextSubnet++
+	return prefix, gateway
+}
+
+// shortID returns the short form of the given ID, used in the error messages.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// errNoSuch returns the message of the not found errors of the Docker API, e.g. "No such container: foo".
+func errNoSuch(kind, ref string) string {
+	return fmt.Sprintf("No such %s: %s", kind, ref)
+}
@@ -0,0 +1,260 @@
+package clienttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/client"
+)
+
+// fakeImage is an image of the fake Docker API.
+type fakeImage struct {
+	id       string
+	repoTags []string
+	created  time.Time
+}
+
+// AddImage adds an image with the given reference to the fake Docker API, as if it had been pulled,
+// returning its ID. If the image already exists, its ID is returned.
+func (f *FakeAPI) AddImage(ref string) (string, error) {
+	tag, err := normalizeRef(ref)
+	if err != nil {
+		return "", errdefs.ErrInvalidArgument.WithMessage(err.Error())
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return f.addImageLocked(tag).id, nil
+}
+
+// addImageLocked adds an image with the given normalized reference, if it doesn't exist.
+func (f *FakeAPI) addImageLocked(tag string) *fakeImage {
+	if img := f.findImageLocked(tag); img != nil {
+		return img
+	}
+
+	img := &fakeImage{id: "sha256:" + newID(), repoTags: []string{tag}, created: time.Now()}
+	f.images[img.id] = img
+	return img
+}
+
+// findImageLocked returns the image with the given ID, ID prefix, or reference, if any.
+func (f *FakeAPI) findImageLocked(ref string) *fakeImage {
+	if img, ok := f.images[ref]; ok {
+		return img
+	}
+
+	if tag, err := normalizeRef(ref); err == nil {
+		for _, img := range f.images {
+			if slices.Contains(img.repoTags, tag) {
+				return img
+			}
+		}
+	}
+
+	id := strings.TrimPrefix(ref, "sha256:")
+	for _, img := range f.images {
+		if len(id) >= 12 && strings.HasPrefix(strings.TrimPrefix(img.id, "sha256:"), id) {
+			return img
+		}
+	}
+
+	return nil
+}
+
+// normalizeRef returns the familiar form of the given reference, with the default tag if it has none,
+// e.g. "nginx:latest" for "docker.io/library/nginx".
+func normalizeRef(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named)), nil
+}
+
+// ImagePull adds the image with the given reference, if it doesn't exist, returning the progress
+// messages of the pull.
+func (f *FakeAPI) ImagePull(_ context.Context, ref string, _ client.ImagePullOptions) (client.ImagePullResponse, error) {
+	tag, err := normalizeRef(ref)
+	if err != nil {
+		return nil, errdefs.ErrInvalidArgument.WithMessage(err.Error())
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	status := "Status: Image is up to date for " + tag
+	if f.findImageLocked(tag) == nil {
+		status = "Status: Downloaded newer image for " + tag
+	}
+	f.addImageLocked(tag)
+
+	name, version, _ := strings.Cut(tag, ":")
+	return newPullResponse(
+		jsonstream.Message{ID: version, Status: "Pulling from " + name},
+		jsonstream.Message{Status: status},
+	), nil
+}
+
+// ImageInspect returns the image with the given ID or reference.
+func (f *FakeAPI) ImageInspect(_ context.Context, ref string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	img := f.findImageLocked(ref)
+	if img == nil {
+		return client.ImageInspectResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("image", ref))
+	}
+
+	return client.ImageInspectResult{InspectResponse: image.InspectResponse{
+		ID:           img.id,
+		RepoTags:     slices.Clone(img.repoTags),
+		Created:      img.created.UTC().Format(time.RFC3339Nano),
+		Architecture: "amd64",
+		Os:           "linux",
+	}}, nil
+}
+
+// ImageList returns the images matching the "reference" and "label" filters.
+func (f *FakeAPI) ImageList(_ context.Context, options client.ImageListOptions) (client.ImageListResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var items []image.Summary
+	for _, img := range f.images {
+		if !matchFilters(options.Filters, map[string]matcher{
+			"reference": func(value string) bool {
+				tag, err := normalizeRef(value)
+				return err == nil && slices.Contains(img.repoTags, tag)
+			},
+			"label": matchLabel(nil),
+		}) {
+			continue
+		}
+
+		items = append(items, image.Summary{
+			ID:       img.id,
+			RepoTags: slices.Clone(img.repoTags),
+			Created:  img.created.Unix(),
+			Labels:   map[string]string{},
+		})
+	}
+
+	return client.ImageListResult{Items: items}, nil
+}
+
+// ImageTag adds the target reference to the image of the source reference.
+func (f *FakeAPI) ImageTag(_ context.Context, options client.ImageTagOptions) (client.ImageTagResult, error) {
+	target, err := normalizeRef(options.Target)
+	if err != nil {
+		return client.ImageTagResult{}, errdefs.ErrInvalidArgument.WithMessage(err.Error())
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	img := f.findImageLocked(options.Source)
+	if img == nil {
+		return client.ImageTagResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("image", options.Source))
+	}
+
+	// a reference points to a single image
+	for _, other := range f.images {
+		other.repoTags = slices.DeleteFunc(other.repoTags, func(t string) bool { return t == target })
+	}
+	img.repoTags = append(img.repoTags, target)
+
+	return client.ImageTagResult{}, nil
+}
+
+// ImageRemove removes the given reference from its image, deleting the image once it has no references.
+// Images used by containers cannot be removed, unless forced.
+func (f *FakeAPI) ImageRemove(_ context.Context, ref string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	img := f.findImageLocked(ref)
+	if img == nil {
+		return client.ImageRemoveResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("image", ref))
+	}
+
+	if !options.Force {
+		for _, c := range f.containers {
+			if c.imageID == img.id {
+				return client.ImageRemoveResult{}, errdefs.ErrConflict.WithMessage(
+					"conflict: unable to remove repository reference \"" + ref + "\" (must force) - container " +
+						shortID(c.id) + " is using its referenced image " + shortID(strings.TrimPrefix(img.id, "sha256:")))
+			}
+		}
+	}
+
+	var result client.ImageRemoveResult
+
+	// removing a reference of an image with several references only untags it
+	if tag, err := normalizeRef(ref); err == nil && slices.Contains(img.repoTags, tag) && len(img.repoTags) > 1 {
+		img.repoTags = slices.DeleteFunc(img.repoTags, func(t string) bool { return t == tag })
+		result.Items = append(result.Items, image.DeleteResponse{Untagged: tag})
+		return result, nil
+	}
+
+	for _, tag := range img.repoTags {
+		result.Items = append(result.Items, image.DeleteResponse{Untagged: tag})
+	}
+	result.Items = append(result.Items, image.DeleteResponse{Deleted: img.id})
+	delete(f.images, img.id)
+
+	return result, nil
+}
+
+// pullResponse is the response of a pull of the fake Docker API, with its progress messages.
+type pullResponse struct {
+	io.Reader
+
+	messages []jsonstream.Message
+}
+
+// newPullResponse returns a pull response streaming the given progress messages as JSON lines.
+func newPullResponse(messages ...jsonstream.Message) *pullResponse {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, msg := range messages {
+		_ = enc.Encode(msg)
+	}
+	return &pullResponse{Reader: &buf, messages: messages}
+}
+
+// Close is a no-op, the pull is already done.
+func (r *pullResponse) Close() error {
+	return nil
+}
+
+// JSONMessages returns the progress messages of the pull.
+func (r *pullResponse) JSONMessages(ctx context.Context) iter.Seq2[jsonstream.Message, error] {
+	return func(yield func(jsonstream.Message, error) bool) {
+		for _, msg := range r.messages {
+			if err := ctx.Err(); err != nil {
+				yield(jsonstream.Message{}, err)
+				return
+			}
+			if !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Wait returns immediately, the pull is already done.
+func (r *pullResponse) Wait(ctx context.Context) error {
+	return ctx.Err()
+}
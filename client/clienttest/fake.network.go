@@ -0,0 +1,247 @@
+package clienttest
+
+import (
+	"context"
+	"maps"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+)
+
+// fakeNetwork is a network of the fake Docker API.
+type fakeNetwork struct {
+	network.Network
+
+	// endpoints are the containers connected to the network, by container ID.
+	endpoints map[string]network.EndpointResource
+
+	// nextIP is the last octet of the next IP address of the network.
+	nextIP byte
+}
+
+// predefined reports whether the network is one of the networks created by the daemon,
+// which cannot be removed.
+func (n *fakeNetwork) predefined() bool {
+	return n.Name == "bridge" || n.Name == "host" || n.Name == "none"
+}
+
+// inspect returns the inspect response of the network.
+func (n *fakeNetwork) inspect() network.Inspect {
+	nw := network.Inspect{Network: n.Network, Containers: maps.Clone(n.endpoints)}
+	nw.Labels = maps.Clone(n.Labels)
+	nw.Options = maps.Clone(n.Options)
+	return nw
+}
+
+// addNetwork adds a network with the given name and options, with a new subnet if it's a bridge network.
+func (f *FakeAPI) addNetwork(name string, options client.NetworkCreateOptions) *fakeNetwork {
+	driver := options.Driver
+	if driver == "" {
+		driver = "bridge"
+	}
+
+	nw := &fakeNetwork{
+		Network: network.Network{
+			Name:       name,
+			ID:         newID(),
+			Created:    time.Now(),
+			Scope:      "local",
+			Driver:     driver,
+			EnableIPv4: true,
+			Internal:   options.Internal,
+			Attachable: options.Attachable,
+			Options:    maps.Clone(options.Options),
+			Labels:     maps.Clone(options.Labels),
+		},
+		endpoints: make(map[string]network.EndpointResource),
+		nextIP:    2,
+	}
+	nw.IPAM.Driver = "default"
+
+	if options.IPAM != nil {
+		nw.IPAM = *options.IPAM
+	} else if driver == "bridge" {
+		subnet, gateway := f.nextSubnetLocked()
+		nw.IPAM.Config = []network.IPAMConfig{{Subnet: subnet, Gateway: gateway}}
+	}
+
+	f.networks[nw.ID] = nw
+	return nw
+}
+
+// findNetworkLocked returns the network with the given ID, name, or ID prefix, if any.
+func (f *FakeAPI) findNetworkLocked(ref string) *fakeNetwork {
+	if nw, ok := f.networks[ref]; ok {
+		return nw
+	}
+	for _, nw := range f.networks {
+		if nw.Name == ref {
+			return nw
+		}
+	}
+	for _, nw := range f.networks {
+		if strings.HasPrefix(nw.ID, ref) {
+			return nw
+		}
+	}
+	return nil
+}
+
+// connectLocked connects the container to the network, returning its endpoint settings.
+func (f *FakeAPI) connectLocked(nw *fakeNetwork, c *fakeContainer, settings *network.EndpointSettings) (*network.EndpointSettings, error) {
+	if _, ok := nw.endpoints[c.id]; ok {
+		return nil, errdefs.ErrConflict.WithMessage("endpoint with name " + c.name + " already exists in network " + nw.Name)
+	}
+
+	endpoint := &network.EndpointSettings{}
+	if settings != nil {
+		endpoint = settings.Copy()
+	}
+	endpoint.NetworkID = nw.ID
+	endpoint.EndpointID = newID()
+
+	resource := network.EndpointResource{Name: c.name, EndpointID: endpoint.EndpointID}
+	if len(nw.IPAM.Config) > 0 && nw.IPAM.Config[0].Subnet.Addr().Is4() {
+		subnet := nw.IPAM.Config[0].Subnet
+		ip := subnet.Addr().As4()
+		ip[3] = nw.nextIP
+		nw.nextIP++
+
+		endpoint.Gateway = nw.IPAM.Config[0].Gateway
+		endpoint.IPAddress = netip.AddrFrom4(ip)
+		endpoint.IPPrefixLen = subnet.Bits()
+		resource.IPv4Address = netip.PrefixFrom(endpoint.IPAddress, subnet.Bits())
+	}
+
+	nw.endpoints[c.id] = resource
+	return endpoint, nil
+}
+
+// NetworkCreate creates a network with the given name, which must be unique.
+func (f *FakeAPI) NetworkCreate(_ context.Context, name string, options client.NetworkCreateOptions) (client.NetworkCreateResult, error) {
+	if name == "" {
+		return client.NetworkCreateResult{}, errdefs.ErrInvalidArgument.WithMessage("network name is empty")
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for _, nw := range f.networks {
+		if nw.Name == name {
+			return client.NetworkCreateResult{}, errdefs.ErrConflict.WithMessage("network with name " + name + " already exists")
+		}
+	}
+
+	return client.NetworkCreateResult{ID: f.addNetwork(name, options).ID}, nil
+}
+
+// NetworkInspect returns the network with the given ID or name, and the containers connected to it.
+func (f *FakeAPI) NetworkInspect(_ context.Context, ref string, _ client.NetworkInspectOptions) (client.NetworkInspectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	nw := f.findNetworkLocked(ref)
+	if nw == nil {
+		return client.NetworkInspectResult{}, errdefs.ErrNotFound.WithMessage("network " + ref + " not found")
+	}
+
+	return client.NetworkInspectResult{Network: nw.inspect()}, nil
+}
+
+// NetworkList returns the networks matching the "id", "name", "label" and "driver" filters.
+func (f *FakeAPI) NetworkList(_ context.Context, options client.NetworkListOptions) (client.NetworkListResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var items []network.Summary
+	for _, nw := range f.networks {
+		if !matchFilters(options.Filters, map[string]matcher{
+			"id":     matchIDPrefix(nw.ID),
+			"name":   func(value string) bool { return strings.Contains(nw.Name, value) },
+			"label":  matchLabel(nw.Labels),
+			"driver": matchEqual(nw.Driver),
+		}) {
+			continue
+		}
+
+		items = append(items, network.Summary{Network: nw.inspect().Network})
+	}
+
+	return client.NetworkListResult{Items: items}, nil
+}
+
+// NetworkRemove removes the network with the given ID or name. The networks with connected
+// containers, and the predefined networks, cannot be removed.
+func (f *FakeAPI) NetworkRemove(_ context.Context, ref string, _ client.NetworkRemoveOptions) (client.NetworkRemoveResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	nw := f.findNetworkLocked(ref)
+	if nw == nil {
+		return client.NetworkRemoveResult{}, errdefs.ErrNotFound.WithMessage("network " + ref + " not found")
+	}
+
+	if nw.predefined() {
+		return client.NetworkRemoveResult{}, errdefs.ErrPermissionDenied.WithMessage(nw.Name + " is a pre-defined network and cannot be removed")
+	}
+
+	if len(nw.endpoints) > 0 {
+		return client.NetworkRemoveResult{}, errdefs.ErrConflict.WithMessage("error while removing network: network " + nw.Name + " id " + nw.ID + " has active endpoints")
+	}
+
+	delete(f.networks, nw.ID)
+	return client.NetworkRemoveResult{}, nil
+}
+
+// NetworkConnect connects the container to the network.
+func (f *FakeAPI) NetworkConnect(_ context.Context, ref string, options client.NetworkConnectOptions) (client.NetworkConnectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	nw := f.findNetworkLocked(ref)
+	if nw == nil {
+		return client.NetworkConnectResult{}, errdefs.ErrNotFound.WithMessage("network " + ref + " not found")
+	}
+
+	c := f.findContainerLocked(options.Container)
+	if c == nil {
+		return client.NetworkConnectResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("container", options.Container))
+	}
+
+	endpoint, err := f.connectLocked(nw, c, options.EndpointConfig)
+	if err != nil {
+		return client.NetworkConnectResult{}, err
+	}
+	c.networks[nw.Name] = endpoint
+
+	return client.NetworkConnectResult{}, nil
+}
+
+// NetworkDisconnect disconnects the container from the network.
+func (f *FakeAPI) NetworkDisconnect(_ context.Context, ref string, options client.NetworkDisconnectOptions) (client.NetworkDisconnectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	nw := f.findNetworkLocked(ref)
+	if nw == nil {
+		return client.NetworkDisconnectResult{}, errdefs.ErrNotFound.WithMessage("network " + ref + " not found")
+	}
+
+	c := f.findContainerLocked(options.Container)
+	if c == nil {
+		return client.NetworkDisconnectResult{}, errdefs.ErrNotFound.WithMessage(errNoSuch("container", options.Container))
+	}
+
+	if _, ok := nw.endpoints[c.id]; !ok {
+		return client.NetworkDisconnectResult{}, errdefs.ErrInvalidArgument.WithMessage("container " + c.id + " is not connected to network " + nw.Name)
+	}
+
+	delete(nw.endpoints, c.id)
+	delete(c.networks, nw.Name)
+
+	return client.NetworkDisconnectResult{}, nil
+}
@@ -0,0 +1,134 @@
+package clienttest
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+)
+
+// copyVolume returns a copy of the volume, not sharing its maps.
+func copyVolume(v *volume.Volume) volume.Volume {
+	cp := *v
+	cp.Labels = maps.Clone(v.Labels)
+	cp.Options = maps.Clone(v.Options)
+	return cp
+}
+
+// addVolumeLocked adds a volume with the given options, with a random name if it has none.
+func (f *FakeAPI) addVolumeLocked(options client.VolumeCreateOptions) *volume.Volume {
+	name := options.Name
+	if name == "" {
+		name = newID()
+	}
+
+	driver := options.Driver
+	if driver == "" {
+		driver = "local"
+	}
+
+	v := &volume.Volume{
+		Name:       name,
+		Driver:     driver,
+		Scope:      "local",
+		CreatedAt:  now(),
+		Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+		Labels:     maps.Clone(options.Labels),
+		Options:    maps.Clone(options.DriverOpts),
+	}
+	if v.Labels == nil {
+		v.Labels = map[string]string{}
+	}
+	f.volumes[name] = v
+
+	return v
+}
+
+// VolumeCreate creates a volume with the given name, or a random one if empty.
+// As in Docker, creating a volume that already exists returns the existing volume.
+func (f *FakeAPI) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if v, ok := f.volumes[options.Name]; ok {
+		return client.VolumeCreateResult{Volume: copyVolume(v)}, nil
+	}
+
+	v := f.addVolumeLocked(options)
+
+	return client.VolumeCreateResult{Volume: copyVolume(v)}, nil
+}
+
+// VolumeInspect returns the volume with the given name.
+func (f *FakeAPI) VolumeInspect(_ context.Context, name string, _ client.VolumeInspectOptions) (client.VolumeInspectResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	v, ok := f.volumes[name]
+	if !ok {
+		return client.VolumeInspectResult{}, errdefs.ErrNotFound.WithMessage("get " + name + ": no such volume")
+	}
+
+	return client.VolumeInspectResult{Volume: copyVolume(v)}, nil
+}
+
+// VolumeList returns the volumes matching the "name", "label", "driver" and "dangling" filters.
+func (f *FakeAPI) VolumeList(_ context.Context, options client.VolumeListOptions) (client.VolumeListResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var items []volume.Volume
+	for _, v := range f.volumes {
+		dangling := !f.volumeInUseLocked(v.Name)
+		if !matchFilters(options.Filters, map[string]matcher{
+			"name":   func(value string) bool { return strings.Contains(v.Name, value) },
+			"label":  matchLabel(v.Labels),
+			"driver": matchEqual(v.Driver),
+			"dangling": func(value string) bool {
+				return (value == "true" || value == "1") == dangling
+			},
+		}) {
+			continue
+		}
+
+		items = append(items, copyVolume(v))
+	}
+
+	return client.VolumeListResult{Items: items}, nil
+}
+
+// VolumeRemove removes the volume with the given name. Volumes used by containers
+// cannot be removed, even if forced.
+func (f *FakeAPI) VolumeRemove(_ context.Context, name string, options client.VolumeRemoveOptions) (client.VolumeRemoveResult, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if _, ok := f.volumes[name]; !ok {
+		if options.Force {
+			return client.VolumeRemoveResult{}, nil
+		}
+		return client.VolumeRemoveResult{}, errdefs.ErrNotFound.WithMessage("get " + name + ": no such volume")
+	}
+
+	if f.volumeInUseLocked(name) {
+		return client.VolumeRemoveResult{}, errdefs.ErrConflict.WithMessage("remove " + name + ": volume is in use")
+	}
+
+	delete(f.volumes, name)
+	return client.VolumeRemoveResult{}, nil
+}
+
+// volumeInUseLocked reports whether the volume is mounted by any container.
+func (f *FakeAPI) volumeInUseLocked(name string) bool {
+	for _, c := range f.containers {
+		for _, m := range c.mounts {
+			if m.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
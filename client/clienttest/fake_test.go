@@ -0,0 +1,297 @@
+package clienttest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	sdkclient "github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+func newFakeClient(t *testing.T) (*clienttest.FakeAPI, sdkclient.SDKClient) {
+	t.Helper()
+
+	fake := clienttest.NewFakeAPI()
+	cli, err := clienttest.NewFakeClient(context.Background(), fake)
+	require.NoError(t, err)
+
+	return fake, cli
+}
+
+func inspectState(t *testing.T, cli sdkclient.SDKClient, ref string) *container.State {
+	t.Helper()
+
+	inspect, err := cli.ContainerInspect(context.Background(), ref, client.ContainerInspectOptions{})
+	require.NoError(t, err)
+
+	return inspect.Container.State
+}
+
+func TestFakeAPI_containerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, cli := newFakeClient(t)
+
+	t.Run("image-not-found", func(t *testing.T) {
+		_, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{Config: &container.Config{Image: "nginx:alpine"}})
+		require.True(t, errdefs.IsNotFound(err))
+	})
+
+	pull, err := cli.ImagePull(ctx, "nginx:alpine", client.ImagePullOptions{})
+	require.NoError(t, err)
+	require.NoError(t, pull.Wait(ctx))
+
+	created, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Name: "web",
+		Config: &container.Config{
+			Image:        "nginx:alpine",
+			ExposedPorts: network.PortSet{network.MustParsePort("80/tcp"): {}},
+		},
+		HostConfig: &container.HostConfig{PublishAllPorts: true},
+	})
+	require.NoError(t, err)
+
+	t.Run("sdk-labels", func(t *testing.T) {
+		inspect, err := cli.ContainerInspect(ctx, created.ID, client.ContainerInspectOptions{})
+		require.NoError(t, err)
+		require.Equal(t, cli.SessionID(), inspect.Container.Config.Labels[sdkclient.LabelSessionID])
+		require.Equal(t, container.StateCreated, inspect.Container.State.Status)
+	})
+
+	t.Run("name-conflict", func(t *testing.T) {
+		_, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{Name: "web", Config: &container.Config{Image: "nginx:alpine"}})
+		require.True(t, errdefs.IsConflict(err))
+	})
+
+	_, err = cli.ContainerStart(ctx, "web", client.ContainerStartOptions{})
+	require.NoError(t, err)
+
+	t.Run("running", func(t *testing.T) {
+		inspect, err := cli.ContainerInspect(ctx, "web", client.ContainerInspectOptions{})
+		require.NoError(t, err)
+		require.True(t, inspect.Container.State.Running)
+		require.Equal(t, "32768", inspect.Container.NetworkSettings.Ports[network.MustParsePort("80/tcp")][0].HostPort)
+		require.Equal(t, "172.17.0.2", inspect.Container.NetworkSettings.Networks["bridge"].IPAddress.String())
+
+		found, err := cli.FindContainerByName(ctx, "web")
+		require.NoError(t, err)
+		require.Equal(t, created.ID, found.ID)
+		require.Equal(t, uint16(32768), found.Ports[0].PublicPort)
+	})
+
+	t.Run("healthcheck", func(t *testing.T) {
+		require.Nil(t, inspectState(t, cli, "web").Health)
+
+		_, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+			Name: "healthy",
+			Config: &container.Config{
+				Image:       "nginx:alpine",
+				Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = cli.ContainerStart(ctx, "healthy", client.ContainerStartOptions{})
+		require.NoError(t, err)
+		require.Equal(t, container.Healthy, inspectState(t, cli, "healthy").Health.Status)
+
+		_, err = cli.ContainerRemove(ctx, "healthy", client.ContainerRemoveOptions{Force: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("remove-running", func(t *testing.T) {
+		_, err := cli.ContainerRemove(ctx, "web", client.ContainerRemoveOptions{})
+		require.True(t, errdefs.IsConflict(err))
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		_, err := cli.ContainerPause(ctx, "web", client.ContainerPauseOptions{})
+		require.NoError(t, err)
+
+		_, err = cli.ContainerStart(ctx, "web", client.ContainerStartOptions{})
+		require.True(t, errdefs.IsConflict(err))
+
+		_, err = cli.ContainerUnpause(ctx, "web", client.ContainerUnpauseOptions{})
+		require.NoError(t, err)
+	})
+
+	_, err = cli.ContainerStop(ctx, "web", client.ContainerStopOptions{})
+	require.NoError(t, err)
+
+	t.Run("stopped", func(t *testing.T) {
+		list, err := cli.ContainerList(ctx, client.ContainerListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+
+		list, err = cli.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: make(client.Filters).Add("status", "exited")})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		require.Equal(t, container.StateExited, list.Items[0].State)
+		require.Empty(t, list.Items[0].Ports)
+	})
+
+	t.Run("image-in-use", func(t *testing.T) {
+		_, err := cli.ImageRemove(ctx, "nginx:alpine", client.ImageRemoveOptions{})
+		require.True(t, errdefs.IsConflict(err))
+	})
+
+	_, err = cli.ContainerRemove(ctx, "web", client.ContainerRemoveOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.ContainerInspect(ctx, "web", client.ContainerInspectOptions{})
+	require.True(t, errdefs.IsNotFound(err))
+
+	removed, err := cli.ImageRemove(ctx, "docker.io/library/nginx:alpine", client.ImageRemoveOptions{})
+	require.NoError(t, err)
+	require.Len(t, removed.Items, 2)
+}
+
+func TestFakeAPI_Exit(t *testing.T) {
+	ctx := context.Background()
+	fake, cli := newFakeClient(t)
+
+	_, err := fake.AddImage("alpine")
+	require.NoError(t, err)
+
+	run := func(t *testing.T, autoRemove bool) string {
+		t.Helper()
+
+		created, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+			Config:     &container.Config{Image: "alpine:latest", Cmd: []string{"sleep", "infinity"}},
+			HostConfig: &container.HostConfig{AutoRemove: autoRemove},
+		})
+		require.NoError(t, err)
+
+		_, err = cli.ContainerStart(ctx, created.ID, client.ContainerStartOptions{})
+		require.NoError(t, err)
+
+		return created.ID
+	}
+
+	t.Run("wait", func(t *testing.T) {
+		id := run(t, false)
+
+		wait := cli.ContainerWait(ctx, id, client.ContainerWaitOptions{})
+		require.NoError(t, fake.Exit(id, 3))
+
+		select {
+		case resp := <-wait.Result:
+			require.Equal(t, int64(3), resp.StatusCode)
+		case err := <-wait.Error:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the container to exit")
+		}
+
+		require.True(t, errdefs.IsConflict(fake.Exit(id, 0)))
+	})
+
+	t.Run("auto-remove", func(t *testing.T) {
+		id := run(t, true)
+
+		wait := cli.ContainerWait(ctx, id, client.ContainerWaitOptions{Condition: container.WaitConditionRemoved})
+		require.NoError(t, fake.Exit(id, 0))
+
+		select {
+		case <-wait.Result:
+		case err := <-wait.Error:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the container to be removed")
+		}
+
+		_, err := cli.FindContainerByID(ctx, id)
+		require.True(t, errdefs.IsNotFound(err))
+	})
+}
+
+func TestFakeAPI_networks(t *testing.T) {
+	ctx := context.Background()
+	fake, cli := newFakeClient(t)
+
+	_, err := fake.AddImage("alpine:latest")
+	require.NoError(t, err)
+
+	nw, err := cli.NetworkCreate(ctx, "backend", client.NetworkCreateOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.NetworkCreate(ctx, "backend", client.NetworkCreateOptions{})
+	require.True(t, errdefs.IsConflict(err))
+
+	created, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config:     &container.Config{Image: "alpine"},
+		HostConfig: &container.HostConfig{NetworkMode: "backend"},
+	})
+	require.NoError(t, err)
+
+	inspect, err := cli.NetworkInspect(ctx, "backend", client.NetworkInspectOptions{})
+	require.NoError(t, err)
+	require.Equal(t, nw.ID, inspect.Network.ID)
+	require.Contains(t, inspect.Network.Containers, created.ID)
+	require.Equal(t, "172.18.0.2/16", inspect.Network.Containers[created.ID].IPv4Address.String())
+	require.Equal(t, cli.SessionID(), inspect.Network.Labels[sdkclient.LabelSessionID])
+
+	_, err = cli.NetworkRemove(ctx, "backend", client.NetworkRemoveOptions{})
+	require.True(t, errdefs.IsConflict(err))
+
+	_, err = cli.NetworkDisconnect(ctx, "backend", client.NetworkDisconnectOptions{Container: created.ID})
+	require.NoError(t, err)
+
+	_, err = cli.NetworkRemove(ctx, nw.ID, client.NetworkRemoveOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.NetworkRemove(ctx, "bridge", client.NetworkRemoveOptions{})
+	require.True(t, errdefs.IsPermissionDenied(err))
+
+	list, err := cli.NetworkList(ctx, client.NetworkListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+}
+
+func TestFakeAPI_volumes(t *testing.T) {
+	ctx := context.Background()
+	fake, cli := newFakeClient(t)
+
+	_, err := fake.AddImage("alpine:latest")
+	require.NoError(t, err)
+
+	_, err = cli.VolumeCreate(ctx, client.VolumeCreateOptions{Name: "data", Labels: map[string]string{"app": "test"}})
+	require.NoError(t, err)
+
+	created, err := cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config: &container.Config{Image: "alpine"},
+		HostConfig: &container.HostConfig{
+			Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: "data", Target: "/data"}},
+			Binds:  []string{"cache:/cache:ro"},
+		},
+	})
+	require.NoError(t, err)
+
+	list, err := cli.VolumeList(ctx, client.VolumeListOptions{Filters: make(client.Filters).Add("label", "app=test")})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "data", list.Items[0].Name)
+
+	// the volume of the bind is created with the container
+	_, err = cli.VolumeInspect(ctx, "cache", client.VolumeInspectOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.VolumeRemove(ctx, "data", client.VolumeRemoveOptions{Force: true})
+	require.True(t, errdefs.IsConflict(err))
+
+	_, err = cli.ContainerRemove(ctx, created.ID, client.ContainerRemoveOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.VolumeRemove(ctx, "data", client.VolumeRemoveOptions{})
+	require.NoError(t, err)
+
+	_, err = cli.VolumeInspect(ctx, "data", client.VolumeInspectOptions{})
+	require.True(t, errdefs.IsNotFound(err))
+}
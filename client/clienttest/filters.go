@@ -0,0 +1,74 @@
+package clienttest
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
+
+// matcher reports whether a resource matches a value of a filter term.
+type matcher func(value string) bool
+
+// matchFilters reports whether a resource matches the given filters, with the matchers of
+// the resource by filter term: a term is satisfied if any of its values matches, and all
+// the terms must be satisfied. The terms without a matcher are ignored.
+func matchFilters(filters client.Filters, matchers map[string]matcher) bool {
+	for term, values := range filters {
+		match, ok := matchers[term]
+		if !ok {
+			continue
+		}
+
+		found := false
+		for value, enabled := range values {
+			if enabled && match(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLabel matches the "label" filter values, "key" or "key=value", against the given labels.
+func matchLabel(labels map[string]string) matcher {
+	return func(value string) bool {
+		key, want, hasValue := strings.Cut(value, "=")
+		got, ok := labels[key]
+		return ok && (!hasValue || got == want)
+	}
+}
+
+// matchIDPrefix matches the "id" filter values as prefixes of the given ID.
+func matchIDPrefix(id string) matcher {
+	return func(value string) bool {
+		return strings.HasPrefix(id, value)
+	}
+}
+
+// matchRegexp matches the filter values as regular expressions against any of the given names.
+func matchRegexp(names ...string) matcher {
+	return func(value string) bool {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return false
+		}
+		for _, name := range names {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchEqual matches the filter values that are equal to the given value.
+func matchEqual(s string) matcher {
+	return func(value string) bool {
+		return value == s
+	}
+}
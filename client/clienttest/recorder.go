@@ -0,0 +1,296 @@
+package clienttest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/docker/go-connections/sockets"
+	"github.com/moby/moby/client"
+
+	sdkclient "github.com/docker/go-sdk/client"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// EnvRecord is the environment variable that makes [ModeFromEnv] return [ModeRecord],
+// when set to a true value, e.g. "true" or "1".
+const EnvRecord = "DOCKER_SDK_RECORD"
+
+// ErrNoInteraction is returned by a [Recorder] replaying a request that was not recorded.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// Mode is the mode of a [Recorder].
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of the golden file, without connecting to a docker daemon.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the docker daemon, recording the interactions to save them
+	// to the golden file.
+	ModeRecord
+)
+
+// ModeFromEnv returns [ModeRecord] if the [EnvRecord] environment variable is set to a true value,
+// or [ModeReplay] otherwise, so that the golden files can be updated by running the tests with it.
+func ModeFromEnv() Mode {
+	if record, _ := strconv.ParseBool(os.Getenv(EnvRecord)); record {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Interaction is an HTTP exchange with the docker daemon, as stored in a golden file.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request of an [Interaction].
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   Body   `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response of an [Interaction].
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is the body of a recorded request or response. It's stored as a string in the golden files,
+// unless it's not valid UTF-8, e.g. the multiplexed logs of a container, which is stored base64-encoded.
+type Body []byte
+
+// MarshalJSON encodes the body as a string, or as a base64-encoded object if it's not valid UTF-8.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes the body encoded by [Body.MarshalJSON].
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("unmarshal body: %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded["base64"])
+	if err != nil {
+		return fmt.Errorf("decode body: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// goldenFile is the content of a golden file.
+type goldenFile struct {
+	// Host is the docker host the interactions were recorded with.
+	Host         string        `json:"host"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an [http.RoundTripper] that records the HTTP exchanges with a docker daemon to a golden file,
+// and replays them deterministically, without a daemon. The requests are matched to the recorded ones by
+// method and path, in the order they were recorded; their query and body are not compared, as they can
+// contain random values, like the session ID of the client.
+//
+// Hijacked connections, used to attach to containers and to run commands in them, and endless streams,
+// like the followed logs or the events, are not supported.
+//
+// Recorder is safe for concurrent use by multiple goroutines.
+type Recorder struct {
+	mode Mode
+	path string
+
+	// transport is the transport connecting to the docker daemon, when recording.
+	transport http.RoundTripper
+
+	mtx      sync.Mutex
+	golden   goldenFile
+	replayed []bool
+}
+
+// NewRecorder returns a recorder for the golden file at the given path. When recording, the requests are sent
+// to the current docker host, and the interactions are written to the golden file by [Recorder.Save]. When
+// replaying, the golden file is read, and must exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{mode: mode, path: path}
+
+	switch mode {
+	case ModeRecord:
+		host, err := dockercontext.CurrentDockerHost()
+		if err != nil {
+			return nil, fmt.Errorf("current docker host: %w", err)
+		}
+
+		hostURL, err := client.ParseHostURL(host)
+		if err != nil {
+			return nil, fmt.Errorf("parse docker host: %w", err)
+		}
+
+		transport := &http.Transport{}
+		if err := sockets.ConfigureTransport(transport, hostURL.Scheme, hostURL.Host); err != nil {
+			return nil, fmt.Errorf("configure transport: %w", err)
+		}
+
+		r.transport = transport
+		r.golden.Host = host
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read golden file: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &r.golden); err != nil {
+			return nil, fmt.Errorf("unmarshal golden file: %w", err)
+		}
+		r.replayed = make([]bool, len(r.golden.Interactions))
+	default:
+		return nil, fmt.Errorf("invalid mode: %d", mode)
+	}
+
+	return r, nil
+}
+
+// ClientOptions returns the options to create an SDK client sending its requests through the recorder,
+// to the docker host of the golden file. The removal of the resources of dead sessions is disabled,
+// as it would send requests in the background.
+func (r *Recorder) ClientOptions() []sdkclient.ClientOption {
+	return []sdkclient.ClientOption{
+		sdkclient.WithDockerHost(r.golden.Host),
+		sdkclient.WithHTTPTransport(r),
+		sdkclient.WithoutReaper(),
+	}
+}
+
+// Interactions returns the interactions recorded, or read from the golden file.
+func (r *Recorder) Interactions() []Interaction {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]Interaction(nil), r.golden.Interactions...)
+}
+
+// RoundTrip sends the request to the docker daemon, recording the interaction, or replays
+// the first recorded interaction matching the request that was not replayed yet.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Body:   reqBody,
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mtx.Lock()
+	r.golden.Interactions = append(r.golden.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       respBody,
+		},
+	})
+	r.mtx.Unlock()
+
+	return resp, nil
+}
+
+// replay returns the response of the first interaction matching the request that was not replayed yet.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for i, interaction := range r.golden.Interactions {
+		if r.replayed[i] || interaction.Request.Method != recorded.Method || interaction.Request.Path != recorded.Path {
+			continue
+		}
+		r.replayed[i] = true
+
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Response.StatusCode) + " " + http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.Path)
+}
+
+// Save writes the recorded interactions to the golden file, creating its directory if needed.
+// It's a no-op when replaying.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mtx.Lock()
+	data, err := json.MarshalIndent(r.golden, "", "  ")
+	r.mtx.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal golden file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("create golden file directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write golden file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package clienttest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	sdkclient "github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+// newDaemon returns a server answering the ping and container list requests of the Docker API.
+func newDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", clienttest.FakeAPIVersion)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/containers/json"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]container.Summary{{ID: "recorded", Names: []string{"/recorded"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	golden := filepath.Join(t.TempDir(), "testdata", "list.json")
+
+	srv := newDaemon(t)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("DOCKER_HOST", "tcp://"+srv.Listener.Addr().String())
+
+	t.Run("record", func(t *testing.T) {
+		rec, err := clienttest.NewRecorder(golden, clienttest.ModeRecord)
+		require.NoError(t, err)
+
+		cli, err := sdkclient.New(ctx, rec.ClientOptions()...)
+		require.NoError(t, err)
+
+		found, err := cli.FindContainerByName(ctx, "recorded")
+		require.NoError(t, err)
+		require.Equal(t, "recorded", found.ID)
+
+		require.NoError(t, rec.Save())
+		require.NotEmpty(t, rec.Interactions())
+	})
+
+	srv.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		rec, err := clienttest.NewRecorder(golden, clienttest.ModeReplay)
+		require.NoError(t, err)
+
+		cli, err := sdkclient.New(ctx, rec.ClientOptions()...)
+		require.NoError(t, err)
+
+		found, err := cli.FindContainerByName(ctx, "recorded")
+		require.NoError(t, err)
+		require.Equal(t, "recorded", found.ID)
+
+		// the interaction was already replayed
+		_, err = cli.ContainerList(ctx, client.ContainerListOptions{})
+		require.ErrorIs(t, err, clienttest.ErrNoInteraction)
+	})
+
+	t.Run("missing-golden-file", func(t *testing.T) {
+		_, err := clienttest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), clienttest.ModeReplay)
+		require.Error(t, err)
+	})
+}
+
+func TestBody(t *testing.T) {
+	for _, body := range []clienttest.Body{clienttest.Body(`{"Id":"abc"}`), {0x01, 0x00, 0xff, 0xfe}} {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		var decoded clienttest.Body
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Equal(t, body, decoded)
+	}
+}
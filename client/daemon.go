@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/netip"
 	"net/url"
 	"os"
@@ -10,12 +11,13 @@ import (
 	"github.com/moby/moby/client"
 )
 
-// dockerEnvFile is the file that is created when running inside a container.
-// It's a variable to allow testing.
-var dockerEnvFile = "/.dockerenv"
+// containerEnvFiles are the files that are created when running inside a container:
+// by Docker, and by Podman. It's a variable to allow testing.
+var containerEnvFiles = []string{"/.dockerenv", "/run/.containerenv"}
 
-// DaemonHostWithContext gets the host or ip of the Docker daemon where ports are exposed on
-// Warning: this is based on your Docker host setting. Will fail if using an SSH tunnel
+// DaemonHostWithContext gets the host or ip of the Docker daemon where ports are exposed on.
+// This is based on your Docker host setting: for ssh:// hosts, it's the host the client
+// connects to through SSH.
 func (c *sdkClient) DaemonHostWithContext(ctx context.Context) (string, error) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
@@ -24,8 +26,13 @@ func (c *sdkClient) DaemonHostWithContext(ctx context.Context) (string, error) {
 }
 
 func (c *sdkClient) daemonHostLocked(ctx context.Context) (string, error) {
-	// infer from Docker host
-	daemonURL, err := url.Parse(c.DaemonHost())
+	// infer from Docker host, which is a placeholder when dialing it through SSH
+	dockerHost := c.DaemonHost()
+	if c.sshHost != "" {
+		dockerHost = c.sshHost
+	}
+
+	daemonURL, err := url.Parse(dockerHost)
 	if err != nil {
 		return "", err
 	}
@@ -33,11 +40,11 @@ func (c *sdkClient) daemonHostLocked(ctx context.Context) (string, error) {
 	var host string
 
 	switch daemonURL.Scheme {
-	case "http", "https", "tcp":
+	case "http", "https", "tcp", "ssh":
 		host = daemonURL.Hostname()
 	case "unix", "npipe":
-		if inAContainer(dockerEnvFile) {
-			ip, err := c.getGatewayIP(ctx, "bridge")
+		if inAContainer(containerEnvFiles...) {
+			ip, err := c.getGatewayIP(ctx, "bridge", podmanDefaultNetwork)
 			if err != nil {
 				host = "localhost"
 			} else {
@@ -53,31 +60,36 @@ func (c *sdkClient) daemonHostLocked(ctx context.Context) (string, error) {
 	return host, nil
 }
 
-func (c *sdkClient) getGatewayIP(ctx context.Context, defaultNetwork string) (netip.Addr, error) {
-	nw, err := c.NetworkInspect(ctx, defaultNetwork, client.NetworkInspectOptions{})
-	if err != nil {
-		return netip.Addr{}, err
-	}
+// getGatewayIP returns the gateway IP of the first of the given default networks that exists
+// and has a gateway, e.g. the "bridge" network of Docker, or the "podman" network of Podman.
+func (c *sdkClient) getGatewayIP(ctx context.Context, defaultNetworks ...string) (netip.Addr, error) {
+	var errs []error
+	for _, defaultNetwork := range defaultNetworks {
+		nw, err := c.NetworkInspect(ctx, defaultNetwork, client.NetworkInspectOptions{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
-	var ip netip.Addr
-	for _, cfg := range nw.Network.IPAM.Config {
-		if cfg.Gateway.IsValid() {
-			ip = cfg.Gateway
-			break
+		for _, cfg := range nw.Network.IPAM.Config {
+			if cfg.Gateway.IsValid() {
+				return cfg.Gateway, nil
+			}
 		}
-	}
-	if !ip.IsValid() {
-		return netip.Addr{}, errors.New("failed to get gateway IP from network settings")
+		errs = append(errs, fmt.Errorf("network %s: failed to get gateway IP from network settings", defaultNetwork))
 	}
 
-	return ip, nil
+	return netip.Addr{}, errors.Join(errs...)
 }
 
-// InAContainer returns true if the code is running inside a container
+// InAContainer returns true if the code is running inside a container, i.e. any of the given files exists.
 // See https://github.com/docker/docker/blob/a9fa38b1edf30b23cae3eade0be48b3d4b1de14b/daemon/initlayer/setup_unix.go#L25
-func inAContainer(path string) bool {
-	if _, err := os.Stat(path); err == nil {
-		return true
+// and https://docs.podman.io/en/latest/markdown/podman-run.1.html for the /run/.containerenv file.
+func inAContainer(paths ...string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
 	}
 	return false
 }
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+const (
+	// eventsReconnectDelay is the time to wait before reconnecting to the events stream.
+	eventsReconnectDelay = time.Second
+
+	// eventsMaxReconnects is the maximum number of consecutive reconnections to the
+	// events stream without receiving any event.
+	eventsMaxReconnects = 5
+)
+
+// Event is a Docker event of a container, network, volume or image.
+type Event struct {
+	// Type is the type of the resource that generated the event.
+	Type events.Type
+
+	// Action is the action of the event, e.g. "start" or "die".
+	Action events.Action
+
+	// ID is the ID of the resource that generated the event.
+	// For volumes, it's the name of the volume.
+	ID string
+
+	// Attributes are the attributes of the resource. For containers and images,
+	// they include their labels.
+	Attributes map[string]string
+
+	// Time is the time the event was generated at.
+	Time time.Time
+
+	// Message is the raw event, as returned by the Docker daemon.
+	Message events.Message
+}
+
+// newEvent returns the event for the given message.
+func newEvent(msg events.Message) Event {
+	t := time.Unix(0, msg.TimeNano)
+	if msg.TimeNano == 0 {
+		t = time.Unix(msg.Time, 0)
+	}
+
+	return Event{
+		Type:       msg.Type,
+		Action:     msg.Action,
+		ID:         msg.Actor.ID,
+		Attributes: msg.Actor.Attributes,
+		Time:       t,
+		Message:    msg,
+	}
+}
+
+// Name returns the name of the resource that generated the event, if any.
+func (e Event) Name() string {
+	return e.Attributes["name"]
+}
+
+// IsOOM returns true if the event reports that a container was killed
+// because it ran out of memory.
+func (e Event) IsOOM() bool {
+	return e.Type == events.ContainerEventType && e.Action == events.ActionOOM
+}
+
+// ExitCode returns the exit code of the container of a "die" event.
+// It returns false if the event is not a "die" event of a container.
+func (e Event) ExitCode() (int, bool) {
+	if e.Type != events.ContainerEventType || e.Action != events.ActionDie {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(e.Attributes["exitCode"])
+	if err != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+// eventsOptions are the options to subscribe to the Docker events.
+type eventsOptions struct {
+	since   time.Time
+	until   time.Time
+	filters client.Filters
+}
+
+// EventsOption is a function that configures the subscription to the Docker events.
+type EventsOption func(*eventsOptions) error
+
+// EventsSince returns the events generated since the given time, including the past ones.
+// By default, only the events generated after subscribing are returned.
+func EventsSince(since time.Time) EventsOption {
+	return func(o *eventsOptions) error {
+		o.since = since
+		return nil
+	}
+}
+
+// EventsUntil stops the subscription once the events generated until the given time are returned.
+func EventsUntil(until time.Time) EventsOption {
+	return func(o *eventsOptions) error {
+		o.until = until
+		return nil
+	}
+}
+
+// EventsTypes returns only the events of the given resource types,
+// e.g. [events.ContainerEventType].
+func EventsTypes(types ...events.Type) EventsOption {
+	return func(o *eventsOptions) error {
+		for _, t := range types {
+			o.filters.Add("type", string(t))
+		}
+		return nil
+	}
+}
+
+// EventsActions returns only the events with the given actions, e.g. [events.ActionDie].
+func EventsActions(actions ...events.Action) EventsOption {
+	return func(o *eventsOptions) error {
+		for _, a := range actions {
+			o.filters.Add("event", string(a))
+		}
+		return nil
+	}
+}
+
+// EventsResource returns only the events of the resource of the given type, identified
+// by its ID or name. The type must be a container, network, volume or image.
+func EventsResource(typ events.Type, id string) EventsOption {
+	return func(o *eventsOptions) error {
+		switch typ {
+		case events.ContainerEventType, events.NetworkEventType, events.VolumeEventType, events.ImageEventType:
+		default:
+			return fmt.Errorf("unsupported resource type: %q", typ)
+		}
+
+		if id == "" {
+			return errors.New("resource id is empty")
+		}
+
+		o.filters.Add("type", string(typ)).Add(string(typ), id)
+		return nil
+	}
+}
+
+// EventsLabel returns only the events of the resources with the given label.
+// The daemon only matches the labels it includes in the attributes of the events,
+// like the ones of containers and images.
+func EventsLabel(key, value string) EventsOption {
+	return func(o *eventsOptions) error {
+		o.filters.Add("label", key+"="+value)
+		return nil
+	}
+}
+
+// EventsSDKLabels returns only the events of the resources created by the SDK.
+func EventsSDKLabels() EventsOption {
+	return EventsLabel(LabelBase, "true")
+}
+
+// EventsSession returns only the events of the resources created by the client
+// with the given session ID. See [SDKClient.SessionID].
+func EventsSession(sessionID string) EventsOption {
+	return EventsLabel(LabelSessionID, sessionID)
+}
+
+// SubscribeEvents returns the Docker events matching the given options, until the context
+// is done, the until time is reached, or an error occurs, which is yielded last.
+// If the events stream fails with a transient error, it's reopened from the time of the
+// last event received, so no event is lost.
+func (c *sdkClient) SubscribeEvents(ctx context.Context, opts ...EventsOption) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		eventsOpts := &eventsOptions{
+			filters: make(client.Filters),
+		}
+		for _, opt := range opts {
+			if err := opt(eventsOpts); err != nil {
+				yield(Event{}, fmt.Errorf("apply option: %w", err))
+				return
+			}
+		}
+
+		since := eventsOpts.since
+		if since.IsZero() {
+			since = time.Now()
+		}
+
+		var last events.Message
+		reconnects := 0
+		for {
+			listOpts := client.EventsListOptions{
+				Since:   formatEventsTime(since),
+				Filters: eventsOpts.filters,
+			}
+			if !eventsOpts.until.IsZero() {
+				listOpts.Until = formatEventsTime(eventsOpts.until)
+			}
+
+			streamCtx, cancel := context.WithCancel(ctx)
+			result := c.APIClient.Events(streamCtx, listOpts)
+
+			err := func() error {
+				defer cancel()
+
+				for {
+					select {
+					case msg := <-result.Messages:
+						// skip the events already returned before reconnecting,
+						// as since includes the events generated at that time.
+						if msg.TimeNano < last.TimeNano || sameEvent(msg, last) {
+							continue
+						}
+
+						reconnects = 0
+						last = msg
+
+						if !yield(newEvent(msg), nil) {
+							return errStopEvents
+						}
+					case err := <-result.Err:
+						if err == nil {
+							return io.EOF
+						}
+						return err
+					}
+				}
+			}()
+
+			switch {
+			case errors.Is(err, errStopEvents):
+				return
+			case errors.Is(err, io.EOF) && !eventsOpts.until.IsZero():
+				// all the events until the given time were returned
+				return
+			case ctx.Err() != nil:
+				yield(Event{}, ctx.Err())
+				return
+			case IsPermanentClientError(err) || reconnects >= eventsMaxReconnects:
+				yield(Event{}, fmt.Errorf("events: %w", err))
+				return
+			}
+
+			reconnects++
+			if last.TimeNano != 0 {
+				since = time.Unix(0, last.TimeNano)
+			}
+
+			c.log.Debug("Reconnecting to the events stream", "since", since, "error", err)
+
+			select {
+			case <-ctx.Done():
+				yield(Event{}, ctx.Err())
+				return
+			case <-time.After(eventsReconnectDelay):
+			}
+		}
+	}
+}
+
+// errStopEvents is used to stop reading the events stream when the consumer stops iterating.
+var errStopEvents = errors.New("stop events")
+
+// sameEvent returns true if both messages are the same event.
+func sameEvent(a, b events.Message) bool {
+	return a.TimeNano == b.TimeNano && a.Type == b.Type && a.Action == b.Action && a.Actor.ID == b.Actor.ID
+}
+
+// formatEventsTime formats the given time as a Unix timestamp with nanoseconds,
+// as expected by the since and until options of the events stream.
+func formatEventsTime(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// eventsMockCli is a mock implementation of client.APIClient, which returns the
+// given streams of events, one for each call to Events, and records the options.
+type eventsMockCli struct {
+	mockCli
+
+	streams []eventsStream
+	options []client.EventsListOptions
+}
+
+// eventsStream is a stream of events, which ends with the given error.
+type eventsStream struct {
+	messages []events.Message
+	err      error
+}
+
+func (m *eventsMockCli) Events(ctx context.Context, options client.EventsListOptions) client.EventsResult {
+	stream := m.streams[len(m.options)]
+	m.options = append(m.options, options)
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		for _, msg := range stream.messages {
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if stream.err != nil {
+			errs <- stream.err
+			return
+		}
+
+		<-ctx.Done()
+		errs <- ctx.Err()
+	}()
+
+	return client.EventsResult{Messages: messages, Err: errs}
+}
+
+func containerEvent(id string, action events.Action, timeNano int64, attrs map[string]string) events.Message {
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   action,
+		Actor:    events.Actor{ID: id, Attributes: attrs},
+		TimeNano: timeNano,
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	t.Run("reconnect", func(t *testing.T) {
+		start := containerEvent("abc", events.ActionStart, 1_000, nil)
+		oom := containerEvent("abc", events.ActionOOM, 2_000, nil)
+		die := containerEvent("abc", events.ActionDie, 3_000, map[string]string{"exitCode": "137", "name": "db"})
+
+		m := &eventsMockCli{
+			streams: []eventsStream{
+				{messages: []events.Message{start, oom}, err: io.ErrUnexpectedEOF},
+				// the events at the time of the last event are returned again
+				{messages: []events.Message{oom, die}, err: errdefs.ErrInvalidArgument.WithMessage("bad filter")},
+			},
+		}
+		sdk := newMockClient(t, m)
+
+		var received []Event
+		var lastErr error
+		for event, err := range sdk.SubscribeEvents(context.Background(), EventsSession("session"), EventsResource(events.ContainerEventType, "abc")) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			received = append(received, event)
+		}
+
+		require.Len(t, received, 3)
+		require.Equal(t, events.ActionStart, received[0].Action)
+		require.True(t, received[1].IsOOM())
+		require.Equal(t, "db", received[2].Name())
+		require.Equal(t, time.Unix(0, 3_000), received[2].Time)
+
+		code, ok := received[2].ExitCode()
+		require.True(t, ok)
+		require.Equal(t, 137, code)
+
+		_, ok = received[0].ExitCode()
+		require.False(t, ok)
+
+		// the permanent error ends the subscription
+		require.ErrorIs(t, lastErr, errdefs.ErrInvalidArgument)
+
+		require.Len(t, m.options, 2)
+		require.Equal(t, formatEventsTime(time.Unix(0, 2_000)), m.options[1].Since)
+		require.True(t, m.options[0].Filters["label"][LabelSessionID+"=session"])
+		require.True(t, m.options[0].Filters["container"]["abc"])
+		require.True(t, m.options[0].Filters["type"]["container"])
+	})
+
+	t.Run("until", func(t *testing.T) {
+		m := &eventsMockCli{
+			streams: []eventsStream{
+				{messages: []events.Message{containerEvent("abc", events.ActionStart, 1_000, nil)}, err: io.EOF},
+			},
+		}
+		sdk := newMockClient(t, m)
+
+		var received int
+		for _, err := range sdk.SubscribeEvents(context.Background(), EventsSince(time.Unix(0, 0)), EventsUntil(time.Unix(1, 0))) {
+			require.NoError(t, err)
+			received++
+		}
+
+		require.Equal(t, 1, received)
+		require.Equal(t, "0.000000000", m.options[0].Since)
+		require.Equal(t, "1.000000000", m.options[0].Until)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		m := &eventsMockCli{
+			streams: []eventsStream{
+				{messages: []events.Message{
+					containerEvent("abc", events.ActionStart, 1_000, nil),
+					containerEvent("abc", events.ActionDie, 2_000, nil),
+				}},
+			},
+		}
+		sdk := newMockClient(t, m)
+
+		for event, err := range sdk.SubscribeEvents(context.Background()) {
+			require.NoError(t, err)
+			require.Equal(t, events.ActionStart, event.Action)
+			break
+		}
+	})
+
+	t.Run("context-done", func(t *testing.T) {
+		m := &eventsMockCli{streams: []eventsStream{{}}}
+		sdk := newMockClient(t, m)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		var lastErr error
+		for _, err := range sdk.SubscribeEvents(ctx) {
+			lastErr = err
+		}
+		require.ErrorIs(t, lastErr, context.DeadlineExceeded)
+	})
+
+	t.Run("invalid-option", func(t *testing.T) {
+		sdk := newMockClient(t, &eventsMockCli{})
+
+		for _, err := range sdk.SubscribeEvents(context.Background(), EventsResource(events.PluginEventType, "abc")) {
+			require.Error(t, err)
+			require.False(t, errors.Is(err, context.Canceled))
+		}
+	})
+}
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// portForwarderImage is the image of the sidecar container used to forward the ports of
+// Docker daemons that are not dialed through SSH. It runs in the network of the Docker host.
+const portForwarderImage = "alpine/socat:1.8.0.1"
+
+// sidecarRemoveTimeout is the maximum time spent removing the sidecar container
+// when the forwarded ports are closed.
+const sidecarRemoveTimeout = 10 * time.Second
+
+// portForwarder forwards the connections accepted on a local port
+// to an address dialed from the Docker host.
+type portForwarder struct {
+	listener net.Listener
+	remote   string
+	dial     func(ctx context.Context, addr string) (net.Conn, error)
+	log      *slog.Logger
+}
+
+// ForwardsPorts reports whether the ports published by the containers are forwarded
+// to local ports, because the client was created with [WithPortForwarding] and the
+// Docker daemon is remote.
+func (c *sdkClient) ForwardsPorts() bool {
+	if !c.portForwarding {
+		return false
+	}
+
+	if c.sshHost != "" {
+		return true
+	}
+
+	daemonURL, err := url.Parse(c.DaemonHost())
+	if err != nil {
+		return false
+	}
+
+	switch daemonURL.Scheme {
+	case "http", "https", "tcp":
+		host := daemonURL.Hostname()
+		if host == "localhost" {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip == nil || !ip.IsLoopback()
+	default:
+		return false
+	}
+}
+
+// ForwardPort returns a local address, in the form 127.0.0.1:port, forwarding its connections to
+// the given port of the Docker host, e.g. a port published by a container. It allows reaching
+// the published ports of a remote Docker daemon behind a firewall, dialing them from the Docker
+// host itself. For ssh:// Docker hosts, the connections are forwarded through SSH. Otherwise, they
+// are forwarded over the connection to the Docker daemon, running socat in a sidecar container in
+// the network of the Docker host. The same port is forwarded once per client, and it's kept open
+// until the client is closed, or reconnected to another Docker host.
+func (c *sdkClient) ForwardPort(ctx context.Context, port uint16) (string, error) {
+	c.forwardersMtx.Lock()
+	defer c.forwardersMtx.Unlock()
+
+	if f, ok := c.forwarders[port]; ok {
+		return f.listener.Addr().String(), nil
+	}
+
+	dial := c.dialSidecar
+	if c.sshDialer != nil {
+		dial = c.sshDialer.DialRemote
+	} else if err := c.startSidecarLocked(ctx); err != nil {
+		return "", fmt.Errorf("start port forwarder: %w", err)
+	}
+
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("listen: %w", err)
+	}
+
+	f := &portForwarder{
+		listener: listener,
+		remote:   net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))),
+		dial:     dial,
+		log:      c.log,
+	}
+	go f.serve()
+
+	if c.forwarders == nil {
+		c.forwarders = make(map[uint16]*portForwarder)
+	}
+	c.forwarders[port] = f
+
+	c.log.Debug("forwarding port", "local", listener.Addr().String(), "remote", f.remote)
+
+	return listener.Addr().String(), nil
+}
+
+// startSidecarLocked starts the sidecar container used to forward ports, if it's not running yet.
+// The container is labelled with the session of the client, so it's removed by the reaper
+// once the session is dead. As an internal resource of the client, its image is pulled, and
+// the container created, without the admission policy of the client, which would reject it
+// e.g. for using the network of the host.
+func (c *sdkClient) startSidecarLocked(ctx context.Context) error {
+	if c.sidecarID != "" {
+		return nil
+	}
+
+	if _, err := c.ImageInspect(ctx, portForwarderImage); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return fmt.Errorf("image inspect: %w", err)
+		}
+
+		resp, err := c.APIClient.ImagePull(ctx, portForwarderImage, client.ImagePullOptions{})
+		if err != nil {
+			return fmt.Errorf("image pull: %w", err)
+		}
+		_, err = io.Copy(io.Discard, resp)
+		resp.Close()
+		if err != nil {
+			return fmt.Errorf("image pull: %w", err)
+		}
+	}
+
+	c.startReaper()
+
+	resp, err := c.APIClient.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config: &container.Config{
+			Image:      portForwarderImage,
+			Entrypoint: []string{"sleep"},
+			Cmd:        []string{"infinity"},
+			Labels:     c.addSDKLabels(nil),
+		},
+		HostConfig: &container.HostConfig{
+			NetworkMode: "host",
+			AutoRemove:  true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("container create: %w", err)
+	}
+
+	if _, err := c.ContainerStart(ctx, resp.ID, client.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("container start: %w", err)
+	}
+
+	c.sidecarID = resp.ID
+	return nil
+}
+
+// closeForwarders stops forwarding the ports of the Docker host: it closes the local listeners,
+// and removes the sidecar container, if any. The connections already forwarded through SSH are
+// kept until they are closed, while the ones forwarded by the sidecar end with it.
+func (c *sdkClient) closeForwarders() error {
+	c.forwardersMtx.Lock()
+	defer c.forwardersMtx.Unlock()
+
+	var errs []error
+	for port, f := range c.forwarders {
+		if err := f.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, fmt.Errorf("close forwarded port %d: %w", port, err))
+		}
+	}
+	c.forwarders = nil
+
+	if c.sidecarID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), sidecarRemoveTimeout)
+		defer cancel()
+
+		if _, err := c.ContainerRemove(ctx, c.sidecarID, client.ContainerRemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("remove port forwarder: %w", err))
+		}
+		c.sidecarID = ""
+	}
+
+	return errors.Join(errs...)
+}
+
+// dialSidecar returns a connection to the given address, dialed by socat in the sidecar
+// container, through the standard input and output of an exec process attached to it.
+func (c *sdkClient) dialSidecar(ctx context.Context, addr string) (net.Conn, error) {
+	exec, err := c.ExecCreate(ctx, c.sidecarID, client.ExecCreateOptions{
+		Cmd:          []string{"socat", "-", "TCP:" + addr},
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exec create: %w", err)
+	}
+
+	attach, err := c.ExecAttach(ctx, exec.ID, client.ExecAttachOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("exec attach: %w", err)
+	}
+
+	return newExecConn(attach.HijackedResponse), nil
+}
+
+// execConn is a [net.Conn] backed by the streams of an exec process: the data is
+// written to its standard input, and read from its demultiplexed standard output.
+type execConn struct {
+	net.Conn
+
+	hijack client.HijackedResponse
+	stdout *io.PipeReader
+}
+
+func newExecConn(hijack client.HijackedResponse) *execConn {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, io.Discard, hijack.Reader)
+		pw.CloseWithError(err)
+	}()
+
+	return &execConn{
+		Conn:   hijack.Conn,
+		hijack: hijack,
+		stdout: pr,
+	}
+}
+
+// Read implements [net.Conn], reading from the standard output of the process.
+func (c *execConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+// CloseWrite closes the standard input of the process.
+func (c *execConn) CloseWrite() error {
+	return c.hijack.CloseWrite()
+}
+
+// Close implements [net.Conn].
+func (c *execConn) Close() error {
+	c.hijack.Close()
+	return c.stdout.Close()
+}
+
+// serve accepts the connections to the local port, until the listener is closed.
+func (f *portForwarder) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.forward(conn)
+	}
+}
+
+// forward copies the data between the local connection and a new connection
+// to the remote address, until any of them is closed.
+func (f *portForwarder) forward(local net.Conn) {
+	defer local.Close()
+
+	remote, err := f.dial(context.Background(), f.remote)
+	if err != nil {
+		f.log.Warn("failed to forward connection", "remote", f.remote, "error", err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+
+	// closing both connections when any of the copies ends unblocks the other one
+	<-done
+	_ = local.Close()
+	_ = remote.Close()
+	<-done
+}
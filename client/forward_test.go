@@ -0,0 +1,239 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardMockCli is a mock implementation of client.APIClient for a remote daemon, whose
+// exec processes echo their standard input to their multiplexed standard output.
+type forwardMockCli struct {
+	mockCli
+
+	host string
+
+	// missingImage makes the image of the sidecar missing, so that it's pulled.
+	missingImage bool
+
+	pulled  []string
+	created []client.ContainerCreateOptions
+	started []string
+	removed []string
+	execs   [][]string
+}
+
+func (m *forwardMockCli) DaemonHost() string {
+	return m.host
+}
+
+func (m *forwardMockCli) ImageInspect(_ context.Context, _ string, _ ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	if m.missingImage {
+		return client.ImageInspectResult{}, errdefs.ErrNotFound.WithMessage("no such image")
+	}
+	return client.ImageInspectResult{}, nil
+}
+
+func (m *forwardMockCli) ImagePull(_ context.Context, ref string, _ client.ImagePullOptions) (client.ImagePullResponse, error) {
+	m.pulled = append(m.pulled, ref)
+	return emptyPullResponse{}, nil
+}
+
+// emptyPullResponse is the response of a pull without progress messages.
+type emptyPullResponse struct {
+	client.ImagePullResponse
+}
+
+func (emptyPullResponse) Read(_ []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (emptyPullResponse) Close() error {
+	return nil
+}
+
+func (m *forwardMockCli) ContainerCreate(_ context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	m.created = append(m.created, options)
+	return client.ContainerCreateResult{ID: strings.Repeat("f", 64)}, nil
+}
+
+func (m *forwardMockCli) ContainerStart(_ context.Context, containerID string, _ client.ContainerStartOptions) (client.ContainerStartResult, error) {
+	m.started = append(m.started, containerID)
+	return client.ContainerStartResult{}, nil
+}
+
+func (m *forwardMockCli) ContainerRemove(_ context.Context, containerID string, _ client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	m.removed = append(m.removed, containerID)
+	return client.ContainerRemoveResult{}, nil
+}
+
+func (m *forwardMockCli) ExecCreate(_ context.Context, _ string, options client.ExecCreateOptions) (client.ExecCreateResult, error) {
+	m.execs = append(m.execs, options.Cmd)
+	return client.ExecCreateResult{ID: "exec"}, nil
+}
+
+func (m *forwardMockCli) ExecAttach(_ context.Context, _ string, _ client.ExecAttachOptions) (client.ExecAttachResult, error) {
+	local, remote := net.Pipe()
+
+	go func() {
+		defer remote.Close()
+
+		buf := make([]byte, 1024)
+		for {
+			n, err := remote.Read(buf)
+			if err != nil {
+				return
+			}
+
+			// multiplexed frame: stream type, 3 zero bytes, big-endian payload size
+			header := []byte{byte(stdcopy.Stdout), 0, 0, 0, 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(header[4:], uint32(n))
+			if _, err := remote.Write(append(header, buf[:n]...)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return client.ExecAttachResult{HijackedResponse: client.NewHijackedResponse(local, "")}, nil
+}
+
+func TestForwardPort_sidecar(t *testing.T) {
+	m := &forwardMockCli{host: "tcp://remote:2375"}
+
+	sdk := newMockClient(t, m, WithPortForwarding())
+	require.True(t, sdk.ForwardsPorts())
+
+	local, err := sdk.ForwardPort(context.Background(), 8080)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(local, "127.0.0.1:"))
+
+	// the sidecar runs in the network of the Docker host
+	require.Len(t, m.created, 1)
+	require.Equal(t, portForwarderImage, m.created[0].Config.Image)
+	require.Equal(t, container.NetworkMode("host"), m.created[0].HostConfig.NetworkMode)
+	require.Equal(t, []string{strings.Repeat("f", 64)}, m.started)
+
+	conn, err := net.Dial("tcp", local)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "ping\n", line)
+	require.Equal(t, [][]string{{"socat", "-", "TCP:127.0.0.1:8080"}}, m.execs)
+
+	// the sidecar is shared by the forwarded ports
+	_, err = sdk.ForwardPort(context.Background(), 8081)
+	require.NoError(t, err)
+	require.Len(t, m.created, 1)
+}
+
+func TestForwardPort_admissionPolicy(t *testing.T) {
+	m := &forwardMockCli{host: "tcp://remote:2375", missingImage: true}
+
+	sdk := newMockClient(t, m, WithPortForwarding(), WithAdmissionPolicy(
+		ForbidHostNetwork(),
+		RequireResourceLimits(),
+		AllowRegistries("registry.example.com"),
+	))
+
+	// the sidecar is not subject to the admission policy of the client
+	_, err := sdk.ForwardPort(context.Background(), 8080)
+	require.NoError(t, err)
+	require.Equal(t, []string{portForwarderImage}, m.pulled)
+	require.Len(t, m.created, 1)
+	require.Equal(t, container.NetworkMode("host"), m.created[0].HostConfig.NetworkMode)
+	require.Equal(t, sdk.SessionID(), m.created[0].Config.Labels[LabelSessionID])
+
+	// while the containers of the user are
+	_, err = sdk.ContainerCreate(context.Background(), client.ContainerCreateOptions{
+		Config:     &container.Config{Image: "registry.example.com/app"},
+		HostConfig: &container.HostConfig{NetworkMode: "host"},
+	})
+	var violation *PolicyViolationError
+	require.ErrorAs(t, err, &violation)
+	require.Len(t, m.created, 1)
+}
+
+func TestForwardPort_close(t *testing.T) {
+	t.Run("close", func(t *testing.T) {
+		m := &forwardMockCli{host: "tcp://remote:2375"}
+
+		sdk := newMockClient(t, m, WithPortForwarding())
+
+		local, err := sdk.ForwardPort(context.Background(), 8080)
+		require.NoError(t, err)
+
+		require.NoError(t, sdk.Close())
+		require.Equal(t, []string{strings.Repeat("f", 64)}, m.removed)
+
+		_, err = net.Dial("tcp", local)
+		require.Error(t, err)
+
+		// closing again does not remove the sidecar twice
+		require.NoError(t, sdk.Close())
+		require.Len(t, m.removed, 1)
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		m := &forwardMockCli{host: "tcp://remote:2375"}
+
+		sdk := newMockClient(t, m, WithPortForwarding())
+
+		c := sdk.(*sdkClient)
+		target, err := newDialTarget(m.host, nil)
+		require.NoError(t, err)
+		c.dialer = &reconnectDialer{}
+		c.dialer.target.Store(target)
+		c.dialTransport = &http.Transport{DialContext: c.dialer.DialContext}
+
+		local, err := sdk.ForwardPort(context.Background(), 8080)
+		require.NoError(t, err)
+
+		// the sidecar of the previous docker host is removed
+		require.NoError(t, c.reconnect("tcp://other:2375", nil, "other"))
+		require.Equal(t, []string{strings.Repeat("f", 64)}, m.removed)
+
+		_, err = net.Dial("tcp", local)
+		require.Error(t, err)
+
+		// a new sidecar is started for the new docker host
+		_, err = sdk.ForwardPort(context.Background(), 8080)
+		require.NoError(t, err)
+		require.Len(t, m.created, 2)
+	})
+}
+
+func TestForwardsPorts(t *testing.T) {
+	for host, want := range map[string]bool{
+		"tcp://remote:2375":           true,
+		"https://10.0.0.7:2376":       true,
+		"tcp://localhost:2375":        false,
+		"tcp://127.0.0.1:2375":        false,
+		"unix:///var/run/docker.sock": false,
+	} {
+		t.Run(host, func(t *testing.T) {
+			sdk := newMockClient(t, &forwardMockCli{host: host}, WithPortForwarding())
+			require.Equal(t, want, sdk.ForwardsPorts())
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		sdk := newMockClient(t, &forwardMockCli{host: "tcp://remote:2375"})
+		require.False(t, sdk.ForwardsPorts())
+	})
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/versions"
+)
+
+// HealthCheckPolicy configures how the client created by [New] waits for the docker daemon
+// to be ready, and the requirements the daemon must meet. The daemon is pinged until it
+// responds, retrying with an exponential backoff, and then it's checked against the requirements,
+// which are not retried. See [WithHealthCheckPolicy].
+//
+// The zero value retries until the context passed to [New] is done, waiting 100ms
+// before the first retry, and doubling the wait after each retry.
+type HealthCheckPolicy struct {
+	// MaxElapsedTime is the maximum time to wait for the docker daemon to be ready.
+	// Zero means no limit other than the context and MaxAttempts.
+	MaxElapsedTime time.Duration
+
+	// MaxAttempts is the maximum number of pings to the docker daemon.
+	// Zero means no limit other than the context and MaxElapsedTime.
+	MaxAttempts int
+
+	// InitialInterval is the wait before the first retry. Zero means 100ms.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum wait between retries. Zero means no maximum.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the wait grows by after each retry. Zero means 2,
+	// otherwise it must be at least 1, which retries at a constant interval.
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to the given fraction of it, in the range [0, 1],
+	// e.g. 0.2 waits between 80% and 120% of the interval, so that many clients
+	// starting at the same time don't ping the docker daemon in lockstep.
+	Jitter float64
+
+	// MinAPIVersion is the minimum version of the API the docker daemon must support, e.g. "1.44".
+	MinAPIVersion string
+
+	// Requirements are the requirements the docker daemon must meet, once it's ready.
+	Requirements []DaemonRequirement
+}
+
+// DaemonRequirement is a requirement the docker daemon must meet. It returns an error
+// wrapping [errdefs.ErrFailedPrecondition] if the daemon does not meet it.
+type DaemonRequirement func(ctx context.Context, c SDKClient) error
+
+// RequireStorageDriver returns a requirement for the docker daemon to use any of the given
+// storage drivers, e.g. "overlay2".
+func RequireStorageDriver(drivers ...string) DaemonRequirement {
+	return func(ctx context.Context, c SDKClient) error {
+		info, err := c.Info(ctx, client.InfoOptions{})
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(drivers, info.Info.Driver) {
+			return errdefs.ErrFailedPrecondition.WithMessage(fmt.Sprintf(
+				"storage driver %q is not any of %s", info.Info.Driver, strings.Join(drivers, ", ")))
+		}
+		return nil
+	}
+}
+
+// RequireOSType returns a requirement for the docker daemon to run containers
+// of the given operating system type, e.g. "linux" or "windows".
+func RequireOSType(osType string) DaemonRequirement {
+	return func(ctx context.Context, c SDKClient) error {
+		info, err := c.Info(ctx, client.InfoOptions{})
+		if err != nil {
+			return err
+		}
+
+		if info.Info.OSType != osType {
+			return errdefs.ErrFailedPrecondition.WithMessage(fmt.Sprintf(
+				"os type %q is not %q", info.Info.OSType, osType))
+		}
+		return nil
+	}
+}
+
+// validate returns an error if the policy is invalid.
+func (p HealthCheckPolicy) validate() error {
+	switch {
+	case p.MaxElapsedTime < 0:
+		return errors.New("max elapsed time is negative")
+	case p.MaxAttempts < 0:
+		return errors.New("max attempts is negative")
+	case p.InitialInterval < 0:
+		return errors.New("initial interval is negative")
+	case p.MaxInterval < 0:
+		return errors.New("max interval is negative")
+	case p.Multiplier != 0 && p.Multiplier < 1:
+		return fmt.Errorf("multiplier %v is less than 1", p.Multiplier)
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("jitter %v is not in the range [0, 1]", p.Jitter)
+	}
+
+	for i, req := range p.Requirements {
+		if req == nil {
+			return fmt.Errorf("requirement %d is nil", i)
+		}
+	}
+
+	return nil
+}
+
+// healthCheck returns a health check for the client, following the policy.
+func (p HealthCheckPolicy) healthCheck(ctx context.Context) func(c SDKClient) error {
+	return func(c SDKClient) error {
+		ping, err := p.waitReady(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		if p.MinAPIVersion != "" && versions.LessThan(ping.APIVersion, p.MinAPIVersion) {
+			return errdefs.ErrFailedPrecondition.WithMessage(fmt.Sprintf(
+				"docker daemon API version %s is less than %s", ping.APIVersion, p.MinAPIVersion))
+		}
+
+		for _, req := range p.Requirements {
+			if err := req(ctx, c); err != nil {
+				return fmt.Errorf("docker daemon requirement: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// waitReady pings the docker daemon until it responds, retrying with an exponential backoff.
+// Permanent errors, see [IsPermanentClientError], are not retried.
+func (p HealthCheckPolicy) waitReady(ctx context.Context, c SDKClient) (client.PingResult, error) {
+	start := time.Now()
+	b := newExponentialBackoff(p.InitialInterval, p.MaxInterval, p.Multiplier, p.Jitter)
+
+	for attempt := 1; ; attempt++ {
+		ping, err := c.Ping(ctx, client.PingOptions{})
+		if err == nil {
+			return ping, nil
+		}
+
+		if IsPermanentClientError(err) {
+			return client.PingResult{}, fmt.Errorf("docker daemon not ready: %w", err)
+		}
+
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return client.PingResult{}, fmt.Errorf("docker daemon not ready after %d attempts: %w", attempt, err)
+		}
+
+		wait := b.next()
+		if p.MaxElapsedTime > 0 && time.Since(start)+wait > p.MaxElapsedTime {
+			return client.PingResult{}, fmt.Errorf("docker daemon not ready after %s: %w", time.Since(start).Round(time.Millisecond), err)
+		}
+
+		c.Logger().Debug("docker daemon not ready, retrying", "attempt", attempt, "wait", wait, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return client.PingResult{}, fmt.Errorf("docker daemon not ready: %w: %w", ctx.Err(), err)
+		case <-time.After(wait):
+		}
+	}
+}
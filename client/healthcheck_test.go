@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// healthCheckMockCli is a mock implementation of client.APIClient, whose pings
+// fail with the given error until the given number of pings.
+type healthCheckMockCli struct {
+	mockCli
+
+	failures int
+	err      error
+	pings    int
+
+	apiVersion string
+	info       system.Info
+}
+
+func (m *healthCheckMockCli) Ping(_ context.Context, _ client.PingOptions) (client.PingResult, error) {
+	m.pings++
+	if m.pings <= m.failures {
+		return client.PingResult{}, m.err
+	}
+	return client.PingResult{APIVersion: m.apiVersion}, nil
+}
+
+func (m *healthCheckMockCli) Info(_ context.Context, _ client.InfoOptions) (client.SystemInfoResult, error) {
+	return client.SystemInfoResult{Info: m.info}, nil
+}
+
+func (m *healthCheckMockCli) ClientVersion() string {
+	return m.apiVersion
+}
+
+func TestWithHealthCheckPolicy(t *testing.T) {
+	errNotReady := errors.New("connection refused")
+
+	newMock := func(failures int) *healthCheckMockCli {
+		return &healthCheckMockCli{
+			failures:   failures,
+			err:        errNotReady,
+			apiVersion: "1.52",
+			info:       system.Info{Driver: "overlay2", OSType: "linux"},
+		}
+	}
+
+	t.Run("default", func(t *testing.T) {
+		m := newMock(2)
+		_, err := New(context.Background(), WithDockerAPI(m))
+		require.NoError(t, err)
+		require.Equal(t, 3, m.pings)
+
+		m = newMock(3)
+		_, err = New(context.Background(), WithDockerAPI(m))
+		require.ErrorIs(t, err, errNotReady)
+		require.ErrorContains(t, err, "not ready after 3 attempts")
+	})
+
+	t.Run("retry-until-ready", func(t *testing.T) {
+		m := newMock(5)
+		_, err := New(context.Background(), WithDockerAPI(m), WithHealthCheckPolicy(HealthCheckPolicy{
+			InitialInterval: time.Millisecond,
+			Jitter:          0.5,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, 6, m.pings)
+	})
+
+	t.Run("max-elapsed-time", func(t *testing.T) {
+		m := newMock(1000)
+		start := time.Now()
+		_, err := New(context.Background(), WithDockerAPI(m), WithHealthCheckPolicy(HealthCheckPolicy{
+			MaxElapsedTime:  50 * time.Millisecond,
+			InitialInterval: 5 * time.Millisecond,
+			Multiplier:      1,
+		}))
+		require.ErrorIs(t, err, errNotReady)
+		require.Less(t, time.Since(start), time.Second)
+		require.Greater(t, m.pings, 1)
+	})
+
+	t.Run("context-done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := New(ctx, WithDockerAPI(newMock(1000)), WithHealthCheckPolicy(HealthCheckPolicy{}))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, err, errNotReady)
+	})
+
+	t.Run("permanent-error", func(t *testing.T) {
+		m := newMock(1000)
+		m.err = errdefs.ErrPermissionDenied
+		_, err := New(context.Background(), WithDockerAPI(m), WithHealthCheckPolicy(HealthCheckPolicy{MaxElapsedTime: time.Minute}))
+		require.ErrorIs(t, err, errdefs.ErrPermissionDenied)
+		require.Equal(t, 1, m.pings)
+	})
+
+	t.Run("min-api-version", func(t *testing.T) {
+		_, err := New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(HealthCheckPolicy{MinAPIVersion: "1.44"}))
+		require.NoError(t, err)
+
+		_, err = New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(HealthCheckPolicy{MinAPIVersion: "1.53"}))
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+	})
+
+	t.Run("requirements", func(t *testing.T) {
+		_, err := New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(HealthCheckPolicy{
+			Requirements: []DaemonRequirement{RequireStorageDriver("overlay2", "btrfs"), RequireOSType("linux")},
+		}))
+		require.NoError(t, err)
+
+		_, err = New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(HealthCheckPolicy{
+			Requirements: []DaemonRequirement{RequireStorageDriver("btrfs")},
+		}))
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+
+		_, err = New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(HealthCheckPolicy{
+			Requirements: []DaemonRequirement{RequireOSType("windows")},
+		}))
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+	})
+
+	for name, policy := range map[string]HealthCheckPolicy{
+		"negative-max-elapsed-time": {MaxElapsedTime: -time.Second},
+		"multiplier-less-than-one":  {Multiplier: 0.5},
+		"jitter-out-of-range":       {Jitter: 1.5},
+		"nil-requirement":           {Requirements: []DaemonRequirement{nil}},
+	} {
+		t.Run("invalid/"+name, func(t *testing.T) {
+			_, err := New(context.Background(), WithDockerAPI(newMock(0)), WithHealthCheckPolicy(policy))
+			require.ErrorContains(t, err, "health check policy")
+		})
+	}
+}
@@ -11,6 +11,18 @@ const (
 
 	// LabelVersion specifies the version of go-sdk's client.
 	LabelVersion = LabelBase + ".client"
+
+	// LabelSessionID specifies the session of the client that created the resource.
+	// See [SDKClient.SessionID].
+	LabelSessionID = LabelBase + ".session-id"
+
+	// LabelRunMetadata is the prefix of the labels with the run metadata of the client
+	// that created the resource. See [WithRunMetadata].
+	LabelRunMetadata = LabelBase + ".run"
+
+	// LabelReaperSkip marks a resource that must not be removed when its session is dead,
+	// e.g. containers that are meant to be reused across sessions.
+	LabelReaperSkip = LabelBase + ".reaper.skip"
 )
 
 // sdkLabels is a map of labels that can be used to identify resources
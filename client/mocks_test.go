@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCli is embedded by the mocks of the internal tests of the client, which can't use the
+// fake Docker API of the clienttest package, as it imports this package.
+type mockCli struct {
+	client.APIClient
+}
+
+func (m *mockCli) Close() error {
+	return nil
+}
+
+// noopHealthCheck is a health check that always succeeds.
+func noopHealthCheck(_ context.Context) func(SDKClient) error {
+	return func(SDKClient) error { return nil }
+}
+
+// newMockClient returns a client backed by the given mock, applying the given options.
+// Its health check always succeeds, so that the mock doesn't need to answer the pings.
+func newMockClient(t *testing.T, m client.APIClient, opts ...ClientOption) SDKClient {
+	t.Helper()
+
+	sdk, err := New(context.Background(), append([]ClientOption{WithDockerAPI(m), WithHealthCheck(noopHealthCheck)}, opts...)...)
+	require.NoError(t, err)
+
+	return sdk
+}
@@ -3,9 +3,16 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"net/http"
 
 	"github.com/moby/moby/client"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	dockercontext "github.com/docker/go-sdk/context"
 )
 
 // ClientOption is a type that represents an option for configuring a client.
@@ -46,9 +53,12 @@ func newClientOption(f func(*sdkClient) error) ClientOption {
 }
 
 // WithDockerAPI returns a client option that sets the docker client used to access Docker API.
+// The removal of the resources of dead sessions is disabled for clients using a custom docker client,
+// as it's not possible to know if it's connected to a real Docker daemon.
 func WithDockerAPI(api client.APIClient) ClientOption {
 	return newClientOption(func(c *sdkClient) error {
 		c.APIClient = api
+		c.reaperDisabled = true
 		return nil
 	})
 }
@@ -79,9 +89,25 @@ func WithExtraHeaders(headers map[string]string) ClientOption {
 	})
 }
 
+// WithHTTPTransport returns a client option that sets the transport of the HTTP client used to call
+// the Docker API, which is then responsible for connecting to the docker daemon: the docker host is
+// still used to build the requests, but its TLS and SSH settings are not applied to the transport.
+// It's useful to intercept the API calls, e.g. to record and replay them in tests.
+func WithHTTPTransport(transport http.RoundTripper) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if transport == nil {
+			return errors.New("http transport is nil")
+		}
+
+		c.transport = transport
+		return nil
+	})
+}
+
 // WithHealthCheck returns a client option that sets the health check for the client.
 // If not set, the default health check will be used, which retries the ping to the
 // docker daemon until it is ready, three times, or the context is done.
+// See [WithHealthCheckPolicy] to configure the retries instead of replacing the health check.
 func WithHealthCheck(healthCheck func(ctx context.Context) func(c SDKClient) error) ClientOption {
 	return newClientOption(func(c *sdkClient) error {
 		if healthCheck == nil {
@@ -93,6 +119,179 @@ func WithHealthCheck(healthCheck func(ctx context.Context) func(c SDKClient) err
 	})
 }
 
+// WithHealthCheckPolicy returns a client option that sets the health check for the client,
+// following the given policy. E.g. to wait up to a minute for a docker daemon that starts slowly,
+// and reject daemons that are not using the overlay2 storage driver:
+//
+//	cli, err := client.New(ctx, client.WithHealthCheckPolicy(client.HealthCheckPolicy{
+//		MaxElapsedTime: time.Minute,
+//		MaxInterval:    5 * time.Second,
+//		Jitter:         0.2,
+//		Requirements:   []client.DaemonRequirement{client.RequireStorageDriver("overlay2")},
+//	}))
+func WithHealthCheckPolicy(policy HealthCheckPolicy) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("health check policy: %w", err)
+		}
+
+		c.healthCheck = policy.healthCheck
+		return nil
+	})
+}
+
+// WithAdmissionPolicy returns a client option that evaluates the given rules before creating
+// containers and pulling images, rejecting the requests violating any of them with a
+// [PolicyViolationError], without reaching the docker daemon. The sidecar forwarding ports,
+// created by the client itself, is not subject to the policy.
+// E.g. to only run images from a private registry, with an explicit tag:
+//
+//	cli, err := client.New(ctx, client.WithAdmissionPolicy(
+//		client.AllowRegistries("registry.example.com"),
+//		client.ForbidLatestTag(),
+//	))
+func WithAdmissionPolicy(rules ...AdmissionRule) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		for _, rule := range rules {
+			if rule == nil {
+				return errors.New("admission rule is nil")
+			}
+		}
+
+		c.admissionRules = append(c.admissionRules, rules...)
+		return nil
+	})
+}
+
+// WithRetryPolicy returns a client option that retries the idempotent API calls failing
+// with transient errors, following the given policy. The retries are logged at warn level
+// with the logger of the client. E.g. to retry the calls to a busy docker daemon for up to 10 seconds:
+//
+//	cli, err := client.New(ctx, client.WithRetryPolicy(client.RetryPolicy{
+//		MaxElapsedTime: 10 * time.Second,
+//		MaxAttempts:    10,
+//		MaxInterval:    2 * time.Second,
+//	}))
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("retry policy: %w", err)
+		}
+
+		c.retryPolicy = &policy
+		return nil
+	})
+}
+
+// WithRunMetadata returns a client option that adds the given metadata to the labels
+// of all the resources created by the client, using the [LabelRunMetadata] prefix,
+// e.g. "com.docker.sdk.run.job" for the "job" key. It can be used to identify the
+// test run, or the CI job, that created the resources.
+func WithRunMetadata(metadata map[string]string) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		for k := range metadata {
+			if k == "" {
+				return errors.New("run metadata key is empty")
+			}
+		}
+
+		if c.runMetadata == nil {
+			c.runMetadata = make(map[string]string, len(metadata))
+		}
+		maps.Copy(c.runMetadata, metadata)
+		return nil
+	})
+}
+
+// WithContextWatch returns a client option that follows the changes of the docker context of the client,
+// watching the Docker config file and the contexts store with the given options: when the docker host
+// of the context changes, e.g. after `docker context use`, the client transparently reconnects to it.
+// The client follows the current docker context, or the one passed with [WithDockerContext]. It can't be
+// used with [WithDockerHost], with [WithHTTPTransport], or with ssh:// docker hosts.
+func WithContextWatch(opts ...dockercontext.WatchOption) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		c.contextWatch = true
+		c.contextWatchOpts = append(c.contextWatchOpts, opts...)
+		return nil
+	})
+}
+
+// WithSSHDialer returns a client option that sets the dialer used to connect to ssh:// docker hosts,
+// instead of the system ssh binary. It allows using a pure-Go SSH implementation, e.g. one based on
+// golang.org/x/crypto/ssh, configured with its own authentication methods and known hosts.
+func WithSSHDialer(dialer SSHDialer) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if dialer == nil {
+			return errors.New("ssh dialer is nil")
+		}
+
+		c.sshDialer = dialer
+		return nil
+	})
+}
+
+// WithPortForwarding returns a client option that forwards the ports published by the containers
+// of a remote docker daemon to local ports, e.g. when the daemon is behind a firewall. If the daemon
+// is remote, the Host method of the containers returns 127.0.0.1, and their MappedPort method
+// returns the local port forwarding to the published one. Only TCP ports are forwarded.
+// See [SDKClient.ForwardPort] for how the ports are forwarded.
+func WithPortForwarding() ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		c.portForwarding = true
+		return nil
+	})
+}
+
+// WithTracerProvider returns a client option that sets the tracer provider used to trace the API calls:
+// each call produces a span, named after its method and endpoint, e.g. "GET /containers/{id}/json",
+// with the ID of the resource and the status of the response. The spans of the lifecycle phases
+// of the containers, like the ones of container.Run, are created with it too.
+// If not set, the API calls are traced with the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if provider == nil {
+			return errors.New("tracer provider is nil")
+		}
+
+		c.tracerProvider = provider
+		return nil
+	})
+}
+
+// WithMeterProvider returns a client option that sets the meter provider used to measure the API calls:
+// the duration of the calls, with their endpoint and the status of the response, which tells the errors.
+// If not set, the API calls are measured with the global meter provider.
+func WithMeterProvider(provider metric.MeterProvider) ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		if provider == nil {
+			return errors.New("meter provider is nil")
+		}
+
+		c.meterProvider = provider
+		return nil
+	})
+}
+
+// WithoutReaper returns a client option that disables the removal of the resources
+// created by the SDK in dead sessions, i.e. by processes that crashed or exited without
+// cleaning up. It can also be disabled with the [EnvReaperDisabled] environment variable.
+func WithoutReaper() ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		c.reaperDisabled = true
+		return nil
+	})
+}
+
+// WithReaperForceImageRemoval returns a client option that forces the removal of the images
+// created by the SDK in dead sessions. By default, the images still used by containers, or
+// tagged in other repositories, are kept.
+func WithReaperForceImageRemoval() ClientOption {
+	return newClientOption(func(c *sdkClient) error {
+		c.reaperForceImages = true
+		return nil
+	})
+}
+
 // WithLogger returns a client option that sets the logger for the client.
 func WithLogger(log *slog.Logger) ClientOption {
 	return newClientOption(func(c *sdkClient) error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -37,6 +38,12 @@ func TestWithOptions(t *testing.T) {
 			require.NotNil(t, cli.healthCheck)
 		})
 
+		t.Run("http-transport", func(t *testing.T) {
+			cli := &sdkClient{}
+			require.NoError(t, WithHTTPTransport(http.DefaultTransport).Apply(cli))
+			require.Equal(t, http.DefaultTransport, cli.transport)
+		})
+
 		t.Run("logger", func(t *testing.T) {
 			cli := &sdkClient{}
 			require.NoError(t, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))).Apply(cli))
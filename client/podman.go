@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
+
+// podmanDefaultNetwork is the name of the default bridge network of Podman,
+// the equivalent of the "bridge" network of Docker.
+const podmanDefaultNetwork = "podman"
+
+// IsPodman reports whether the daemon is Podman, serving its Docker-compatible API.
+// It returns false if the capabilities of the daemon cannot be probed.
+func IsPodman(ctx context.Context, c SDKClient) bool {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		c.Logger().Debug("failed to probe the capabilities of the docker daemon", "error", err)
+		return false
+	}
+
+	return caps.Engine == EnginePodman
+}
+
+// filtersLabelsLocally reports whether the results of a list filtered by the given
+// filters must be filtered by label in the client too. The Docker-compatible API of
+// some Podman versions matches any of the label filters, instead of all of them.
+func (c *sdkClient) filtersLabelsLocally(ctx context.Context, filters client.Filters) bool {
+	return len(filters["label"]) > 1 && IsPodman(ctx, c)
+}
+
+// matchesLabels reports whether the labels match all the label filters,
+// in the form "key" or "key=value".
+func matchesLabels(labels map[string]string, filters client.Filters) bool {
+	for filter, enabled := range filters["label"] {
+		if !enabled {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(filter, "=")
+		v, ok := labels[key]
+		if !ok || (hasValue && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterByLabels returns the items whose labels match all the label filters, if the results
+// of the list must be filtered by label in the client too, or the items as is otherwise.
+func filterByLabels[T any](ctx context.Context, c *sdkClient, filters client.Filters, items []T, labels func(T) map[string]string) []T {
+	if !c.filtersLabelsLocally(ctx, filters) {
+		return items
+	}
+
+	return slices.DeleteFunc(items, func(item T) bool {
+		return !matchesLabels(labels(item), filters)
+	})
+}
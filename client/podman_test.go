@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// podmanMockCli is a mock implementation of client.APIClient for a Podman or Docker daemon,
+// whose container list matches any of the label filters, like some Podman versions do.
+type podmanMockCli struct {
+	mockCli
+
+	engine     string
+	containers []container.Summary
+	networks   map[string]network.Inspect
+}
+
+func (m *podmanMockCli) Info(_ context.Context, _ client.InfoOptions) (client.SystemInfoResult, error) {
+	return client.SystemInfoResult{}, nil
+}
+
+func (m *podmanMockCli) ServerVersion(_ context.Context, _ client.ServerVersionOptions) (client.ServerVersionResult, error) {
+	return client.ServerVersionResult{Components: []system.ComponentVersion{{Name: m.engine}}}, nil
+}
+
+func (m *podmanMockCli) ClientVersion() string {
+	return "1.41"
+}
+
+func (m *podmanMockCli) ContainerList(_ context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	var result client.ContainerListResult
+	for _, c := range m.containers {
+		for filter := range options.Filters["label"] {
+			if matchesLabels(c.Labels, make(client.Filters).Add("label", filter)) {
+				result.Items = append(result.Items, c)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *podmanMockCli) NetworkInspect(_ context.Context, networkID string, _ client.NetworkInspectOptions) (client.NetworkInspectResult, error) {
+	nw, ok := m.networks[networkID]
+	if !ok {
+		return client.NetworkInspectResult{}, errdefs.ErrNotFound.WithMessage("network " + networkID + " not found")
+	}
+	return client.NetworkInspectResult{Network: nw}, nil
+}
+
+func TestContainerList_labelFilters(t *testing.T) {
+	containers := []container.Summary{
+		{ID: "both", Labels: map[string]string{"app": "shop", "tier": "api"}},
+		{ID: "app-only", Labels: map[string]string{"app": "shop", "tier": "db"}},
+		{ID: "tier-only", Labels: map[string]string{"tier": "api"}},
+	}
+
+	list := func(t *testing.T, engine string, filters client.Filters) []string {
+		t.Helper()
+
+		sdk := newMockClient(t, &podmanMockCli{engine: engine, containers: containers})
+
+		result, err := sdk.ContainerList(context.Background(), client.ContainerListOptions{Filters: filters})
+		require.NoError(t, err)
+
+		var ids []string
+		for _, c := range result.Items {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	filters := make(client.Filters).Add("label", "app=shop", "tier")
+
+	t.Run("podman", func(t *testing.T) {
+		require.Equal(t, []string{"both", "app-only"}, list(t, "Podman Engine", filters))
+	})
+
+	t.Run("podman/single-label", func(t *testing.T) {
+		require.Equal(t, []string{"both", "app-only"}, list(t, "Podman Engine", make(client.Filters).Add("label", "app=shop")))
+	})
+
+	t.Run("docker", func(t *testing.T) {
+		// the results of the daemon are not filtered again
+		require.Len(t, list(t, "Engine", filters), 3)
+	})
+}
+
+func TestMatchesLabels(t *testing.T) {
+	labels := map[string]string{"app": "shop", "tier": "api"}
+
+	require.True(t, matchesLabels(labels, make(client.Filters).Add("label", "app=shop", "tier")))
+	require.False(t, matchesLabels(labels, make(client.Filters).Add("label", "app=shop", "tier=db")))
+	require.False(t, matchesLabels(labels, make(client.Filters).Add("label", "missing")))
+	require.True(t, matchesLabels(labels, make(client.Filters)))
+}
+
+func TestGetGatewayIP(t *testing.T) {
+	withGateway := func(gateway string) network.Inspect {
+		var nw network.Inspect
+		nw.IPAM.Config = []network.IPAMConfig{{Gateway: netip.MustParseAddr(gateway)}}
+		return nw
+	}
+
+	t.Run("docker", func(t *testing.T) {
+		m := &podmanMockCli{networks: map[string]network.Inspect{"bridge": withGateway("172.17.0.1")}}
+		sdk := newMockClient(t, m)
+
+		ip, err := sdk.(*sdkClient).getGatewayIP(context.Background(), "bridge", podmanDefaultNetwork)
+		require.NoError(t, err)
+		require.Equal(t, "172.17.0.1", ip.String())
+	})
+
+	t.Run("podman", func(t *testing.T) {
+		m := &podmanMockCli{networks: map[string]network.Inspect{podmanDefaultNetwork: withGateway("10.88.0.1")}}
+		sdk := newMockClient(t, m)
+
+		ip, err := sdk.(*sdkClient).getGatewayIP(context.Background(), "bridge", podmanDefaultNetwork)
+		require.NoError(t, err)
+		require.Equal(t, "10.88.0.1", ip.String())
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		sdk := newMockClient(t, &podmanMockCli{})
+
+		_, err := sdk.(*sdkClient).getGatewayIP(context.Background(), "bridge", podmanDefaultNetwork)
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+	})
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"github.com/moby/moby/api/types/container"
+)
+
+// AdmissionOperation is the operation of an [AdmissionRequest].
+type AdmissionOperation string
+
+const (
+	// AdmissionContainerCreate is the creation of a container.
+	AdmissionContainerCreate AdmissionOperation = "container-create"
+
+	// AdmissionImagePull is the pull of an image.
+	AdmissionImagePull AdmissionOperation = "image-pull"
+)
+
+// AdmissionRequest is a request evaluated by the rules of the admission policy of the client,
+// before it reaches the docker daemon.
+type AdmissionRequest struct {
+	// Operation is the operation of the request.
+	Operation AdmissionOperation
+
+	// Image is the reference of the image of the request: the image to pull,
+	// or the image of the container to create.
+	Image string
+
+	// Config is the configuration of the container to create. It's nil for image pulls.
+	Config *container.Config
+
+	// HostConfig is the host configuration of the container to create. It's nil for image pulls.
+	HostConfig *container.HostConfig
+}
+
+// AdmissionRule is a rule of the admission policy of the client. See [WithAdmissionPolicy].
+type AdmissionRule interface {
+	// Name returns the name of the rule, reported by the [PolicyViolationError] of the requests it rejects.
+	Name() string
+
+	// Admit returns an error describing why the request violates the rule, or nil if it's admitted.
+	Admit(ctx context.Context, req AdmissionRequest) error
+}
+
+// PolicyViolationError is returned by the client when a request violates a rule of its admission policy.
+// It's a permission denied error, so it's reported as such by [errdefs.IsPermissionDenied].
+type PolicyViolationError struct {
+	// Rule is the name of the violated rule.
+	Rule string
+
+	// Operation is the operation of the rejected request.
+	Operation AdmissionOperation
+
+	// Image is the image of the rejected request.
+	Image string
+
+	// Err describes the violation.
+	Err error
+}
+
+// Error returns the message of the error.
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("admission policy: rule %s rejected %s of %s: %v", e.Rule, e.Operation, e.Image, e.Err)
+}
+
+// Unwrap returns the errors wrapped by the error: the violation, and [errdefs.ErrPermissionDenied].
+func (e *PolicyViolationError) Unwrap() []error {
+	return []error{e.Err, errdefs.ErrPermissionDenied}
+}
+
+// admissionRule is a rule built from a name and a function.
+type admissionRule struct {
+	name  string
+	admit func(ctx context.Context, req AdmissionRequest) error
+}
+
+// Name implements the [AdmissionRule] interface.
+func (r admissionRule) Name() string {
+	return r.name
+}
+
+// Admit implements the [AdmissionRule] interface.
+func (r admissionRule) Admit(ctx context.Context, req AdmissionRequest) error {
+	return r.admit(ctx, req)
+}
+
+// NewAdmissionRule returns an admission rule with the given name, admitting the requests for which
+// the given function returns nil.
+func NewAdmissionRule(name string, admit func(ctx context.Context, req AdmissionRequest) error) AdmissionRule {
+	return admissionRule{name: name, admit: admit}
+}
+
+// AllowRegistries returns a rule admitting only the images of the given registries, e.g. "docker.io"
+// or "registry.example.com:5000". The images without registry are from "docker.io".
+func AllowRegistries(registries ...string) AdmissionRule {
+	return NewAdmissionRule("allow-registries", func(_ context.Context, req AdmissionRequest) error {
+		named, err := reference.ParseNormalizedNamed(req.Image)
+		if err != nil {
+			return fmt.Errorf("parse image: %w", err)
+		}
+
+		if registry := reference.Domain(named); !slices.Contains(registries, registry) {
+			return fmt.Errorf("registry %s is not any of %s", registry, strings.Join(registries, ", "))
+		}
+		return nil
+	})
+}
+
+// ForbidLatestTag returns a rule rejecting the images with the "latest" tag, or without tag nor digest.
+func ForbidLatestTag() AdmissionRule {
+	return NewAdmissionRule("forbid-latest-tag", func(_ context.Context, req AdmissionRequest) error {
+		named, err := reference.ParseNormalizedNamed(req.Image)
+		if err != nil {
+			return fmt.Errorf("parse image: %w", err)
+		}
+
+		if _, ok := named.(reference.Digested); ok {
+			return nil
+		}
+
+		if tagged, ok := named.(reference.Tagged); !ok || tagged.Tag() == "latest" {
+			return errors.New("the latest tag is forbidden, use an explicit tag or digest")
+		}
+		return nil
+	})
+}
+
+// ForbidPrivileged returns a rule rejecting the privileged containers.
+func ForbidPrivileged() AdmissionRule {
+	return NewAdmissionRule("forbid-privileged", func(_ context.Context, req AdmissionRequest) error {
+		if req.HostConfig != nil && req.HostConfig.Privileged {
+			return errors.New("privileged containers are forbidden")
+		}
+		return nil
+	})
+}
+
+// ForbidHostNetwork returns a rule rejecting the containers using the network of the host.
+func ForbidHostNetwork() AdmissionRule {
+	return NewAdmissionRule("forbid-host-network", func(_ context.Context, req AdmissionRequest) error {
+		if req.HostConfig != nil && req.HostConfig.NetworkMode.IsHost() {
+			return errors.New("the host network is forbidden")
+		}
+		return nil
+	})
+}
+
+// RequireResourceLimits returns a rule rejecting the containers without memory and CPU limits.
+// The CPU limit can be set with either NanoCPUs or CPUQuota.
+func RequireResourceLimits() AdmissionRule {
+	return NewAdmissionRule("require-resource-limits", func(_ context.Context, req AdmissionRequest) error {
+		if req.Operation != AdmissionContainerCreate {
+			return nil
+		}
+
+		var missing []string
+		if req.HostConfig == nil || req.HostConfig.Memory <= 0 {
+			missing = append(missing, "memory")
+		}
+		if req.HostConfig == nil || (req.HostConfig.NanoCPUs <= 0 && req.HostConfig.CPUQuota <= 0) {
+			missing = append(missing, "cpu")
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("missing resource limits: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+}
+
+// admit evaluates the request against the rules of the admission policy of the client,
+// returning a [PolicyViolationError] for the first rule rejecting it.
+func (c *sdkClient) admit(ctx context.Context, req AdmissionRequest) error {
+	for _, rule := range c.admissionRules {
+		if err := rule.Admit(ctx, req); err != nil {
+			return &PolicyViolationError{Rule: rule.Name(), Operation: req.Operation, Image: req.Image, Err: err}
+		}
+	}
+	return nil
+}
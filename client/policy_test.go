@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+func TestAdmissionRules(t *testing.T) {
+	ctx := context.Background()
+
+	create := func(image string, hostConfig *container.HostConfig) client.AdmissionRequest {
+		return client.AdmissionRequest{
+			Operation:  client.AdmissionContainerCreate,
+			Image:      image,
+			Config:     &container.Config{Image: image},
+			HostConfig: hostConfig,
+		}
+	}
+	pull := func(image string) client.AdmissionRequest {
+		return client.AdmissionRequest{Operation: client.AdmissionImagePull, Image: image}
+	}
+
+	limits := &container.HostConfig{Resources: container.Resources{Memory: 64 << 20, NanoCPUs: 500_000_000}}
+
+	tests := []struct {
+		name    string
+		rule    client.AdmissionRule
+		req     client.AdmissionRequest
+		wantErr bool
+	}{
+		{name: "allow-registries/docker-hub", rule: client.AllowRegistries("docker.io"), req: pull("alpine:3.22")},
+		{name: "allow-registries/private", rule: client.AllowRegistries("registry.example.com:5000"), req: pull("registry.example.com:5000/app:1.0")},
+		{name: "allow-registries/rejected", rule: client.AllowRegistries("registry.example.com"), req: pull("alpine:3.22"), wantErr: true},
+		{name: "allow-registries/invalid-image", rule: client.AllowRegistries("docker.io"), req: pull("Alpine"), wantErr: true},
+		{name: "forbid-latest-tag/tag", rule: client.ForbidLatestTag(), req: pull("alpine:3.22")},
+		{name: "forbid-latest-tag/digest", rule: client.ForbidLatestTag(), req: pull("alpine@sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1")},
+		{name: "forbid-latest-tag/latest", rule: client.ForbidLatestTag(), req: pull("alpine:latest"), wantErr: true},
+		{name: "forbid-latest-tag/no-tag", rule: client.ForbidLatestTag(), req: pull("alpine"), wantErr: true},
+		{name: "forbid-privileged/unprivileged", rule: client.ForbidPrivileged(), req: create("alpine", nil)},
+		{name: "forbid-privileged/privileged", rule: client.ForbidPrivileged(), req: create("alpine", &container.HostConfig{Privileged: true}), wantErr: true},
+		{name: "forbid-host-network/bridge", rule: client.ForbidHostNetwork(), req: create("alpine", &container.HostConfig{NetworkMode: "bridge"})},
+		{name: "forbid-host-network/host", rule: client.ForbidHostNetwork(), req: create("alpine", &container.HostConfig{NetworkMode: "host"}), wantErr: true},
+		{name: "require-resource-limits/limits", rule: client.RequireResourceLimits(), req: create("alpine", limits)},
+		{name: "require-resource-limits/cpu-quota", rule: client.RequireResourceLimits(), req: create("alpine", &container.HostConfig{Resources: container.Resources{Memory: 64 << 20, CPUQuota: 50_000}})},
+		{name: "require-resource-limits/pull", rule: client.RequireResourceLimits(), req: pull("alpine:3.22")},
+		{name: "require-resource-limits/no-limits", rule: client.RequireResourceLimits(), req: create("alpine", nil), wantErr: true},
+		{name: "require-resource-limits/no-cpu", rule: client.RequireResourceLimits(), req: create("alpine", &container.HostConfig{Resources: container.Resources{Memory: 64 << 20}}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Admit(ctx, tt.req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithAdmissionPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clienttest.NewFakeAPI()
+	_, err := fake.AddImage("alpine:3.22")
+	require.NoError(t, err)
+
+	cli, err := clienttest.NewFakeClient(ctx, fake, client.WithAdmissionPolicy(
+		client.ForbidLatestTag(),
+		client.ForbidPrivileged(),
+	))
+	require.NoError(t, err)
+
+	t.Run("container-create/admitted", func(t *testing.T) {
+		_, err := cli.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{Config: &container.Config{Image: "alpine:3.22"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("container-create/rejected", func(t *testing.T) {
+		_, err := cli.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{
+			Config:     &container.Config{Image: "alpine:3.22"},
+			HostConfig: &container.HostConfig{Privileged: true},
+		})
+
+		var violation *client.PolicyViolationError
+		require.ErrorAs(t, err, &violation)
+		require.Equal(t, "forbid-privileged", violation.Rule)
+		require.Equal(t, client.AdmissionContainerCreate, violation.Operation)
+		require.Equal(t, "alpine:3.22", violation.Image)
+		require.True(t, errdefs.IsPermissionDenied(err))
+		require.True(t, client.IsPermanentClientError(err))
+
+		list, err := cli.ContainerList(ctx, dockerclient.ContainerListOptions{All: true})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+	})
+
+	t.Run("image-pull/rejected", func(t *testing.T) {
+		_, err := cli.ImagePull(ctx, "nginx:latest", dockerclient.ImagePullOptions{})
+
+		var violation *client.PolicyViolationError
+		require.ErrorAs(t, err, &violation)
+		require.Equal(t, "forbid-latest-tag", violation.Rule)
+		require.Equal(t, client.AdmissionImagePull, violation.Operation)
+	})
+
+	t.Run("custom-rule", func(t *testing.T) {
+		errNoRoot := errors.New("root user")
+		cli, err := clienttest.NewFakeClient(ctx, fake, client.WithAdmissionPolicy(
+			client.NewAdmissionRule("forbid-root", func(_ context.Context, req client.AdmissionRequest) error {
+				if req.Config != nil && (req.Config.User == "" || req.Config.User == "root") {
+					return errNoRoot
+				}
+				return nil
+			}),
+		))
+		require.NoError(t, err)
+
+		_, err = cli.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{Config: &container.Config{Image: "alpine:3.22"}})
+		require.ErrorIs(t, err, errNoRoot)
+
+		_, err = cli.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{Config: &container.Config{Image: "alpine:3.22", User: "nobody"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("nil-rule", func(t *testing.T) {
+		_, err := clienttest.NewFakeClient(ctx, fake, client.WithAdmissionPolicy(nil))
+		require.Error(t, err)
+	})
+}
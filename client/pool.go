@@ -0,0 +1,316 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// SchedulingStrategy ranks the clients of a [Pool] to schedule a new container on them:
+// the first healthy client of the ranking is picked.
+type SchedulingStrategy interface {
+	// Rank returns the given clients, ordered by preference.
+	Rank(ctx context.Context, clients []SDKClient) ([]SDKClient, error)
+}
+
+// SchedulingStrategyFunc is a function that implements [SchedulingStrategy].
+type SchedulingStrategyFunc func(ctx context.Context, clients []SDKClient) ([]SDKClient, error)
+
+// Rank implements the [SchedulingStrategy] interface.
+func (f SchedulingStrategyFunc) Rank(ctx context.Context, clients []SDKClient) ([]SDKClient, error) {
+	return f(ctx, clients)
+}
+
+// roundRobin is the strategy rotating the clients on each scheduling.
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+// Rank returns the clients starting from the one after the first client of the previous ranking.
+func (s *roundRobin) Rank(_ context.Context, clients []SDKClient) ([]SDKClient, error) {
+	if len(clients) == 0 {
+		return nil, nil
+	}
+
+	start := int((s.next.Add(1) - 1) % uint64(len(clients)))
+	return append(slices.Clone(clients[start:]), clients[:start]...), nil
+}
+
+// RoundRobin returns a strategy scheduling the containers on each client in turn.
+// It's the default strategy of a [Pool].
+func RoundRobin() SchedulingStrategy {
+	return &roundRobin{}
+}
+
+// LeastContainers returns a strategy scheduling the containers on the client with the fewest
+// running containers. The clients failing to list their containers are ranked last.
+func LeastContainers() SchedulingStrategy {
+	return rankBy(func(ctx context.Context, cli SDKClient) (int64, error) {
+		list, err := cli.ContainerList(ctx, client.ContainerListOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("container list: %w", err)
+		}
+		return int64(len(list.Items)), nil
+	})
+}
+
+// LeastMemory returns a strategy scheduling the containers on the client with the most free memory:
+// the total memory of the docker host, reported by Info, minus the memory used by its running containers.
+// The clients failing to report their memory are ranked last.
+func LeastMemory() SchedulingStrategy {
+	return rankBy(func(ctx context.Context, cli SDKClient) (int64, error) {
+		info, err := cli.Info(ctx, client.InfoOptions{})
+		if err != nil {
+			return 0, err
+		}
+
+		used, err := memoryUsage(ctx, cli)
+		if err != nil {
+			return 0, err
+		}
+
+		// rank by used memory, so that the client with the most free memory comes first
+		return used - info.Info.MemTotal, nil
+	})
+}
+
+// memoryUsage returns the memory used by the running containers of the client.
+func memoryUsage(ctx context.Context, cli SDKClient) (int64, error) {
+	list, err := cli.ContainerList(ctx, client.ContainerListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("container list: %w", err)
+	}
+
+	var used int64
+	for _, c := range list.Items {
+		resp, err := cli.ContainerStats(ctx, c.ID, client.ContainerStatsOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("container stats: %w", err)
+		}
+
+		var stats container.StatsResponse
+		err = json.NewDecoder(resp.Body).Decode(&stats)
+		resp.Body.Close()
+		if err != nil {
+			return 0, fmt.Errorf("decode container stats: %w", err)
+		}
+
+		used += int64(stats.MemoryStats.Usage)
+	}
+
+	return used, nil
+}
+
+// rankBy returns a strategy ranking the clients by the given score, lowest first.
+// The clients whose score cannot be computed are ranked last, keeping their order.
+func rankBy(score func(ctx context.Context, cli SDKClient) (int64, error)) SchedulingStrategy {
+	return SchedulingStrategyFunc(func(ctx context.Context, clients []SDKClient) ([]SDKClient, error) {
+		type scored struct {
+			cli   SDKClient
+			score int64
+			err   error
+		}
+
+		scores := make([]scored, len(clients))
+		var wg sync.WaitGroup
+		for i, cli := range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s, err := score(ctx, cli)
+				scores[i] = scored{cli: cli, score: s, err: err}
+			}()
+		}
+		wg.Wait()
+
+		slices.SortStableFunc(scores, func(a, b scored) int {
+			if (a.err == nil) != (b.err == nil) {
+				if a.err == nil {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(a.score, b.score)
+		})
+
+		ranked := make([]SDKClient, len(scores))
+		for i, s := range scores {
+			ranked[i] = s.cli
+		}
+		return ranked, nil
+	})
+}
+
+// PoolOption is a type that represents an option for configuring a client pool.
+type PoolOption func(*Pool) error
+
+// WithPoolHosts returns a pool option that adds a client for each of the given docker hosts.
+func WithPoolHosts(hosts ...string) PoolOption {
+	return func(p *Pool) error {
+		for _, host := range hosts {
+			if host == "" {
+				return errors.New("docker host is empty")
+			}
+			p.members = append(p.members, poolMember{name: host, opt: WithDockerHost(host)})
+		}
+		return nil
+	}
+}
+
+// WithPoolContexts returns a pool option that adds a client for each of the given docker contexts.
+func WithPoolContexts(contexts ...string) PoolOption {
+	return func(p *Pool) error {
+		for _, dockerContext := range contexts {
+			if dockerContext == "" {
+				return errors.New("docker context is empty")
+			}
+			p.members = append(p.members, poolMember{name: dockerContext, opt: WithDockerContext(dockerContext)})
+		}
+		return nil
+	}
+}
+
+// WithPoolClients returns a pool option that adds the given clients to the pool.
+// The pool closes them when it's closed.
+func WithPoolClients(clients ...SDKClient) PoolOption {
+	return func(p *Pool) error {
+		for _, cli := range clients {
+			if cli == nil {
+				return errors.New("client is nil")
+			}
+		}
+		p.clients = append(p.clients, clients...)
+		return nil
+	}
+}
+
+// WithPoolClientOptions returns a pool option that applies the given client options to
+// the clients created for the hosts and contexts of the pool, e.g. the logger, or the health check.
+func WithPoolClientOptions(opts ...ClientOption) PoolOption {
+	return func(p *Pool) error {
+		p.clientOpts = append(p.clientOpts, opts...)
+		return nil
+	}
+}
+
+// WithPoolStrategy returns a pool option that sets the strategy used to schedule the containers.
+// If not set, the containers are scheduled with [RoundRobin].
+func WithPoolStrategy(strategy SchedulingStrategy) PoolOption {
+	return func(p *Pool) error {
+		if strategy == nil {
+			return errors.New("scheduling strategy is nil")
+		}
+		p.strategy = strategy
+		return nil
+	}
+}
+
+// poolMember is a docker host or context of a pool, with the option to create its client.
+type poolMember struct {
+	name string
+	opt  ClientOption
+}
+
+// Pool is a pool of clients connected to several docker hosts, which schedules
+// the containers across them. Pass it to container.Run with the container.WithClientPool
+// option, and each container keeps using the client it was scheduled on.
+// The pool owns its clients: they are closed by [Pool.Close], and never by the containers
+// scheduled on them, so the pool must be closed once its containers are terminated.
+//
+// The pool is safe for concurrent use by multiple goroutines.
+type Pool struct {
+	members    []poolMember
+	clientOpts []ClientOption
+	strategy   SchedulingStrategy
+
+	// clients are the healthy clients of the pool.
+	clients []SDKClient
+}
+
+// NewPool returns a new pool of clients, one for each of the docker hosts and contexts of the
+// given options, plus the given clients. Each client is health-checked when it's created: the
+// docker hosts failing the health check are left out of the pool, logging a warning with the
+// logger of the clients, and an error is returned only if none of them passes it.
+//
+// E.g. to schedule the containers on the docker host running the fewest containers:
+//
+//	pool, err := client.NewPool(ctx,
+//		client.WithPoolHosts("tcp://docker-1:2375", "tcp://docker-2:2375"),
+//		client.WithPoolStrategy(client.LeastContainers()),
+//	)
+func NewPool(ctx context.Context, opts ...PoolOption) (*Pool, error) {
+	p := &Pool{strategy: RoundRobin()}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	if len(p.members) == 0 && len(p.clients) == 0 {
+		return nil, errors.New("no docker hosts in the pool")
+	}
+
+	var errs []error
+	for _, member := range p.members {
+		cli, err := New(ctx, append(slices.Clone(p.clientOpts), member.opt)...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", member.name, err))
+			continue
+		}
+		p.clients = append(p.clients, cli)
+	}
+
+	if len(p.clients) == 0 {
+		return nil, fmt.Errorf("no healthy docker hosts in the pool: %w", errors.Join(errs...))
+	}
+
+	for _, err := range errs {
+		p.clients[0].Logger().Warn("Docker host left out of the pool", "error", err)
+	}
+
+	return p, nil
+}
+
+// Clients returns the healthy clients of the pool.
+func (p *Pool) Clients() []SDKClient {
+	return slices.Clone(p.clients)
+}
+
+// Pick returns the client to schedule a new container on: the first client of the ranking
+// of the strategy of the pool that responds to a ping.
+func (p *Pool) Pick(ctx context.Context) (SDKClient, error) {
+	ranked, err := p.strategy.Rank(ctx, p.Clients())
+	if err != nil {
+		return nil, fmt.Errorf("rank clients: %w", err)
+	}
+
+	var errs []error
+	for _, cli := range ranked {
+		if _, err := cli.Ping(ctx, client.PingOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cli.DaemonHost(), err))
+			continue
+		}
+		return cli, nil
+	}
+
+	return nil, fmt.Errorf("no healthy docker hosts in the pool: %w", errors.Join(errs...))
+}
+
+// Close closes all the clients of the pool.
+func (p *Pool) Close() error {
+	var errs []error
+	for _, cli := range p.clients {
+		if err := cli.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
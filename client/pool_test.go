@@ -0,0 +1,144 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+// newFakePool returns a pool of n clients backed by fake Docker APIs, with the alpine image.
+func newFakePool(t *testing.T, n int, opts ...client.PoolOption) (*client.Pool, []*clienttest.FakeAPI) {
+	t.Helper()
+
+	var fakes []*clienttest.FakeAPI
+	var clients []client.SDKClient
+	for range n {
+		fake := clienttest.NewFakeAPI()
+		_, err := fake.AddImage("alpine:latest")
+		require.NoError(t, err)
+
+		cli, err := clienttest.NewFakeClient(context.Background(), fake)
+		require.NoError(t, err)
+
+		fakes = append(fakes, fake)
+		clients = append(clients, cli)
+	}
+
+	pool, err := client.NewPool(context.Background(), append([]client.PoolOption{client.WithPoolClients(clients...)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, pool.Close()) })
+
+	return pool, fakes
+}
+
+// runOn creates and starts a container on the given client, returning its ID.
+func runOn(t *testing.T, cli client.SDKClient) string {
+	t.Helper()
+
+	ctx := context.Background()
+	created, err := cli.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{Config: &container.Config{Image: "alpine"}})
+	require.NoError(t, err)
+
+	_, err = cli.ContainerStart(ctx, created.ID, dockerclient.ContainerStartOptions{})
+	require.NoError(t, err)
+
+	return created.ID
+}
+
+func TestNewPool(t *testing.T) {
+	t.Run("no-hosts", func(t *testing.T) {
+		_, err := client.NewPool(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("empty-host", func(t *testing.T) {
+		_, err := client.NewPool(context.Background(), client.WithPoolHosts(""))
+		require.Error(t, err)
+	})
+
+	t.Run("unhealthy-hosts-left-out", func(t *testing.T) {
+		healthy, err := clienttest.NewFakeClient(context.Background(), clienttest.NewFakeAPI())
+		require.NoError(t, err)
+
+		pool, err := client.NewPool(context.Background(),
+			client.WithPoolClients(healthy),
+			client.WithPoolHosts("tcp://unhealthy:2375"),
+			client.WithPoolClientOptions(client.WithHealthCheck(func(_ context.Context) func(client.SDKClient) error {
+				return func(client.SDKClient) error { return errors.New("unhealthy") }
+			})),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []client.SDKClient{healthy}, pool.Clients())
+	})
+
+	t.Run("all-hosts-unhealthy", func(t *testing.T) {
+		_, err := client.NewPool(context.Background(),
+			client.WithPoolHosts("tcp://unhealthy-1:2375", "tcp://unhealthy-2:2375"),
+			client.WithPoolClientOptions(client.WithHealthCheck(func(_ context.Context) func(client.SDKClient) error {
+				return func(client.SDKClient) error { return errors.New("unhealthy") }
+			})),
+		)
+		require.ErrorContains(t, err, "tcp://unhealthy-1:2375")
+		require.ErrorContains(t, err, "tcp://unhealthy-2:2375")
+	})
+}
+
+func TestPool_Pick(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round-robin", func(t *testing.T) {
+		pool, _ := newFakePool(t, 3)
+		clients := pool.Clients()
+
+		for i := range 6 {
+			cli, err := pool.Pick(ctx)
+			require.NoError(t, err)
+			require.Same(t, clients[i%3], cli)
+		}
+	})
+
+	t.Run("least-containers", func(t *testing.T) {
+		pool, _ := newFakePool(t, 3, client.WithPoolStrategy(client.LeastContainers()))
+		clients := pool.Clients()
+
+		runOn(t, clients[0])
+		runOn(t, clients[0])
+		runOn(t, clients[2])
+
+		cli, err := pool.Pick(ctx)
+		require.NoError(t, err)
+		require.Same(t, clients[1], cli)
+	})
+
+	t.Run("least-memory", func(t *testing.T) {
+		pool, fakes := newFakePool(t, 3, client.WithPoolStrategy(client.LeastMemory()))
+		clients := pool.Clients()
+
+		for i, usage := range []uint64{2 << 30, 1 << 30, 3 << 30} {
+			require.NoError(t, fakes[i].SetMemoryUsage(runOn(t, clients[i]), usage))
+		}
+
+		cli, err := pool.Pick(ctx)
+		require.NoError(t, err)
+		require.Same(t, clients[1], cli)
+	})
+
+	t.Run("custom-strategy", func(t *testing.T) {
+		pool, _ := newFakePool(t, 2, client.WithPoolStrategy(client.SchedulingStrategyFunc(
+			func(_ context.Context, clients []client.SDKClient) ([]client.SDKClient, error) {
+				return []client.SDKClient{clients[1], clients[0]}, nil
+			},
+		)))
+
+		cli, err := pool.Pick(ctx)
+		require.NoError(t, err)
+		require.Same(t, pool.Clients()[1], cli)
+	})
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether the process with the given PID is running,
+// sending it the null signal, which only checks that it exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package client
+
+import "os"
+
+// processAlive reports whether the process with the given PID is running:
+// on Windows, finding a process fails if it doesn't exist.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -0,0 +1,326 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+)
+
+const (
+	// EnvReaperDisabled is the environment variable that disables the resource reaper,
+	// when set to a true value, e.g. "true" or "1".
+	EnvReaperDisabled = "DOCKER_SDK_REAPER_DISABLED"
+
+	// heartbeatInterval is the interval at which the heartbeat of the session is refreshed.
+	heartbeatInterval = 10 * time.Second
+
+	// sessionTTL is the time after which a session without heartbeat is considered dead,
+	// once its process is gone. It's well above the heartbeat interval, so that a late
+	// heartbeat, e.g. of a process resumed after the machine slept, doesn't expire.
+	sessionTTL = 5 * time.Minute
+
+	// sweepTimeout is the maximum time spent removing the resources of dead sessions.
+	sweepTimeout = time.Minute
+)
+
+// sessionsDir returns the directory where the heartbeats of the sessions of the given
+// docker host are stored, so that the sessions are only reaped through the docker
+// daemon their resources were created on.
+func sessionsDir(dockerHost string) string {
+	sum := sha256.Sum256([]byte(dockerHost))
+	return filepath.Join(os.TempDir(), "docker-go-sdk", "sessions", hex.EncodeToString(sum[:8]))
+}
+
+// reaperDisabledFromEnv returns true if the reaper is disabled by the [EnvReaperDisabled] environment variable.
+func reaperDisabledFromEnv() bool {
+	disabled, err := strconv.ParseBool(os.Getenv(EnvReaperDisabled))
+	return err == nil && disabled
+}
+
+// reaper removes the resources created by the SDK that belong to dead sessions,
+// which are left behind by processes that crashed, or exited without cleaning up.
+//
+// It runs in-process: each session keeps a heartbeat file, holding the PID of its process,
+// up to date while the process is alive, in the sessions directory of its docker host. The
+// sessions whose process is gone and whose heartbeat is older than [sessionTTL] are considered
+// dead. Sessions without a heartbeat file, e.g. sessions of processes running on other hosts
+// against the same Docker daemon, are never reaped.
+type reaper struct {
+	api client.APIClient
+	log *slog.Logger
+
+	// dir is the directory where the heartbeats of the sessions of the docker host are stored.
+	dir string
+
+	// forceImages forces the removal of the images of the dead sessions.
+	forceImages bool
+
+	// sessionID is the ID of the session of the client, which is never reaped.
+	sessionID string
+
+	// ttl is the time after which a session without heartbeat is considered dead.
+	ttl time.Duration
+
+	// now returns the current time, and alive reports whether the process with the given
+	// PID is running. They're used to simplify testing.
+	now   func() time.Time
+	alive func(pid int) bool
+}
+
+// newReaper returns a reaper for the resources of the given Docker API client, connected
+// to the given docker host, keeping the heartbeat of the given session.
+func newReaper(api client.APIClient, log *slog.Logger, dockerHost, sessionID string) *reaper {
+	return &reaper{
+		api:       api,
+		log:       log,
+		dir:       sessionsDir(dockerHost),
+		sessionID: sessionID,
+		ttl:       sessionTTL,
+		now:       time.Now,
+		alive:     processAlive,
+	}
+}
+
+// startReaper starts the heartbeat of the session of the client, and removes the
+// resources of the dead sessions in the background.
+// It's called the first time the client creates a resource, and it's a no-op if
+// the reaper is disabled.
+//
+// The heartbeat is kept until the client is closed: the session is then considered dead
+// once its heartbeat expires and its process is gone, and its remaining resources are
+// removed by the other sessions of the same docker host. The heartbeat is stored for
+// the docker host the client is connected to when the reaper starts: the resources
+// created after reconnecting to another docker host are never reaped.
+func (c *sdkClient) startReaper() {
+	if c.reaperDisabled {
+		return
+	}
+
+	c.reaperOnce.Do(func() {
+		r := newReaper(c.APIClient, c.log, c.DaemonHost(), c.sessionID)
+		r.forceImages = c.reaperForceImages
+
+		reaperCtx, cancel := context.WithCancel(context.Background())
+		c.reaperCancel = cancel
+
+		c.reaperWg.Add(2)
+		go func() {
+			defer c.reaperWg.Done()
+			r.heartbeat(reaperCtx, heartbeatInterval)
+		}()
+
+		go func() {
+			defer c.reaperWg.Done()
+
+			ctx, cancel := context.WithTimeout(reaperCtx, sweepTimeout)
+			defer cancel()
+
+			if err := r.sweep(ctx); err != nil {
+				c.log.Debug("failed to remove the resources of dead sessions", "error", err)
+			}
+		}()
+	})
+}
+
+// stopReaper stops the heartbeat of the session, and the removal of the resources of
+// the dead sessions, if the reaper was started, waiting for them to return, so that the
+// heartbeat is not refreshed once the client is closed. Once stopped, the reaper is never started.
+func (c *sdkClient) stopReaper() {
+	c.reaperOnce.Do(func() {})
+
+	if c.reaperCancel != nil {
+		c.reaperCancel()
+	}
+	c.reaperWg.Wait()
+}
+
+// heartbeat refreshes the heartbeat of the session at the given interval,
+// until the context is done.
+func (r *reaper) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.beat(); err != nil {
+			r.log.Debug("failed to refresh the session heartbeat", "session", r.sessionID, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// beat writes the heartbeat file of the session, updating its modification time.
+func (r *reaper) beat() error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("create sessions dir: %w", err)
+	}
+
+	path := filepath.Join(r.dir, r.sessionID)
+	now := r.now()
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+		return fmt.Errorf("write heartbeat: %w", err)
+	}
+
+	return os.Chtimes(path, now, now)
+}
+
+// deadSessions returns the IDs of the sessions whose heartbeat expired, and whose
+// process is gone. A heartbeat without a valid PID is only checked for expiry.
+func (r *reaper) deadSessions() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read sessions dir: %w", err)
+	}
+
+	var sessions []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == r.sessionID {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// the heartbeat was removed by another reaper in the meantime
+			continue
+		}
+
+		if r.now().Sub(info.ModTime()) <= r.ttl {
+			continue
+		}
+
+		if pid, ok := r.heartbeatPID(entry.Name()); ok && r.alive(pid) {
+			continue
+		}
+
+		sessions = append(sessions, entry.Name())
+	}
+
+	return sessions, nil
+}
+
+// heartbeatPID returns the PID of the process of the given session, written in its heartbeat.
+func (r *reaper) heartbeatPID(session string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(r.dir, session))
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+
+	return pid, true
+}
+
+// sweep removes the resources of the dead sessions, and their heartbeat files
+// once all their resources are removed.
+func (r *reaper) sweep(ctx context.Context) error {
+	sessions, err := r.deadSessions()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, session := range sessions {
+		r.log.Info("Removing resources of dead session", "session", session)
+
+		if err := removeSessionResources(ctx, r.api, session, r.forceImages); err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", session, err))
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(r.dir, session)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("remove heartbeat of session %s: %w", session, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeSessionResources removes the containers, networks, volumes and images
+// labelled with the given session ID. Containers are removed first, as they
+// could be using the other resources. Containers labelled with [LabelReaperSkip]
+// are kept, and so are the resources they are still using. The images are only
+// removed by force if forceImages is true: otherwise, the images tagged in other
+// repositories, or used by other containers, are kept.
+func removeSessionResources(ctx context.Context, api client.APIClient, session string, forceImages bool) error {
+	filters := SessionFilters(session)
+
+	var errs []error
+
+	containers, err := api.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: filters})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("container list: %w", err))
+	}
+	for _, c := range containers.Items {
+		if c.Labels[LabelReaperSkip] == "true" {
+			continue
+		}
+		if _, err := api.ContainerRemove(ctx, c.ID, client.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); ignoreRemoveErr(err) != nil {
+			errs = append(errs, fmt.Errorf("container remove %s: %w", c.ID, err))
+		}
+	}
+
+	networks, err := api.NetworkList(ctx, client.NetworkListOptions{Filters: filters})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("network list: %w", err))
+	}
+	for _, n := range networks.Items {
+		if _, err := api.NetworkRemove(ctx, n.ID, client.NetworkRemoveOptions{}); ignoreRemoveErr(err) != nil {
+			errs = append(errs, fmt.Errorf("network remove %s: %w", n.Name, err))
+		}
+	}
+
+	volumes, err := api.VolumeList(ctx, client.VolumeListOptions{Filters: filters})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("volume list: %w", err))
+	}
+	for _, v := range volumes.Items {
+		if _, err := api.VolumeRemove(ctx, v.Name, client.VolumeRemoveOptions{Force: true}); ignoreRemoveErr(err) != nil {
+			errs = append(errs, fmt.Errorf("volume remove %s: %w", v.Name, err))
+		}
+	}
+
+	images, err := api.ImageList(ctx, client.ImageListOptions{Filters: filters})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("image list: %w", err))
+	}
+	for _, img := range images.Items {
+		if _, err := api.ImageRemove(ctx, img.ID, client.ImageRemoveOptions{Force: forceImages, PruneChildren: true}); ignoreRemoveErr(err) != nil {
+			errs = append(errs, fmt.Errorf("image remove %s: %w", img.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ignoreRemoveErr returns nil if err is a not found error, as the resource could have
+// been removed in the meantime, or if the resource is still in use, e.g. by a container
+// labelled with [LabelReaperSkip].
+func ignoreRemoveErr(err error) error {
+	if errdefs.IsNotFound(err) || errdefs.IsConflict(err) || errdefs.IsPermissionDenied(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// reaperMockCli is a mock implementation of client.APIClient, which returns a single
+// resource of each type for the session in the label filter, and records the removals.
+type reaperMockCli struct {
+	mockCli
+
+	sessions []string
+	removed  []string
+
+	imageRemoveOptions client.ImageRemoveOptions
+}
+
+func (m *reaperMockCli) DaemonHost() string {
+	return "tcp://reaper:2375"
+}
+
+// session returns the session of the label filter.
+func (m *reaperMockCli) session(filters client.Filters) string {
+	for label := range filters["label"] {
+		if s, ok := strings.CutPrefix(label, LabelSessionID+"="); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func (m *reaperMockCli) ContainerList(_ context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	m.sessions = append(m.sessions, m.session(options.Filters))
+	return client.ContainerListResult{Items: []container.Summary{
+		{ID: "container-" + m.session(options.Filters)},
+		{ID: "reused-" + m.session(options.Filters), Labels: map[string]string{LabelReaperSkip: "true"}},
+	}}, nil
+}
+
+func (m *reaperMockCli) ContainerRemove(_ context.Context, id string, _ client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	m.removed = append(m.removed, id)
+	return client.ContainerRemoveResult{}, nil
+}
+
+func (m *reaperMockCli) NetworkList(_ context.Context, options client.NetworkListOptions) (client.NetworkListResult, error) {
+	return client.NetworkListResult{Items: []network.Summary{{Network: network.Network{ID: "network-" + m.session(options.Filters)}}}}, nil
+}
+
+func (m *reaperMockCli) NetworkRemove(_ context.Context, id string, _ client.NetworkRemoveOptions) (client.NetworkRemoveResult, error) {
+	m.removed = append(m.removed, id)
+	return client.NetworkRemoveResult{}, nil
+}
+
+func (m *reaperMockCli) VolumeList(_ context.Context, options client.VolumeListOptions) (client.VolumeListResult, error) {
+	return client.VolumeListResult{Items: []volume.Volume{{Name: "volume-" + m.session(options.Filters)}}}, nil
+}
+
+func (m *reaperMockCli) VolumeRemove(_ context.Context, id string, _ client.VolumeRemoveOptions) (client.VolumeRemoveResult, error) {
+	m.removed = append(m.removed, id)
+	return client.VolumeRemoveResult{}, nil
+}
+
+func (m *reaperMockCli) ImageList(_ context.Context, options client.ImageListOptions) (client.ImageListResult, error) {
+	return client.ImageListResult{Items: []image.Summary{{ID: "image-" + m.session(options.Filters)}}}, nil
+}
+
+func (m *reaperMockCli) ImageRemove(_ context.Context, id string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
+	m.removed = append(m.removed, id)
+	m.imageRemoveOptions = options
+	return client.ImageRemoveResult{}, nil
+}
+
+func TestReaper(t *testing.T) {
+	now := time.Now()
+
+	m := &reaperMockCli{}
+	r := &reaper{
+		api:       m,
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		dir:       t.TempDir(),
+		sessionID: "current",
+		ttl:       time.Minute,
+		now:       func() time.Time { return now },
+		alive:     func(pid int) bool { return pid == 100 },
+	}
+
+	// writeHeartbeat writes the heartbeat of a session, with the given PID, at the given time.
+	writeHeartbeat := func(session, pid string, at time.Time) {
+		path := filepath.Join(r.dir, session)
+		require.NoError(t, os.WriteFile(path, []byte(pid), 0o644))
+		require.NoError(t, os.Chtimes(path, at, at))
+	}
+
+	require.NoError(t, r.beat())
+	require.FileExists(t, filepath.Join(r.dir, "current"))
+	pid, ok := r.heartbeatPID("current")
+	require.True(t, ok)
+	require.Equal(t, os.Getpid(), pid)
+
+	writeHeartbeat("alive", "200", now.Add(-10*time.Second))
+	writeHeartbeat("dead", "200", now.Add(-2*time.Minute))
+	writeHeartbeat("no-pid", "", now.Add(-2*time.Minute))
+
+	// the sessions whose process is still running are not reaped, even if their heartbeat
+	// expired, e.g. because the machine slept
+	writeHeartbeat("asleep", "100", now.Add(-2*time.Minute))
+
+	// the current session is never reaped, even if its heartbeat expired
+	writeHeartbeat("current", "200", now.Add(-2*time.Minute))
+
+	sessions, err := r.deadSessions()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"dead", "no-pid"}, sessions)
+
+	require.NoError(t, r.sweep(context.Background()))
+	require.ElementsMatch(t, []string{"dead", "no-pid"}, m.sessions)
+	require.Equal(t, []string{
+		"container-dead", "network-dead", "volume-dead", "image-dead",
+		"container-no-pid", "network-no-pid", "volume-no-pid", "image-no-pid",
+	}, m.removed)
+	require.False(t, m.imageRemoveOptions.Force)
+
+	require.NoFileExists(t, filepath.Join(r.dir, "dead"))
+	require.FileExists(t, filepath.Join(r.dir, "alive"))
+	require.FileExists(t, filepath.Join(r.dir, "asleep"))
+
+	t.Run("force-images", func(t *testing.T) {
+		m := &reaperMockCli{}
+		require.NoError(t, removeSessionResources(context.Background(), m, "dead", true))
+		require.True(t, m.imageRemoveOptions.Force)
+	})
+
+	t.Run("no-sessions-dir", func(t *testing.T) {
+		r := &reaper{dir: filepath.Join(t.TempDir(), "missing"), now: time.Now}
+
+		sessions, err := r.deadSessions()
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+}
+
+func TestReaper_heartbeat(t *testing.T) {
+	r := &reaper{
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		dir:       t.TempDir(),
+		sessionID: "current",
+		now:       time.Now,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.heartbeat(ctx, 10*time.Millisecond)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(r.dir, "current"))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat not stopped")
+	}
+}
+
+func TestSDKClient_stopReaper(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	t.Run("started", func(t *testing.T) {
+		c := &sdkClient{APIClient: &reaperMockCli{}, log: slog.New(slog.NewTextHandler(io.Discard, nil)), sessionID: "started"}
+
+		c.startReaper()
+		require.NotNil(t, c.reaperCancel)
+
+		c.stopReaper()
+	})
+
+	t.Run("not-started", func(t *testing.T) {
+		c := &sdkClient{APIClient: &reaperMockCli{}, log: slog.New(slog.NewTextHandler(io.Discard, nil)), sessionID: "not-started"}
+
+		// the reaper is never started once the client is closed
+		c.stopReaper()
+		c.startReaper()
+		require.Nil(t, c.reaperCancel)
+		require.NoFileExists(t, filepath.Join(sessionsDir("tcp://reaper:2375"), "not-started"))
+	})
+}
+
+func TestNew_reaper(t *testing.T) {
+	t.Run("disabled/docker-api", func(t *testing.T) {
+		sdk := newMockClient(t, &reaperMockCli{})
+		require.True(t, sdk.(*sdkClient).reaperDisabled)
+	})
+
+	t.Run("disabled/option", func(t *testing.T) {
+		c := &sdkClient{}
+		require.NoError(t, WithoutReaper().Apply(c))
+		require.True(t, c.reaperDisabled)
+	})
+
+	t.Run("force-images/option", func(t *testing.T) {
+		c := &sdkClient{}
+		require.NoError(t, WithReaperForceImageRemoval().Apply(c))
+		require.True(t, c.reaperForceImages)
+	})
+
+	t.Run("disabled/env", func(t *testing.T) {
+		t.Setenv(EnvReaperDisabled, "true")
+		require.True(t, reaperDisabledFromEnv())
+
+		t.Setenv(EnvReaperDisabled, "false")
+		require.False(t, reaperDisabledFromEnv())
+	})
+}
+
+func TestSessionsDir(t *testing.T) {
+	// the sessions of different docker hosts are kept apart
+	require.Equal(t, sessionsDir("unix:///var/run/docker.sock"), sessionsDir("unix:///var/run/docker.sock"))
+	require.NotEqual(t, sessionsDir("unix:///var/run/docker.sock"), sessionsDir("tcp://remote:2375"))
+	require.Equal(t, filepath.Join(os.TempDir(), "docker-go-sdk", "sessions"), filepath.Dir(sessionsDir("tcp://remote:2375")))
+}
+
+func TestProcessAlive(t *testing.T) {
+	require.True(t, processAlive(os.Getpid()))
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	require.False(t, processAlive(cmd.Process.Pid))
+}
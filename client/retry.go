@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
+)
+
+// defaultRetryAttempts is the number of attempts of the API calls, if the policy does not set it.
+const defaultRetryAttempts = 3
+
+// RetryPolicy configures how the client retries the idempotent API calls failing with
+// transient errors, e.g. a reset connection to a busy docker daemon, with an exponential backoff.
+// Permanent errors, see [IsPermanentClientError], are not retried. See [WithRetryPolicy].
+//
+// The retried calls are the ones that can be safely repeated:
+//   - ContainerInspect, ContainerList, ContainerLogs and ContainerStart
+//   - ImageInspect and ImageList
+//   - NetworkInspect and NetworkList
+//   - VolumeInspect and VolumeList
+//   - Info and ServerVersion
+//
+// The zero value makes up to 3 attempts, waiting 100ms and 200ms between them.
+type RetryPolicy struct {
+	// MaxElapsedTime is the maximum time to retry a call.
+	// Zero means no limit other than the context and MaxAttempts.
+	MaxElapsedTime time.Duration
+
+	// MaxAttempts is the maximum number of attempts of a call. Zero means 3.
+	MaxAttempts int
+
+	// InitialInterval is the wait before the first retry. Zero means 100ms.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum wait between retries. Zero means no maximum.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the wait grows by after each retry. Zero means 2,
+	// otherwise it must be at least 1, which retries at a constant interval.
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to the given fraction of it, in the range [0, 1].
+	Jitter float64
+}
+
+// validate returns an error if the policy is invalid.
+func (p RetryPolicy) validate() error {
+	switch {
+	case p.MaxElapsedTime < 0:
+		return errors.New("max elapsed time is negative")
+	case p.MaxAttempts < 0:
+		return errors.New("max attempts is negative")
+	case p.InitialInterval < 0:
+		return errors.New("initial interval is negative")
+	case p.MaxInterval < 0:
+		return errors.New("max interval is negative")
+	case p.Multiplier != 0 && p.Multiplier < 1:
+		return fmt.Errorf("multiplier %v is less than 1", p.Multiplier)
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("jitter %v is not in the range [0, 1]", p.Jitter)
+	}
+	return nil
+}
+
+// retryable reports whether the error of a call is transient, so the call can be retried.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return !IsPermanentClientError(err)
+}
+
+// retryCall calls the given API call, retrying it following the retry policy of the client, if any.
+// The error of the last attempt is returned as is, so the errors of the docker client can still be inspected.
+func retryCall[T any](ctx context.Context, c *sdkClient, operation string, call func() (T, error)) (T, error) {
+	result, err := call()
+	if c.retryPolicy == nil || err == nil {
+		return result, err
+	}
+
+	p := c.retryPolicy
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultRetryAttempts
+	}
+
+	start := time.Now()
+	b := newExponentialBackoff(p.InitialInterval, p.MaxInterval, p.Multiplier, p.Jitter)
+
+	for attempt := 1; err != nil && retryable(ctx, err); attempt++ {
+		if attempt >= maxAttempts {
+			c.log.Warn("docker API call failed, giving up", "operation", operation, "attempts", attempt, "error", err)
+			return result, err
+		}
+
+		wait := b.next()
+		if p.MaxElapsedTime > 0 && time.Since(start)+wait > p.MaxElapsedTime {
+			c.log.Warn("docker API call failed, giving up", "operation", operation, "attempts", attempt, "error", err)
+			return result, err
+		}
+
+		c.log.Warn("docker API call failed, retrying", "operation", operation, "attempt", attempt, "wait", wait, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(wait):
+		}
+
+		result, err = call()
+	}
+
+	return result, err
+}
+
+// ContainerInspect returns the container information, retrying the transient errors.
+func (c *sdkClient) ContainerInspect(ctx context.Context, containerID string, options client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
+	return retryCall(ctx, c, "ContainerInspect", func() (client.ContainerInspectResult, error) {
+		return c.APIClient.ContainerInspect(ctx, containerID, options)
+	})
+}
+
+// ContainerList returns the list of containers in the docker host, retrying the transient errors.
+// The containers are filtered by label in the client too for Podman.
+func (c *sdkClient) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	result, err := retryCall(ctx, c, "ContainerList", func() (client.ContainerListResult, error) {
+		return c.APIClient.ContainerList(ctx, options)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = filterByLabels(ctx, c, options.Filters, result.Items, func(s container.Summary) map[string]string {
+		return s.Labels
+	})
+	return result, nil
+}
+
+// ContainerLogs returns the logs generated by a container, retrying the transient errors
+// before the logs are streamed.
+func (c *sdkClient) ContainerLogs(ctx context.Context, containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+	return retryCall(ctx, c, "ContainerLogs", func() (client.ContainerLogsResult, error) {
+		return c.APIClient.ContainerLogs(ctx, containerID, options)
+	})
+}
+
+// ContainerStart sends a request to the docker daemon to start a container, retrying the transient errors.
+// Starting a container is idempotent: the daemon does nothing if the container is already running.
+func (c *sdkClient) ContainerStart(ctx context.Context, containerID string, options client.ContainerStartOptions) (client.ContainerStartResult, error) {
+	return retryCall(ctx, c, "ContainerStart", func() (client.ContainerStartResult, error) {
+		return c.APIClient.ContainerStart(ctx, containerID, options)
+	})
+}
+
+// ImageInspect returns the image information, retrying the transient errors.
+func (c *sdkClient) ImageInspect(ctx context.Context, imageID string, options ...client.ImageInspectOption) (client.ImageInspectResult, error) {
+	return retryCall(ctx, c, "ImageInspect", func() (client.ImageInspectResult, error) {
+		return c.APIClient.ImageInspect(ctx, imageID, options...)
+	})
+}
+
+// ImageList returns the list of images in the docker host, retrying the transient errors.
+// The images are filtered by label in the client too for Podman.
+func (c *sdkClient) ImageList(ctx context.Context, options client.ImageListOptions) (client.ImageListResult, error) {
+	result, err := retryCall(ctx, c, "ImageList", func() (client.ImageListResult, error) {
+		return c.APIClient.ImageList(ctx, options)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = filterByLabels(ctx, c, options.Filters, result.Items, func(s image.Summary) map[string]string {
+		return s.Labels
+	})
+	return result, nil
+}
+
+// NetworkInspect returns the information for a specific network configured in the docker host,
+// retrying the transient errors.
+func (c *sdkClient) NetworkInspect(ctx context.Context, networkID string, options client.NetworkInspectOptions) (client.NetworkInspectResult, error) {
+	return retryCall(ctx, c, "NetworkInspect", func() (client.NetworkInspectResult, error) {
+		return c.APIClient.NetworkInspect(ctx, networkID, options)
+	})
+}
+
+// NetworkList returns the list of networks configured in the docker host, retrying the transient errors.
+// The networks are filtered by label in the client too for Podman.
+func (c *sdkClient) NetworkList(ctx context.Context, options client.NetworkListOptions) (client.NetworkListResult, error) {
+	result, err := retryCall(ctx, c, "NetworkList", func() (client.NetworkListResult, error) {
+		return c.APIClient.NetworkList(ctx, options)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = filterByLabels(ctx, c, options.Filters, result.Items, func(s network.Summary) map[string]string {
+		return s.Labels
+	})
+	return result, nil
+}
+
+// VolumeInspect returns the information about a specific volume in the docker host,
+// retrying the transient errors.
+func (c *sdkClient) VolumeInspect(ctx context.Context, volumeID string, options client.VolumeInspectOptions) (client.VolumeInspectResult, error) {
+	return retryCall(ctx, c, "VolumeInspect", func() (client.VolumeInspectResult, error) {
+		return c.APIClient.VolumeInspect(ctx, volumeID, options)
+	})
+}
+
+// VolumeList returns the volumes configured in the docker host, retrying the transient errors.
+// The volumes are filtered by label in the client too for Podman.
+func (c *sdkClient) VolumeList(ctx context.Context, options client.VolumeListOptions) (client.VolumeListResult, error) {
+	result, err := retryCall(ctx, c, "VolumeList", func() (client.VolumeListResult, error) {
+		return c.APIClient.VolumeList(ctx, options)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = filterByLabels(ctx, c, options.Filters, result.Items, func(v volume.Volume) map[string]string {
+		return v.Labels
+	})
+	return result, nil
+}
+
+// ServerVersion returns information of the docker server host, retrying the transient errors.
+func (c *sdkClient) ServerVersion(ctx context.Context, options client.ServerVersionOptions) (client.ServerVersionResult, error) {
+	return retryCall(ctx, c, "ServerVersion", func() (client.ServerVersionResult, error) {
+		return c.APIClient.ServerVersion(ctx, options)
+	})
+}
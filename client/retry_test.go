@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// retryMockCli is a mock implementation of client.APIClient, whose container inspects
+// and lists fail with the given error until the given number of calls.
+type retryMockCli struct {
+	mockCli
+
+	failures int
+	err      error
+	calls    int
+}
+
+func (m *retryMockCli) ContainerInspect(_ context.Context, containerID string, _ client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return client.ContainerInspectResult{}, m.err
+	}
+	return client.ContainerInspectResult{Container: container.InspectResponse{ID: containerID}}, nil
+}
+
+func (m *retryMockCli) ContainerList(_ context.Context, _ client.ContainerListOptions) (client.ContainerListResult, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return client.ContainerListResult{}, m.err
+	}
+	return client.ContainerListResult{Items: []container.Summary{{ID: "0123456789ab"}}}, nil
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	errReset := errors.New("read: connection reset by peer")
+
+	newClient := func(t *testing.T, m *retryMockCli, policy RetryPolicy, opts ...ClientOption) SDKClient {
+		t.Helper()
+
+		return newMockClient(t, m, append(opts, WithRetryPolicy(policy))...)
+	}
+
+	t.Run("retry-until-success", func(t *testing.T) {
+		var logs bytes.Buffer
+		m := &retryMockCli{failures: 2, err: errReset}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: time.Millisecond},
+			WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+		inspect, err := cli.ContainerInspect(context.Background(), "0123456789ab", client.ContainerInspectOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "0123456789ab", inspect.Container.ID)
+		require.Equal(t, 3, m.calls)
+		require.Contains(t, logs.String(), "docker API call failed, retrying")
+		require.Contains(t, logs.String(), "operation=ContainerInspect")
+	})
+
+	t.Run("max-attempts", func(t *testing.T) {
+		m := &retryMockCli{failures: 5, err: errReset}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: time.Millisecond, MaxAttempts: 4})
+
+		_, err := cli.ContainerList(context.Background(), client.ContainerListOptions{})
+		require.ErrorIs(t, err, errReset)
+		require.Equal(t, 4, m.calls)
+	})
+
+	t.Run("default-attempts", func(t *testing.T) {
+		m := &retryMockCli{failures: 5, err: errReset}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: time.Millisecond})
+
+		_, err := cli.ContainerList(context.Background(), client.ContainerListOptions{})
+		require.ErrorIs(t, err, errReset)
+		require.Equal(t, defaultRetryAttempts, m.calls)
+	})
+
+	t.Run("max-elapsed-time", func(t *testing.T) {
+		m := &retryMockCli{failures: 100, err: errReset}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: 20 * time.Millisecond, Multiplier: 1, MaxAttempts: 100, MaxElapsedTime: 50 * time.Millisecond})
+
+		_, err := cli.ContainerInspect(context.Background(), "0123456789ab", client.ContainerInspectOptions{})
+		require.ErrorIs(t, err, errReset)
+		require.Less(t, m.calls, 5)
+	})
+
+	t.Run("permanent-error", func(t *testing.T) {
+		m := &retryMockCli{failures: 1, err: errdefs.ErrNotFound.WithMessage("no such container")}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: time.Millisecond})
+
+		_, err := cli.ContainerInspect(context.Background(), "0123456789ab", client.ContainerInspectOptions{})
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+		require.Equal(t, 1, m.calls)
+	})
+
+	t.Run("context-done", func(t *testing.T) {
+		m := &retryMockCli{failures: 100, err: errReset}
+		cli := newClient(t, m, RetryPolicy{InitialInterval: time.Hour, MaxAttempts: 100})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := cli.ContainerInspect(ctx, "0123456789ab", client.ContainerInspectOptions{})
+		require.ErrorIs(t, err, errReset)
+		require.Equal(t, 1, m.calls)
+	})
+
+	t.Run("no-policy", func(t *testing.T) {
+		m := &retryMockCli{failures: 1, err: errReset}
+		cli := newMockClient(t, m)
+
+		_, err := cli.ContainerInspect(context.Background(), "0123456789ab", client.ContainerInspectOptions{})
+		require.ErrorIs(t, err, errReset)
+		require.Equal(t, 1, m.calls)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, policy := range map[string]RetryPolicy{
+			"negative-max-attempts": {MaxAttempts: -1},
+			"multiplier-below-one":  {Multiplier: 0.5},
+			"jitter-out-of-range":   {Jitter: 1.5},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := New(context.Background(), WithDockerAPI(&retryMockCli{}), WithRetryPolicy(policy))
+				require.ErrorContains(t, err, "retry policy")
+			})
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/moby/moby/client"
+)
+
+// newSessionID returns a new random session ID.
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
+
+// SessionID returns the ID of the session of the client, which is added
+// to all the resources created by the client, using the [LabelSessionID] label.
+func (c *sdkClient) SessionID() string {
+	return c.sessionID
+}
+
+// SessionFilters returns the filters to list the resources created
+// by the client with the given session ID.
+func SessionFilters(sessionID string) client.Filters {
+	return make(client.Filters).Add("label", LabelSessionID+"="+sessionID)
+}
+
+// addSDKLabels adds the SDK labels, the session ID and the run metadata of the
+// client to target, returning it, or a new map if target is nil.
+func (c *sdkClient) addSDKLabels(target map[string]string) map[string]string {
+	if target == nil {
+		target = make(map[string]string)
+	}
+
+	AddSDKLabels(target)
+
+	for k, v := range c.runMetadata {
+		target[LabelRunMetadata+"."+k] = v
+	}
+
+	if c.sessionID != "" {
+		target[LabelSessionID] = c.sessionID
+	}
+
+	return target
+}
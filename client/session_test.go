@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionMockCli is a mock implementation of client.APIClient, which records
+// the labels of the created resources.
+type sessionMockCli struct {
+	mockCli
+
+	labels map[string]string
+}
+
+func (m *sessionMockCli) ContainerCreate(_ context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	m.labels = options.Config.Labels
+	return client.ContainerCreateResult{}, nil
+}
+
+func (m *sessionMockCli) VolumeCreate(_ context.Context, options client.VolumeCreateOptions) (client.VolumeCreateResult, error) {
+	m.labels = options.Labels
+	return client.VolumeCreateResult{}, nil
+}
+
+func TestNew_session(t *testing.T) {
+	t.Run("session-id", func(t *testing.T) {
+		m := &sessionMockCli{}
+		sdk := newMockClient(t, m)
+		require.Len(t, sdk.SessionID(), 32)
+		require.NotEqual(t, sdk.SessionID(), newMockClient(t, m).SessionID())
+
+		_, err := sdk.ContainerCreate(context.Background(), client.ContainerCreateOptions{Config: &container.Config{}})
+		require.NoError(t, err)
+		require.Equal(t, sdk.SessionID(), m.labels[LabelSessionID])
+		require.Equal(t, "true", m.labels[LabelBase])
+	})
+
+	t.Run("run-metadata", func(t *testing.T) {
+		m := &sessionMockCli{}
+		sdk := newMockClient(t, m, WithRunMetadata(map[string]string{"job": "unit-tests"}), WithRunMetadata(map[string]string{"attempt": "2"}))
+
+		_, err := sdk.VolumeCreate(context.Background(), client.VolumeCreateOptions{Labels: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+		require.Equal(t, "bar", m.labels["foo"])
+		require.Equal(t, sdk.SessionID(), m.labels[LabelSessionID])
+		require.Equal(t, "unit-tests", m.labels[LabelRunMetadata+".job"])
+		require.Equal(t, "2", m.labels[LabelRunMetadata+".attempt"])
+	})
+
+	t.Run("run-metadata/empty-key", func(t *testing.T) {
+		_, err := New(context.Background(), WithDockerAPI(&sessionMockCli{}), WithRunMetadata(map[string]string{"": "value"}))
+		require.Error(t, err)
+	})
+
+	t.Run("session-filters", func(t *testing.T) {
+		filters := SessionFilters("abc")
+		require.True(t, filters["label"][LabelSessionID+"=abc"])
+	})
+}
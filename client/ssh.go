@@ -0,0 +1,233 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/errdefs"
+)
+
+// sshPlaceholderHost is the host of the Docker API when dialing it through SSH.
+// The connections are opened by the SSH dialer, so the host is not resolved, but
+// the Docker client requires an HTTP host to build the URLs of the requests.
+const sshPlaceholderHost = "http://docker.example.com"
+
+// SSHDialer opens connections through SSH to the remote host of an ssh:// Docker host,
+// e.g. ssh://user@buildbox. The client uses the system ssh binary by default, see [NewSSHDialer],
+// but a pure-Go implementation can be provided with the [WithSSHDialer] option.
+type SSHDialer interface {
+	// DialDaemon returns a connection to the API of the Docker daemon of the remote host.
+	DialDaemon(ctx context.Context) (net.Conn, error)
+
+	// DialRemote returns a connection to the given address, dialed from the remote host,
+	// e.g. "127.0.0.1:8080" to connect to a port published by a container.
+	DialRemote(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// systemSSHDialer is an [SSHDialer] using the system ssh binary, running
+// "docker system dial-stdio" on the remote host to connect to its Docker daemon.
+type systemSSHDialer struct {
+	// args are the arguments of the ssh command, before the destination.
+	args []string
+
+	// destination is the host to connect to.
+	destination string
+
+	// socketPath is the path of the socket of the Docker daemon on the remote host.
+	// If empty, the default socket of the remote docker CLI is used.
+	socketPath string
+}
+
+// NewSSHDialer returns an [SSHDialer] using the system ssh binary to connect to the
+// given ssh:// Docker host, in the form ssh://[user@]host[:port][/path/to/docker.sock].
+// The docker CLI must be installed on the remote host. The extra arguments are passed
+// to the ssh command before the destination, e.g. "-i", "~/.ssh/buildbox".
+// As the ssh command runs in batch mode, the authentication must not require a prompt,
+// e.g. using an SSH agent or a key without passphrase.
+func NewSSHDialer(host string, extraArgs ...string) (SSHDialer, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("parse ssh host: %w", err)
+	}
+
+	if u.Scheme != "ssh" {
+		return nil, errdefs.ErrInvalidArgument.WithMessage(fmt.Sprintf("host %q is not an ssh:// host", host))
+	}
+
+	if u.Hostname() == "" {
+		return nil, errdefs.ErrInvalidArgument.WithMessage(fmt.Sprintf("ssh host %q has no hostname", host))
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, errdefs.ErrInvalidArgument.WithMessage(fmt.Sprintf("ssh host %q must not have a query or fragment", host))
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		return nil, errdefs.ErrInvalidArgument.WithMessage("ssh host must not have a password, use an SSH agent or key instead")
+	}
+
+	d := &systemSSHDialer{
+		args:        []string{"-o", "ConnectTimeout=30", "-o", "BatchMode=yes", "-T"},
+		destination: u.Hostname(),
+		socketPath:  u.Path,
+	}
+
+	if user := u.User.Username(); user != "" {
+		d.args = append(d.args, "-l", user)
+	}
+
+	if port := u.Port(); port != "" {
+		d.args = append(d.args, "-p", port)
+	}
+
+	d.args = append(d.args, extraArgs...)
+
+	return d, nil
+}
+
+// DialDaemon implements [SSHDialer].
+func (d *systemSSHDialer) DialDaemon(ctx context.Context) (net.Conn, error) {
+	remoteCmd := []string{"docker"}
+	if d.socketPath != "" {
+		remoteCmd = append(remoteCmd, "--host", "unix://"+d.socketPath)
+	}
+	remoteCmd = append(remoteCmd, "system", "dial-stdio")
+
+	args := append(append([]string{}, d.args...), "--", d.destination)
+	return newCommandConn(ctx, "ssh", append(args, remoteCmd...)...)
+}
+
+// DialRemote implements [SSHDialer], forwarding the standard input and output
+// of the ssh command to the given address.
+func (d *systemSSHDialer) DialRemote(ctx context.Context, addr string) (net.Conn, error) {
+	args := append(append([]string{}, d.args...), "-W", addr, "--", d.destination)
+	return newCommandConn(ctx, "ssh", args...)
+}
+
+// commandConn is a [net.Conn] backed by the standard input and output of a command.
+// Deadlines are not supported: the command is killed when the connection is closed.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+
+	waitOnce sync.Once
+	waitErr  error
+
+	closeOnce sync.Once
+
+	addr commandAddr
+}
+
+// newCommandConn starts the given command, returning a connection to it.
+// The context is only used to start the command, as the connection outlives it.
+func newCommandConn(ctx context.Context, name string, args ...string) (net.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c := &commandConn{
+		cmd:  exec.Command(name, args...),
+		addr: commandAddr(name + " " + strings.Join(args, " ")),
+	}
+	c.cmd.Stderr = &c.stderr
+
+	var err error
+	if c.stdin, err = c.cmd.StdinPipe(); err != nil {
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+	if c.stdout, err = c.cmd.StdoutPipe(); err != nil {
+		return nil, fmt.Errorf("stdout pipe: %w", err)
+	}
+
+	if err := c.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", name, err)
+	}
+
+	return c, nil
+}
+
+// wait waits for the command to exit, returning its error along with its standard error.
+func (c *commandConn) wait() error {
+	c.waitOnce.Do(func() {
+		if err := c.cmd.Wait(); err != nil {
+			c.waitErr = fmt.Errorf("%s: %w: %s", c.cmd.Path, err, strings.TrimSpace(c.stderr.String()))
+		}
+	})
+	return c.waitErr
+}
+
+// Read implements [net.Conn]. When the command exits with an error, it's returned
+// instead of [io.EOF], so that failures like the authentication are reported.
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if errors.Is(err, io.EOF) {
+		if waitErr := c.wait(); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// Write implements [net.Conn].
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// Close implements [net.Conn], killing the command.
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		_ = c.stdin.Close()
+		_ = c.cmd.Process.Kill()
+		_ = c.wait()
+	})
+	return nil
+}
+
+// LocalAddr implements [net.Conn].
+func (c *commandConn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+// RemoteAddr implements [net.Conn].
+func (c *commandConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// SetDeadline implements [net.Conn]. Deadlines are not supported.
+func (c *commandConn) SetDeadline(_ time.Time) error {
+	return nil
+}
+
+// SetReadDeadline implements [net.Conn]. Deadlines are not supported.
+func (c *commandConn) SetReadDeadline(_ time.Time) error {
+	return nil
+}
+
+// SetWriteDeadline implements [net.Conn]. Deadlines are not supported.
+func (c *commandConn) SetWriteDeadline(_ time.Time) error {
+	return nil
+}
+
+// commandAddr is the address of a [commandConn], which is the command line.
+type commandAddr string
+
+// Network implements [net.Addr].
+func (a commandAddr) Network() string {
+	return "command"
+}
+
+// String implements [net.Addr].
+func (a commandAddr) String() string {
+	return string(a)
+}
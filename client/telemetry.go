@@ -0,0 +1,175 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/go-connections/sockets"
+	"github.com/moby/moby/client"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// AttributeEndpoint is the attribute of the spans and metrics of the API calls with
+	// their endpoint, where the IDs and names of the resources are replaced by placeholders,
+	// e.g. "/containers/{id}/json".
+	AttributeEndpoint = attribute.Key("docker.endpoint")
+
+	// AttributeContainerID is the attribute of the spans with the ID of the container.
+	AttributeContainerID = attribute.Key("docker.container.id")
+
+	// AttributeImageName is the attribute of the spans with the name of the image.
+	AttributeImageName = attribute.Key("docker.image.name")
+)
+
+// apiVersionPrefix matches the API version prefix of the paths of the API calls, e.g. "/v1.52".
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// collectionEndpoints are the endpoints of the resources that are not followed by an ID or name, by resource.
+// The endpoints of any other resource are not templated.
+var collectionEndpoints = map[string][]string{
+	"containers": {"json", "create", "prune"},
+	"exec":       {},
+	"images":     {"json", "create", "load", "search", "prune", "get"},
+	"networks":   {"create", "prune"},
+	"volumes":    {"create", "prune"},
+}
+
+// imageSubresources are the endpoints following the name of an image, which can contain slashes.
+var imageSubresources = []string{"json", "history", "push", "tag", "get"}
+
+// resourceAttributes are the attributes of the spans with the ID or name of the resource, by resource.
+var resourceAttributes = map[string]attribute.Key{
+	"containers": AttributeContainerID,
+	"exec":       attribute.Key("docker.exec.id"),
+	"images":     AttributeImageName,
+	"networks":   attribute.Key("docker.network.id"),
+	"volumes":    attribute.Key("docker.volume.name"),
+}
+
+// TracerProvider returns the tracer provider of the client, set with the [WithTracerProvider] option,
+// or the global tracer provider.
+func (c *sdkClient) TracerProvider() trace.TracerProvider {
+	if c.tracerProvider != nil {
+		return c.tracerProvider
+	}
+	return otel.GetTracerProvider()
+}
+
+// telemetryEnabled reports whether the API calls are instrumented, i.e. whether a tracer
+// or a meter provider is set.
+func (c *sdkClient) telemetryEnabled() bool {
+	return c.tracerProvider != nil || c.meterProvider != nil
+}
+
+// telemetryOpts returns the docker client options needed to instrument the API calls
+// with the tracer and meter providers of the client, if any: an HTTP client like the
+// default one of the docker client, so that its transport can be instrumented once the
+// docker client is created. The options setting another HTTP client replace it.
+func (c *sdkClient) telemetryOpts() ([]client.Opt, error) {
+	if !c.telemetryEnabled() {
+		return nil, nil
+	}
+
+	hostURL, err := client.ParseHostURL(client.DefaultDockerHost)
+	if err != nil {
+		return nil, fmt.Errorf("parse default docker host: %w", err)
+	}
+
+	tr := &http.Transport{MaxIdleConns: 6, IdleConnTimeout: 30 * time.Second}
+	if err := sockets.ConfigureTransport(tr, hostURL.Scheme, hostURL.Host); err != nil {
+		return nil, fmt.Errorf("configure transport: %w", err)
+	}
+
+	return []client.Opt{c.withHTTPClient(&http.Client{Transport: tr, CheckRedirect: client.CheckRedirect})}, nil
+}
+
+// instrument replaces the instrumentation of the docker client with the one of the client,
+// wrapping the given transport of its HTTP client, if the API calls are instrumented.
+func (c *sdkClient) instrument(transport http.RoundTripper) {
+	if !c.telemetryEnabled() || c.httpClient == nil {
+		return
+	}
+
+	opts := []otelhttp.Option{
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			endpoint, _, _ := apiEndpoint(r.URL.Path)
+			return r.Method + " " + endpoint
+		}),
+		otelhttp.WithMetricAttributesFn(requestAttributes),
+	}
+
+	if c.tracerProvider != nil {
+		opts = append(opts, otelhttp.WithTracerProvider(c.tracerProvider))
+	}
+
+	if c.meterProvider != nil {
+		opts = append(opts, otelhttp.WithMeterProvider(c.meterProvider))
+	}
+
+	c.httpClient.Transport = otelhttp.NewTransport(&spanAttributesTransport{base: transport}, opts...)
+}
+
+// spanAttributesTransport annotates the spans of the API calls with their endpoint, and the ID
+// or name of their resource. It's wrapped by the instrumented transport, which starts the spans,
+// so that the calls are annotated before they're sent, even if they fail.
+type spanAttributesTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip annotates the span of the request, and sends it with the base transport.
+func (t *spanAttributesTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	endpoint, resource, id := apiEndpoint(r.URL.Path)
+
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(AttributeEndpoint.String(endpoint))
+	if id != "" {
+		span.SetAttributes(resourceAttributes[resource].String(id))
+	}
+
+	return t.base.RoundTrip(r)
+}
+
+// requestAttributes returns the metric attributes of an API call: its endpoint, as the IDs
+// and names of the resources would be too high-cardinality for the metrics.
+func requestAttributes(r *http.Request) []attribute.KeyValue {
+	endpoint, _, _ := apiEndpoint(r.URL.Path)
+	return []attribute.KeyValue{AttributeEndpoint.String(endpoint)}
+}
+
+// apiEndpoint returns the endpoint of the given path of an API call, without the API version,
+// and replacing the ID or name of the resource by a placeholder, together with the resource and its ID.
+// E.g. "/v1.52/containers/0123456789ab/json" returns "/containers/{id}/json", "containers" and "0123456789ab".
+func apiEndpoint(path string) (endpoint string, resource string, id string) {
+	path = apiVersionPrefix.ReplaceAllString(path, "")
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	resource = segments[0]
+
+	fixed, ok := collectionEndpoints[resource]
+	if !ok || len(segments) < 2 || slices.Contains(fixed, segments[1]) {
+		return path, "", ""
+	}
+
+	if resource == "images" {
+		// image names can contain slashes, e.g. "docker.io/library/nginx:latest"
+		last := len(segments)
+		if len(segments) > 2 && slices.Contains(imageSubresources, segments[last-1]) {
+			last--
+		}
+
+		id = strings.Join(segments[1:last], "/")
+		return "/" + strings.Join(append([]string{resource, "{name}"}, segments[last:]...), "/"), resource, id
+	}
+
+	id = segments[1]
+	segments[1] = "{id}"
+	return "/" + strings.Join(segments, "/"), resource, id
+}
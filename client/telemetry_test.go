@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestApiEndpoint(t *testing.T) {
+	for path, want := range map[string][3]string{
+		"/_ping":                                     {"/_ping", "", ""},
+		"/v1.52/containers/json":                     {"/containers/json", "", ""},
+		"/v1.52/containers/create":                   {"/containers/create", "", ""},
+		"/v1.52/containers/0123456789ab/json":        {"/containers/{id}/json", "containers", "0123456789ab"},
+		"/v1.52/containers/0123456789ab":             {"/containers/{id}", "containers", "0123456789ab"},
+		"/v1.52/exec/abc/start":                      {"/exec/{id}/start", "exec", "abc"},
+		"/v1.52/images/create":                       {"/images/create", "", ""},
+		"/v1.52/images/docker.io/library/nginx/json": {"/images/{name}/json", "images", "docker.io/library/nginx"},
+		"/v1.52/images/nginx:latest":                 {"/images/{name}", "images", "nginx:latest"},
+		"/v1.52/networks/backend":                    {"/networks/{id}", "networks", "backend"},
+		"/v1.52/volumes/prune":                       {"/volumes/prune", "", ""},
+		"/v1.52/info":                                {"/info", "", ""},
+	} {
+		t.Run(path, func(t *testing.T) {
+			endpoint, resource, id := apiEndpoint(path)
+			require.Equal(t, want, [3]string{endpoint, resource, id})
+		})
+	}
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.52")
+			_, _ = w.Write([]byte("OK"))
+		case strings.Contains(r.URL.Path, "/containers/broken/"):
+			// the connection is closed without a response, so the round trip fails
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		case strings.HasSuffix(r.URL.Path, "/json"):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"Id": "0123456789ab"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer daemon.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	sdk, err := New(context.Background(),
+		WithDockerHost("tcp://"+daemon.Listener.Addr().String()),
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+		WithoutReaper(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, tp, sdk.TracerProvider())
+
+	_, err = sdk.ContainerInspect(context.Background(), "0123456789ab", client.ContainerInspectOptions{})
+	require.NoError(t, err)
+
+	_, err = sdk.ContainerInspect(context.Background(), "broken", client.ContainerInspectOptions{})
+	require.Error(t, err)
+
+	inspectSpan := func(t *testing.T, id string) sdktrace.ReadOnlySpan {
+		t.Helper()
+
+		for _, span := range spans.Ended() {
+			if span.Name() == "GET /containers/{id}/json" && slices.Contains(span.Attributes(), AttributeContainerID.String(id)) {
+				return span
+			}
+		}
+		require.FailNow(t, "no span for the inspect of container "+id)
+		return nil
+	}
+
+	t.Run("spans", func(t *testing.T) {
+		inspect := inspectSpan(t, "0123456789ab")
+		require.Contains(t, inspect.Attributes(), AttributeEndpoint.String("/containers/{id}/json"))
+		require.Contains(t, inspect.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+	})
+
+	t.Run("failed-call", func(t *testing.T) {
+		inspect := inspectSpan(t, "broken")
+		require.Contains(t, inspect.Attributes(), AttributeEndpoint.String("/containers/{id}/json"))
+		require.Equal(t, codes.Error, inspect.Status().Code)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+
+		var endpoints []string
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != "http.client.request.duration" {
+					continue
+				}
+				for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					endpoint, _ := dp.Attributes.Value(AttributeEndpoint)
+					endpoints = append(endpoints, endpoint.AsString())
+				}
+			}
+		}
+		require.Contains(t, endpoints, "/containers/{id}/json")
+		require.Contains(t, endpoints, "/_ping")
+	})
+
+	t.Run("nil-provider", func(t *testing.T) {
+		_, err := New(context.Background(), WithTracerProvider(nil))
+		require.ErrorContains(t, err, "tracer provider is nil")
+
+		_, err = New(context.Background(), WithMeterProvider(nil))
+		require.ErrorContains(t, err, "meter provider is nil")
+	})
+}
@@ -0,0 +1,187 @@
+package client_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// testCertificate is a PEM-encoded certificate with its key.
+type testCertificate struct {
+	cert, key []byte
+	parsed    *x509.Certificate
+	signer    *ecdsa.PrivateKey
+}
+
+// newTestCertificate returns a certificate from the given template, signed by the given parent,
+// or self-signed if the parent is nil.
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.parsed, parent.signer
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	parsed, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		parsed: parsed,
+		signer: key,
+	}
+}
+
+// newMutualTLSDaemon returns a docker daemon answering the ping requests over TLS, requiring a client
+// certificate signed by the returned CA, and a client certificate signed by it.
+func newMutualTLSDaemon(t *testing.T) (srv *httptest.Server, ca, clientCert *testCertificate) {
+	t.Helper()
+
+	ca = newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+
+	serverCert := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-daemon"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+
+	clientCert = newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	keyPair, err := tls.X509KeyPair(serverCert.cert, serverCert.key)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.parsed)
+
+	srv = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.52")
+		if strings.HasSuffix(r.URL.Path, "/_ping") {
+			_, _ = w.Write([]byte("OK"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv, ca, clientCert
+}
+
+func TestNew_dockerContextTLS(t *testing.T) {
+	ctx := context.Background()
+
+	srv, ca, clientCert := newMutualTLSDaemon(t)
+	host := "tcp://" + srv.Listener.Addr().String()
+
+	failFast := client.WithHealthCheckPolicy(client.HealthCheckPolicy{MaxAttempts: 1})
+
+	t.Run("mutual-tls", func(t *testing.T) {
+		dockercontext.SetupTestDockerContexts(t, 1, 1)
+
+		_, err := dockercontext.New("remote-tls", dockercontext.WithHost(host),
+			dockercontext.WithTLSCA(ca.cert),
+			dockercontext.WithTLSCert(clientCert.cert),
+			dockercontext.WithTLSKey(clientCert.key),
+		)
+		require.NoError(t, err)
+
+		cli, err := client.New(ctx, client.WithDockerContext("remote-tls"), failFast)
+		require.NoError(t, err)
+		require.Equal(t, host, cli.DaemonHost())
+	})
+
+	t.Run("current-context", func(t *testing.T) {
+		dockercontext.SetupTestDockerContexts(t, 1, 1)
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+		t.Setenv("DOCKER_HOST", "")
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		_, err := dockercontext.New("remote-tls", dockercontext.WithHost(host),
+			dockercontext.WithTLSCA(ca.cert),
+			dockercontext.WithTLSCert(clientCert.cert),
+			dockercontext.WithTLSKey(clientCert.key),
+			dockercontext.AsCurrent(),
+		)
+		require.NoError(t, err)
+
+		_, err = client.New(ctx, failFast)
+		require.NoError(t, err)
+	})
+
+	t.Run("context-watch", func(t *testing.T) {
+		dockercontext.SetupTestDockerContexts(t, 1, 1)
+
+		_, err := dockercontext.New("remote-tls", dockercontext.WithHost(host),
+			dockercontext.WithTLSCA(ca.cert),
+			dockercontext.WithTLSCert(clientCert.cert),
+			dockercontext.WithTLSKey(clientCert.key),
+		)
+		require.NoError(t, err)
+
+		// the TLS handshake is done by the dialer following the context
+		cli, err := client.New(ctx, client.WithDockerContext("remote-tls"), client.WithContextWatch(), failFast)
+		require.NoError(t, err)
+		require.NoError(t, cli.Close())
+	})
+
+	t.Run("no-client-certificate", func(t *testing.T) {
+		dockercontext.SetupTestDockerContexts(t, 1, 1)
+
+		_, err := dockercontext.New("remote-tls", dockercontext.WithHost(host), dockercontext.WithTLSCA(ca.cert))
+		require.NoError(t, err)
+
+		// the daemon requires a client certificate
+		_, err = client.New(ctx, client.WithDockerContext("remote-tls"), failFast)
+		require.ErrorContains(t, err, "health check")
+	})
+}
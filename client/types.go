@@ -2,13 +2,20 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"iter"
 	"log/slog"
+	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	dockercontext "github.com/docker/go-sdk/context"
 )
 
 // packagePath is the package path for the docker-go-sdk package.
@@ -29,6 +36,26 @@ type SDKClient interface {
 
 	// FindContainerByID finds a container by ID.
 	FindContainerByID(ctx context.Context, containerID string) (*container.Summary, error)
+
+	// SessionID returns the ID of the session of the client, which is added
+	// to all the resources created by the client.
+	SessionID() string
+
+	// SubscribeEvents returns the Docker events matching the given options,
+	// reconnecting to the events stream on transient errors.
+	SubscribeEvents(ctx context.Context, opts ...EventsOption) iter.Seq2[Event, error]
+
+	// ForwardPort returns a local address forwarding its connections to the given port of the Docker host.
+	ForwardPort(ctx context.Context, port uint16) (string, error)
+
+	// ForwardsPorts reports whether the ports published by the containers are forwarded to local ports.
+	ForwardsPorts() bool
+
+	// Capabilities returns the cached capabilities of the docker daemon.
+	Capabilities(ctx context.Context) (Capabilities, error)
+
+	// TracerProvider returns the tracer provider of the client.
+	TracerProvider() trace.TracerProvider
 }
 
 var _ client.APIClient = &sdkClient{}
@@ -58,6 +85,48 @@ type sdkClient struct {
 	// dockerHost is the host of the docker daemon.
 	dockerHost string
 
+	// tlsConfig is the TLS configuration of the docker context the docker host is read from,
+	// built from the TLS material of its TLS store. If not set, the docker host is not reached through TLS,
+	// unless configured with the DOCKER_TLS_VERIFY and DOCKER_CERT_PATH environment variables.
+	tlsConfig *tls.Config
+
+	// sshDialer is the dialer used to connect to ssh:// docker hosts.
+	// If not set, the system ssh binary is used for them.
+	sshDialer SSHDialer
+
+	// sshHost is the ssh:// docker host the client is connected to, if any.
+	sshHost string
+
+	// portForwarding is used to forward the ports published by the containers
+	// of a remote docker daemon to local ports.
+	portForwarding bool
+
+	// forwarders are the forwarded ports of the docker host, by port.
+	forwarders    map[uint16]*portForwarder
+	forwardersMtx sync.Mutex
+
+	// sidecarID is the ID of the container used to forward ports, when not connected through SSH.
+	sidecarID string
+
+	// transport is the transport of the HTTP client used to call the Docker API.
+	// If not set, the transport of the docker client is used.
+	transport http.RoundTripper
+
+	// contextWatch is used to follow the changes of the docker context of the client,
+	// reconnecting to its new docker host, with the given watch options.
+	contextWatch     bool
+	contextWatchOpts []dockercontext.WatchOption
+
+	// watchedContext is the docker context followed by the client, if it was passed with
+	// WithDockerContext. If empty, the client follows the current docker context.
+	watchedContext string
+
+	// watcher, dialer and dialTransport are used to reconnect to the new docker host
+	// of the followed docker context, when contextWatch is set.
+	watcher       *dockercontext.Watcher
+	dialer        *reconnectDialer
+	dialTransport *http.Transport
+
 	// extraHeaders are additional headers to be sent to the docker client.
 	extraHeaders map[string]string
 
@@ -65,9 +134,47 @@ type sdkClient struct {
 	dockerInfo    client.SystemInfoResult
 	dockerInfoSet bool
 
+	// cached capabilities of the docker daemon, built on the docker info
+	capabilities *Capabilities
+
+	// tracerProvider and meterProvider are used to instrument the API calls.
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+
+	// httpClient is the HTTP client passed to the docker client, if any,
+	// whose transport is instrumented once the docker client is created.
+	httpClient *http.Client
+
+	// retryPolicy is used to retry the idempotent API calls failing with transient errors.
+	// If not set, the calls are not retried.
+	retryPolicy *RetryPolicy
+
+	// admissionRules are the rules of the admission policy, evaluated before creating
+	// containers and pulling images. If not set, all the requests are admitted.
+	admissionRules []AdmissionRule
+
 	// healthCheck is a function that returns the health of the docker daemon.
 	// If not set, the default health check will be used.
 	healthCheck func(ctx context.Context) func(c SDKClient) error
+
+	// sessionID is the ID of the session of the client, generated when the client is created.
+	sessionID string
+
+	// runMetadata is the metadata added to the labels of all the resources created by the client.
+	runMetadata map[string]string
+
+	// reaperDisabled is used to disable the removal of the resources of dead sessions.
+	reaperDisabled bool
+
+	// reaperForceImages forces the removal of the images of dead sessions.
+	reaperForceImages bool
+
+	// reaperOnce starts the reaper the first time the client creates a resource,
+	// and reaperCancel stops it when the client is closed, waiting for its goroutines
+	// with reaperWg.
+	reaperOnce   sync.Once
+	reaperCancel context.CancelFunc
+	reaperWg     sync.WaitGroup
 }
 
 // Logger returns the logger for the client.
@@ -79,27 +186,32 @@ func (c *sdkClient) Logger() *slog.Logger {
 // and reused every time Info is called.
 // It will also print out the docker server info, and the resolved Docker paths, to the default logger.
 func (c *sdkClient) Info(ctx context.Context, options client.InfoOptions) (client.SystemInfoResult, error) {
-	c.mtx.Lock()
+	c.mtx.RLock()
 	if c.dockerInfoSet {
-		defer c.mtx.Unlock()
+		defer c.mtx.RUnlock()
 		return c.dockerInfo, nil
 	}
-	c.mtx.Unlock()
+	c.mtx.RUnlock()
 
-	var info client.SystemInfoResult
-
-	info, err := c.APIClient.Info(ctx, options)
+	info, err := retryCall(ctx, c, "Info", func() (client.SystemInfoResult, error) {
+		return c.APIClient.Info(ctx, options)
+	})
 	if err != nil {
 		return info, fmt.Errorf("docker info: %w", err)
 	}
+
+	// the client reconnecting to another docker host discards the cached info
+	c.mtx.Lock()
 	c.dockerInfo = info
 	c.dockerInfoSet = true
+	dockerContext, dockerHost := c.dockerContext, c.dockerHost
+	c.mtx.Unlock()
 
 	infoLabels := ""
-	if len(c.dockerInfo.Info.Labels) > 0 {
+	if len(info.Info.Labels) > 0 {
 		var sb strings.Builder
 		sb.WriteString("\n  Labels:")
-		for _, lb := range c.dockerInfo.Info.Labels {
+		for _, lb := range info.Info.Labels {
 			sb.WriteString("\n    ")
 			sb.WriteString(lb)
 		}
@@ -108,14 +220,14 @@ func (c *sdkClient) Info(ctx context.Context, options client.InfoOptions) (clien
 
 	c.log.Info("Connected to docker",
 		"package", packagePath,
-		"server_version", c.dockerInfo.Info.ServerVersion,
+		"server_version", info.Info.ServerVersion,
 		"client_version", c.ClientVersion(),
-		"operating_system", c.dockerInfo.Info.OperatingSystem,
-		"mem_total", c.dockerInfo.Info.MemTotal/1024/1024,
+		"operating_system", info.Info.OperatingSystem,
+		"mem_total", info.Info.MemTotal/1024/1024,
 		"labels", infoLabels,
-		"docker_context", c.dockerContext,
-		"docker_host", c.dockerHost,
+		"docker_context", dockerContext,
+		"docker_host", dockerHost,
 	)
 
-	return c.dockerInfo, nil
+	return info, nil
 }
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/go-connections/sockets"
+	"github.com/moby/moby/client"
+
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// errStaleConnection is returned by the connections to a docker host the client is no longer connected to.
+var errStaleConnection = errors.New("connection to a previous docker host")
+
+// dialTarget is the docker host a reconnecting client dials.
+type dialTarget struct {
+	host      string
+	dial      func(ctx context.Context, network, addr string) (net.Conn, error)
+	tlsConfig *tls.Config
+}
+
+// newDialTarget returns the target to dial the given docker host, with the given TLS configuration.
+// The ssh:// docker hosts are not supported.
+func newDialTarget(host string, tlsConfig *tls.Config) (*dialTarget, error) {
+	hostURL, err := client.ParseHostURL(host)
+	if err != nil {
+		return nil, fmt.Errorf("parse docker host: %w", err)
+	}
+
+	if hostURL.Scheme == "ssh" {
+		return nil, fmt.Errorf("ssh docker host not supported: %s", host)
+	}
+
+	// let the docker sockets configure the dialer, as the docker client does
+	tr := &http.Transport{}
+	if err := sockets.ConfigureTransport(tr, hostURL.Scheme, hostURL.Host); err != nil {
+		return nil, fmt.Errorf("configure transport: %w", err)
+	}
+
+	addr := hostURL.Host
+	return &dialTarget{
+		host: host,
+		dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return tr.DialContext(ctx, network, addr)
+		},
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+// reconnectDialer dials the current docker host of a client following its docker context.
+// It's used by the transport of the client, so that the requests, and the hijacked connections
+// of the attached streams, are sent to the docker host it's currently connected to.
+type reconnectDialer struct {
+	target atomic.Pointer[dialTarget]
+}
+
+// DialContext dials the current docker host, through TLS if its docker context has TLS material.
+func (d *reconnectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	target := d.target.Load()
+
+	conn, err := target.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.tlsConfig != nil {
+		cfg := target.tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			if hostURL, err := client.ParseHostURL(target.host); err == nil {
+				cfg.ServerName = hostURL.Hostname()
+			}
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	return &reconnectConn{Conn: conn, dialer: d, target: target}, nil
+}
+
+// reconnectConn is a connection to a docker host. The pooled connections of the HTTP requests
+// stop writing once the client is connected to another docker host, so that they're not reused
+// for new requests, while the hijacked ones, e.g. of the attached streams of the execs, are kept
+// until they're closed.
+type reconnectConn struct {
+	net.Conn
+	dialer *reconnectDialer
+	target *dialTarget
+
+	// hijacked reports whether the connection is upgraded to a raw stream, which is
+	// known from the request written first, once firstWrite is done.
+	firstWrite sync.Once
+	hijacked   bool
+}
+
+// Write writes to the connection, unless it's a pooled connection, and the client
+// is connected to another docker host.
+func (c *reconnectConn) Write(p []byte) (int, error) {
+	c.firstWrite.Do(func() {
+		c.hijacked = isUpgradeRequest(p)
+	})
+
+	if !c.hijacked && c.dialer.target.Load() != c.target {
+		c.Conn.Close()
+		return 0, errStaleConnection
+	}
+	return c.Conn.Write(p)
+}
+
+// isUpgradeRequest reports whether the headers of the HTTP request starting with p ask to upgrade
+// the connection, as the docker client does to hijack it.
+func isUpgradeRequest(p []byte) bool {
+	if end := bytes.Index(p, []byte("\r\n\r\n")); end >= 0 {
+		p = p[:end]
+	}
+	return bytes.Contains(p, []byte("\r\nUpgrade: "))
+}
+
+// reconnectOpts returns the options of the docker client following the docker context, dialing the given host.
+func (c *sdkClient) reconnectOpts(host string) ([]client.Opt, error) {
+	if c.transport != nil {
+		return nil, errors.New("context watch cannot be used with an HTTP transport")
+	}
+
+	target, err := newDialTarget(host, c.tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c.dialer = &reconnectDialer{}
+	c.dialer.target.Store(target)
+	c.dialTransport = &http.Transport{DialContext: c.dialer.DialContext}
+
+	// replace the HTTP client once the host is applied, so that the dialer of the client is used
+	return []client.Opt{c.withHTTPClient(&http.Client{Transport: c.dialTransport, CheckRedirect: client.CheckRedirect})}, nil
+}
+
+// startWatch starts following the changes of the docker context of the client,
+// reconnecting to the new docker host when it changes.
+func (c *sdkClient) startWatch() error {
+	watcher, err := dockercontext.NewWatcher(c.contextWatchOpts...)
+	if err != nil {
+		return fmt.Errorf("new watcher: %w", err)
+	}
+	c.watcher = watcher
+
+	go func() {
+		for event := range watcher.Events() {
+			host, tlsConfig, err := c.watchedHost(event)
+			if err != nil {
+				c.log.Warn("Failed to resolve the docker host of the context", "docker_context", c.watchedContext, "error", err)
+				continue
+			}
+
+			if host == "" || host == c.dialer.target.Load().host {
+				continue
+			}
+
+			dockerContext := event.Context
+			if c.watchedContext != "" {
+				dockerContext = c.watchedContext
+			}
+
+			if err := c.reconnect(host, tlsConfig, dockerContext); err != nil {
+				c.log.Warn("Failed to reconnect to docker", "docker_host", host, "error", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchedHost returns the docker host, and its TLS configuration, to connect to after the given change
+// of the docker contexts, or an empty host if the docker host of the client is not affected by it.
+func (c *sdkClient) watchedHost(event dockercontext.Event) (string, *tls.Config, error) {
+	// the client follows the current context
+	if c.watchedContext == "" {
+		if event.Type != dockercontext.EventCurrentChanged {
+			return "", nil, nil
+		}
+		if event.Err != nil {
+			return "", nil, event.Err
+		}
+
+		tlsConfig, err := currentContextTLS(event.Context, event.Host)
+		if err != nil {
+			return "", nil, fmt.Errorf("tls config from context: %w", err)
+		}
+		return event.Host, tlsConfig, nil
+	}
+
+	// the client follows the docker endpoint of its docker context
+	if event.Type != dockercontext.EventStoreChanged {
+		return "", nil, nil
+	}
+
+	dockerCtx, err := dockercontext.Inspect(c.watchedContext)
+	if err != nil {
+		return "", nil, fmt.Errorf("inspect context: %w", err)
+	}
+
+	tlsConfig, err := dockerCtx.TLSConfig()
+	if err != nil {
+		return "", nil, fmt.Errorf("tls config from context: %w", err)
+	}
+
+	// Inspect already validates that the docker endpoint is set
+	return dockerCtx.Endpoints[dockercontext.DockerEndpoint].Host, tlsConfig, nil
+}
+
+// reconnect connects the client to the given docker host: the new requests are sent to it,
+// and the connections to the previous one are closed once idle. The cached info and
+// capabilities of the previous docker daemon are discarded, and so are its forwarded ports,
+// as the sidecar container forwarding them runs on the previous docker daemon.
+func (c *sdkClient) reconnect(host string, tlsConfig *tls.Config, dockerContext string) error {
+	target, err := newDialTarget(host, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	// the sidecar is removed while the previous docker daemon is still dialed
+	if err := c.closeForwarders(); err != nil {
+		c.log.Warn("Failed to close the forwarded ports of the previous docker host", "error", err)
+	}
+
+	c.mtx.Lock()
+	previous := c.dockerHost
+	c.dockerHost = host
+	c.dockerContext = dockerContext
+	c.tlsConfig = tlsConfig
+	c.dockerInfo = client.SystemInfoResult{}
+	c.dockerInfoSet = false
+	c.capabilities = nil
+	c.mtx.Unlock()
+
+	c.dialer.target.Store(target)
+	c.dialTransport.CloseIdleConnections()
+
+	c.log.Info("Reconnected to docker", "previous_docker_host", previous, "docker_host", host, "docker_context", dockerContext)
+
+	return nil
+}
+
+// DaemonHost returns the host of the docker daemon the client is connected to,
+// which changes when the client follows its docker context.
+func (c *sdkClient) DaemonHost() string {
+	if c.dialer != nil {
+		return c.dialer.target.Load().host
+	}
+	return c.APIClient.DaemonHost()
+}
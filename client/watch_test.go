@@ -0,0 +1,137 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/system"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// newNamedDaemon returns the docker host of a daemon answering the ping and info requests, with the given name.
+func newNamedDaemon(t *testing.T, name string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.52")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/info"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(system.Info{Name: name})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return "tcp://" + srv.Listener.Addr().String()
+}
+
+// requireDaemon asserts that the client eventually reaches the daemon with the given host and name.
+func requireDaemon(t *testing.T, cli client.SDKClient, host, name string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return cli.DaemonHost() == host }, 5*time.Second, 10*time.Millisecond)
+
+	info, err := cli.Info(context.Background(), dockerclient.InfoOptions{})
+	require.NoError(t, err)
+	require.Equal(t, name, info.Info.Name)
+}
+
+func TestWithContextWatch(t *testing.T) {
+	ctx := context.Background()
+
+	hostA := newNamedDaemon(t, "daemon-a")
+	hostB := newNamedDaemon(t, "daemon-b")
+
+	setup := func(t *testing.T) {
+		t.Helper()
+
+		dockercontext.SetupTestDockerContexts(t, 1, 1)
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+		t.Setenv("DOCKER_HOST", "")
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		_, err := dockercontext.New("context-a", dockercontext.WithHost(hostA), dockercontext.AsCurrent())
+		require.NoError(t, err)
+		_, err = dockercontext.New("context-b", dockercontext.WithHost(hostB))
+		require.NoError(t, err)
+	}
+
+	watch := client.WithContextWatch(dockercontext.WithWatchInterval(10 * time.Millisecond))
+
+	t.Run("current-context", func(t *testing.T) {
+		setup(t)
+
+		cli, err := client.New(ctx, watch)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, cli.Close())
+		}()
+
+		requireDaemon(t, cli, hostA, "daemon-a")
+
+		require.NoError(t, dockercontext.Use("context-b"))
+		requireDaemon(t, cli, hostB, "daemon-b")
+
+		require.NoError(t, dockercontext.Use("context-a"))
+		requireDaemon(t, cli, hostA, "daemon-a")
+	})
+
+	t.Run("docker-context", func(t *testing.T) {
+		setup(t)
+
+		cli, err := client.New(ctx, client.WithDockerContext("context-b"), watch)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, cli.Close())
+		}()
+
+		requireDaemon(t, cli, hostB, "daemon-b")
+
+		// switching the current context does not affect the client
+		require.NoError(t, dockercontext.Use("context-b"))
+
+		_, err = dockercontext.Update("context-b", dockercontext.WithHost(hostA))
+		require.NoError(t, err)
+		requireDaemon(t, cli, hostA, "daemon-a")
+	})
+
+	t.Run("without-watch", func(t *testing.T) {
+		setup(t)
+
+		cli, err := client.New(ctx)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, cli.Close())
+		}()
+
+		require.NoError(t, dockercontext.Use("context-b"))
+		time.Sleep(50 * time.Millisecond)
+
+		require.Equal(t, hostA, cli.DaemonHost())
+	})
+
+	t.Run("error/docker-host", func(t *testing.T) {
+		_, err := client.New(ctx, client.WithDockerHost(hostA), watch)
+		require.ErrorContains(t, err, "context watch cannot be used with a docker host")
+	})
+
+	t.Run("error/http-transport", func(t *testing.T) {
+		setup(t)
+
+		_, err := client.New(ctx, client.WithHTTPTransport(http.DefaultTransport), watch)
+		require.ErrorContains(t, err, "context watch cannot be used with an HTTP transport")
+	})
+}
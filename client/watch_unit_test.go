@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// infoMockCli is a mock implementation of client.APIClient counting the calls to Info.
+type infoMockCli struct {
+	mockCli
+
+	calls atomic.Int32
+}
+
+func (m *infoMockCli) ClientVersion() string {
+	return "1.52"
+}
+
+func (m *infoMockCli) Info(_ context.Context, _ client.InfoOptions) (client.SystemInfoResult, error) {
+	m.calls.Add(1)
+	return client.SystemInfoResult{}, nil
+}
+
+// newPipeTarget returns a target for the given docker host, whose connections discard what's written to them.
+func newPipeTarget(host string) *dialTarget {
+	return &dialTarget{
+		host: host,
+		dial: func(_ context.Context, _, _ string) (net.Conn, error) {
+			local, remote := net.Pipe()
+			go func() {
+				_, _ = io.Copy(io.Discard, remote)
+			}()
+			return local, nil
+		},
+	}
+}
+
+func TestReconnectConn(t *testing.T) {
+	ctx := context.Background()
+
+	dialer := &reconnectDialer{}
+	dialer.target.Store(newPipeTarget("tcp://a:2375"))
+
+	dial := func(t *testing.T, request string) net.Conn {
+		t.Helper()
+
+		conn, err := dialer.DialContext(ctx, "tcp", "a:2375")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+
+		_, err = conn.Write([]byte(request))
+		require.NoError(t, err)
+
+		return conn
+	}
+
+	pooled := dial(t, "GET /v1.52/_ping HTTP/1.1\r\nHost: api.moby.localhost\r\n\r\n")
+	hijacked := dial(t, "POST /v1.52/exec/abc/start HTTP/1.1\r\nHost: api.moby.localhost\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+
+	dialer.target.Store(newPipeTarget("tcp://b:2375"))
+
+	t.Run("pooled", func(t *testing.T) {
+		_, err := pooled.Write([]byte("GET /v1.52/info HTTP/1.1\r\nHost: api.moby.localhost\r\n\r\n"))
+		require.ErrorIs(t, err, errStaleConnection)
+	})
+
+	t.Run("hijacked", func(t *testing.T) {
+		// the attached stream keeps going to the previous docker host
+		_, err := hijacked.Write([]byte("ls\n"))
+		require.NoError(t, err)
+	})
+}
+
+func TestIsUpgradeRequest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		request string
+		want    bool
+	}{
+		"upgrade":         {request: "POST /v1.52/containers/abc/attach HTTP/1.1\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n", want: true},
+		"no-upgrade":      {request: "GET /v1.52/_ping HTTP/1.1\r\nHost: api.moby.localhost\r\n\r\n"},
+		"upgrade-in-body": {request: "POST /v1.52/containers/create HTTP/1.1\r\nHost: api.moby.localhost\r\n\r\n{\"Cmd\": [\"\\r\\nUpgrade: tcp\"]}\r\nUpgrade: tcp"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, isUpgradeRequest([]byte(tc.request)))
+		})
+	}
+}
+
+func TestSDKClient_reconnect(t *testing.T) {
+	ctx := context.Background()
+
+	m := &infoMockCli{}
+	c := &sdkClient{
+		APIClient:     m,
+		log:           slog.New(slog.DiscardHandler),
+		dockerHost:    "tcp://a:2375",
+		dialer:        &reconnectDialer{},
+		dialTransport: &http.Transport{},
+	}
+	c.dialer.target.Store(newPipeTarget("tcp://a:2375"))
+
+	// the info is cached concurrently with the reconnections, e.g. for the race detector
+	started, done := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_, _ = c.Info(ctx, client.InfoOptions{})
+			}
+		}
+	}()
+	<-started
+	for i := range 100 {
+		host := "tcp://a:2375"
+		if i%2 == 0 {
+			host = "tcp://b:2375"
+		}
+		require.NoError(t, c.reconnect(host, nil, ""))
+	}
+	close(done)
+	wg.Wait()
+
+	// the cached info of the previous docker host is discarded
+	_, err := c.Info(ctx, client.InfoOptions{})
+	require.NoError(t, err)
+	calls := m.calls.Load()
+
+	require.NoError(t, c.reconnect("tcp://b:2375", nil, ""))
+	for range 2 {
+		_, err = c.Info(ctx, client.InfoOptions{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, calls+1, m.calls.Load())
+}
@@ -0,0 +1,460 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	dockercontainer "github.com/moby/moby/api/types/container"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/container"
+	"github.com/docker/go-sdk/container/wait"
+	"github.com/docker/go-sdk/network"
+	"github.com/docker/go-sdk/volume"
+)
+
+// defaultNetwork is the network the services without networks are attached to.
+const defaultNetwork = "default"
+
+// Labels added to the resources of a project, compatible with the ones of docker compose.
+const (
+	// LabelProject is the name of the project the resource belongs to.
+	LabelProject = "com.docker.compose.project"
+
+	// LabelService is the name of the service of a container.
+	LabelService = "com.docker.compose.service"
+
+	// LabelNetwork is the name of a network in the project.
+	LabelNetwork = "com.docker.compose.network"
+
+	// LabelVolume is the name of a volume in the project.
+	LabelVolume = "com.docker.compose.volume"
+)
+
+// Stack is a running compose project: the networks, volumes and containers
+// created for it, which are torn down together.
+type Stack struct {
+	project *Project
+	client  client.SDKClient
+
+	// networkNames and volumeNames are the Docker names of the networks and volumes of the project.
+	networkNames map[string]string
+	volumeNames  map[string]string
+
+	// networks and volumes are the resources created for the project, without the external ones.
+	networks map[string]*network.Network
+	volumes  map[string]*volume.Volume
+
+	// containers are the containers of the services, by service name.
+	containers map[string]*container.Container
+
+	// order is the order the services are started in.
+	order []string
+}
+
+// Up creates the networks and volumes of the project, and starts its services in dependency
+// order, waiting for the conditions of their dependencies. If any of them fails, the resources
+// created so far are torn down, keeping the named volumes.
+func Up(ctx context.Context, project *Project, opts ...UpOption) (*Stack, error) {
+	upOpts := &upOptions{}
+	for _, opt := range opts {
+		if err := opt(upOpts); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	if project == nil {
+		return nil, errdefs.ErrInvalidArgument.WithMessage("project is nil")
+	}
+
+	order, err := project.startOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	if upOpts.client == nil {
+		sdk, err := client.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		upOpts.client = sdk
+	}
+
+	s := &Stack{
+		project:      project,
+		client:       upOpts.client,
+		networkNames: make(map[string]string),
+		volumeNames:  make(map[string]string),
+		networks:     make(map[string]*network.Network),
+		volumes:      make(map[string]*volume.Volume),
+		containers:   make(map[string]*container.Container),
+		order:        order,
+	}
+
+	if err := s.up(ctx, upOpts); err != nil {
+		return nil, errors.Join(err, s.Down(context.WithoutCancel(ctx)))
+	}
+
+	return s, nil
+}
+
+// up creates the resources of the stack.
+func (s *Stack) up(ctx context.Context, upOpts *upOptions) error {
+	if err := s.createNetworks(ctx); err != nil {
+		return err
+	}
+
+	if err := s.createVolumes(ctx); err != nil {
+		return err
+	}
+
+	for _, name := range s.order {
+		if err := s.runService(ctx, name, upOpts.customizers[name]); err != nil {
+			return fmt.Errorf("service %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// createNetworks creates the networks of the project, including the default network
+// if any service is not attached to any network.
+func (s *Stack) createNetworks(ctx context.Context) error {
+	networks := maps.Clone(s.project.Networks)
+	if networks == nil {
+		networks = make(map[string]Network)
+	}
+
+	for _, svc := range s.project.Services {
+		if _, ok := networks[defaultNetwork]; !ok && len(svc.Networks) == 0 {
+			networks[defaultNetwork] = Network{}
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(networks)) {
+		cfg := networks[key]
+
+		name := cfg.Name
+		if name == "" {
+			if cfg.External {
+				name = key
+			} else {
+				name = s.project.Name + "_" + key
+			}
+		}
+		s.networkNames[key] = name
+
+		if cfg.External {
+			continue
+		}
+
+		labels := maps.Clone(cfg.Labels)
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[LabelProject] = s.project.Name
+		labels[LabelNetwork] = key
+
+		nwOpts := []network.Option{
+			network.WithClient(s.client),
+			network.WithName(name),
+			network.WithLabels(labels),
+		}
+		if cfg.Driver != "" {
+			nwOpts = append(nwOpts, network.WithDriver(cfg.Driver))
+		}
+		if cfg.Internal {
+			nwOpts = append(nwOpts, network.WithInternal())
+		}
+		if cfg.Attachable {
+			nwOpts = append(nwOpts, network.WithAttachable())
+		}
+		if cfg.EnableIPv6 {
+			nwOpts = append(nwOpts, network.WithEnableIPv6())
+		}
+
+		nw, err := network.New(ctx, nwOpts...)
+		if err != nil {
+			return fmt.Errorf("network %q: %w", key, err)
+		}
+		s.networks[key] = nw
+	}
+
+	return nil
+}
+
+// createVolumes creates the named volumes of the project.
+func (s *Stack) createVolumes(ctx context.Context) error {
+	for _, key := range slices.Sorted(maps.Keys(s.project.Volumes)) {
+		cfg := s.project.Volumes[key]
+
+		name := cfg.Name
+		if name == "" {
+			if cfg.External {
+				name = key
+			} else {
+				name = s.project.Name + "_" + key
+			}
+		}
+		s.volumeNames[key] = name
+
+		if cfg.External {
+			continue
+		}
+
+		labels := maps.Clone(cfg.Labels)
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[LabelProject] = s.project.Name
+		labels[LabelVolume] = key
+
+		v, err := volume.New(ctx, volume.WithClient(s.client), volume.WithName(name), volume.WithLabels(labels))
+		if err != nil {
+			return fmt.Errorf("volume %q: %w", key, err)
+		}
+		s.volumes[key] = v
+	}
+
+	return nil
+}
+
+// runService runs the container of the given service, waiting for the conditions
+// its dependents require, applying the given customizers last.
+func (s *Stack) runService(ctx context.Context, name string, customizers []container.ContainerCustomizer) error {
+	svc := s.project.Services[name]
+
+	opts, err := s.serviceCustomizers(name, svc)
+	if err != nil {
+		return err
+	}
+
+	conditions := s.project.conditions(name)
+
+	var strategies []wait.Strategy
+	if conditions[ConditionServiceHealthy] {
+		if svc.Healthcheck == nil || svc.Healthcheck.Disable {
+			return errdefs.ErrInvalidArgument.WithMessage("a service depends on it being healthy, but it has no healthcheck")
+		}
+		strategies = append(strategies, wait.ForHealthCheck())
+	}
+	if conditions[ConditionServiceCompletedSuccessfully] {
+		strategies = append(strategies, wait.ForExit())
+	}
+	if len(strategies) > 0 {
+		opts = append(opts, container.WithWaitStrategy(strategies...))
+	}
+
+	ctr, err := container.Run(ctx, append(opts, customizers...)...)
+	if ctr != nil {
+		s.containers[name] = ctr
+	}
+	if err != nil {
+		return fmt.Errorf("run container: %w", err)
+	}
+
+	if conditions[ConditionServiceCompletedSuccessfully] {
+		state, err := ctr.State(ctx)
+		if err != nil {
+			return fmt.Errorf("container state: %w", err)
+		}
+		if state.ExitCode != 0 {
+			return fmt.Errorf("container exited with code %d", state.ExitCode)
+		}
+	}
+
+	return nil
+}
+
+// serviceCustomizers returns the customizers of the container of the given service.
+func (s *Stack) serviceCustomizers(name string, svc Service) ([]container.ContainerCustomizer, error) {
+	containerName := svc.ContainerName
+	if containerName == "" {
+		containerName = s.project.Name + "-" + name + "-1"
+	}
+
+	labels := maps.Clone(svc.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[LabelProject] = s.project.Name
+	labels[LabelService] = name
+	labels[moduleLabel] = Version()
+
+	opts := []container.ContainerCustomizer{
+		container.WithClient(s.client),
+		container.WithImage(svc.Image),
+		container.WithName(containerName),
+		container.WithLabels(labels),
+	}
+
+	if len(svc.Command) > 0 {
+		opts = append(opts, container.WithCmd(svc.Command...))
+	}
+	if len(svc.Entrypoint) > 0 {
+		opts = append(opts, container.WithEntrypoint(svc.Entrypoint...))
+	}
+	if len(svc.Environment) > 0 {
+		opts = append(opts, container.WithEnv(svc.Environment))
+	}
+	if len(svc.Ports) > 0 {
+		opts = append(opts, container.WithExposedPorts(svc.Ports...))
+	}
+
+	networks := svc.Networks
+	if len(networks) == 0 {
+		networks = ServiceNetworks{defaultNetwork: {}}
+	}
+	for _, key := range slices.Sorted(maps.Keys(networks)) {
+		aliases := append([]string{name}, networks[key].Aliases...)
+		opts = append(opts, container.WithNetworkName(aliases, s.networkNames[key]))
+	}
+
+	mounts := make([]volumeMount, 0, len(svc.Volumes))
+	for _, v := range svc.Volumes {
+		m, err := parseVolumeMount(v, s.project.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+
+	opts = append(opts,
+		container.WithAdditionalConfigModifier(func(cfg *dockercontainer.Config) {
+			if svc.User != "" {
+				cfg.User = svc.User
+			}
+			if svc.WorkingDir != "" {
+				cfg.WorkingDir = svc.WorkingDir
+			}
+			if svc.Healthcheck != nil {
+				cfg.Healthcheck = svc.Healthcheck.config()
+			}
+		}),
+		container.WithAdditionalHostConfigModifier(func(hc *dockercontainer.HostConfig) {
+			for _, m := range mounts {
+				hc.Mounts = append(hc.Mounts, m.mount(s.volumeNames))
+			}
+			hc.Privileged = svc.Privileged
+			hc.ExtraHosts = append(hc.ExtraHosts, svc.ExtraHosts...)
+			for _, t := range svc.Tmpfs {
+				if hc.Tmpfs == nil {
+					hc.Tmpfs = make(map[string]string)
+				}
+				path, options, _ := strings.Cut(t, ":")
+				hc.Tmpfs[path] = options
+			}
+			if len(svc.Sysctls) > 0 {
+				hc.Sysctls = maps.Clone(svc.Sysctls)
+			}
+		}),
+	)
+
+	return opts, nil
+}
+
+// config returns the healthcheck in the format of the Docker API.
+func (h *Healthcheck) config() *dockercontainer.HealthConfig {
+	if h.Disable {
+		return &dockercontainer.HealthConfig{Test: []string{"NONE"}}
+	}
+
+	return &dockercontainer.HealthConfig{
+		Test:        h.Test,
+		Interval:    time.Duration(h.Interval),
+		Timeout:     time.Duration(h.Timeout),
+		StartPeriod: time.Duration(h.StartPeriod),
+		Retries:     h.Retries,
+	}
+}
+
+// Project returns the compose project of the stack.
+func (s *Stack) Project() *Project {
+	return s.project
+}
+
+// Services returns the names of the services of the stack, in the order they were started.
+func (s *Stack) Services() []string {
+	return slices.Clone(s.order)
+}
+
+// Container returns the container of the given service.
+func (s *Stack) Container(service string) (*container.Container, error) {
+	ctr, ok := s.containers[service]
+	if !ok {
+		return nil, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("service %q not found", service))
+	}
+	return ctr, nil
+}
+
+// Network returns the network created for the given network of the project.
+// External networks are not returned, as they are not created by the stack.
+func (s *Stack) Network(name string) (*network.Network, error) {
+	nw, ok := s.networks[name]
+	if !ok {
+		return nil, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("network %q not found", name))
+	}
+	return nw, nil
+}
+
+// Volume returns the volume created for the given named volume of the project.
+// External volumes are not returned, as they are not created by the stack.
+func (s *Stack) Volume(name string) (*volume.Volume, error) {
+	v, ok := s.volumes[name]
+	if !ok {
+		return nil, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("volume %q not found", name))
+	}
+	return v, nil
+}
+
+// Down tears down the stack: it terminates the containers of the services, in reverse
+// start order, and removes the networks created for the project. The named volumes are
+// kept, unless [WithRemoveVolumes] is used. It tries to remove all the resources,
+// returning the errors of the ones that could not be removed.
+func (s *Stack) Down(ctx context.Context, opts ...DownOption) error {
+	downOpts := &downOptions{}
+	for _, opt := range opts {
+		if err := opt(downOpts); err != nil {
+			return fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, name := range slices.Backward(s.order) {
+		ctr, ok := s.containers[name]
+		if !ok {
+			continue
+		}
+		if err := ctr.Terminate(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("terminate service %q: %w", name, err))
+			continue
+		}
+		delete(s.containers, name)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.networks)) {
+		if err := s.networks[key].Terminate(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("terminate network %q: %w", key, err))
+			continue
+		}
+		delete(s.networks, key)
+	}
+
+	if downOpts.removeVolumes {
+		for _, key := range slices.Sorted(maps.Keys(s.volumes)) {
+			if err := s.volumes[key].Terminate(ctx, volume.WithForce()); err != nil {
+				errs = append(errs, fmt.Errorf("terminate volume %q: %w", key, err))
+				continue
+			}
+			delete(s.volumes, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,346 @@
+package compose
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	dockernetwork "github.com/moby/moby/api/types/network"
+	dockervolume "github.com/moby/moby/api/types/volume"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+	"github.com/docker/go-sdk/container"
+)
+
+// composeMockCli is a mock implementation of client.APIClient backed by the fake Docker API,
+// which keeps the state of the networks, volumes and containers of a project. It records the
+// order in which the containers are started and removed, fails to start the given container,
+// and makes the containers with an exit code exit once started.
+type composeMockCli struct {
+	*clienttest.FakeAPI
+
+	mu sync.Mutex
+
+	// exitCodes are the exit codes of the containers that exit once started, by name.
+	exitCodes map[string]int
+
+	// failStart is the name of the container that fails to start.
+	failStart string
+
+	started []string
+	removed []string
+}
+
+// newComposeMockCli returns a mock with the images of the test project, so that they
+// are not pulled.
+func newComposeMockCli(t *testing.T) *composeMockCli {
+	t.Helper()
+
+	fake := clienttest.NewFakeAPI()
+	for _, img := range []string{"postgres:16-alpine", "alpine:latest", "nginx:alpine"} {
+		_, err := fake.AddImage(img)
+		require.NoError(t, err)
+	}
+
+	return &composeMockCli{
+		FakeAPI:   fake,
+		exitCodes: make(map[string]int),
+	}
+}
+
+func (m *composeMockCli) ContainerStart(ctx context.Context, containerID string, options dockerclient.ContainerStartOptions) (dockerclient.ContainerStartResult, error) {
+	inspect, err := m.FakeAPI.ContainerInspect(ctx, containerID, dockerclient.ContainerInspectOptions{})
+	if err != nil {
+		return dockerclient.ContainerStartResult{}, err
+	}
+
+	name := strings.TrimPrefix(inspect.Container.Name, "/")
+	if name == m.failStart {
+		return dockerclient.ContainerStartResult{}, errdefs.ErrUnknown.WithMessage("failed to start")
+	}
+
+	result, err := m.FakeAPI.ContainerStart(ctx, containerID, options)
+	if err != nil {
+		return result, err
+	}
+
+	m.mu.Lock()
+	m.started = append(m.started, name)
+	code, exits := m.exitCodes[name]
+	m.mu.Unlock()
+
+	if exits {
+		if err := m.Exit(containerID, code); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (m *composeMockCli) ContainerRemove(ctx context.Context, containerID string, options dockerclient.ContainerRemoveOptions) (dockerclient.ContainerRemoveResult, error) {
+	inspect, err := m.FakeAPI.ContainerInspect(ctx, containerID, dockerclient.ContainerInspectOptions{})
+	if err != nil {
+		return dockerclient.ContainerRemoveResult{}, err
+	}
+
+	result, err := m.FakeAPI.ContainerRemove(ctx, containerID, options)
+	if err != nil {
+		return result, err
+	}
+
+	m.mu.Lock()
+	m.removed = append(m.removed, strings.TrimPrefix(inspect.Container.Name, "/"))
+	m.mu.Unlock()
+
+	return result, nil
+}
+
+// containers returns the containers of the fake Docker API, by name.
+func (m *composeMockCli) containers(t *testing.T) map[string]dockercontainer.InspectResponse {
+	t.Helper()
+
+	list, err := m.ContainerList(context.Background(), dockerclient.ContainerListOptions{All: true})
+	require.NoError(t, err)
+
+	containers := make(map[string]dockercontainer.InspectResponse, len(list.Items))
+	for _, item := range list.Items {
+		inspect, err := m.ContainerInspect(context.Background(), item.ID, dockerclient.ContainerInspectOptions{})
+		require.NoError(t, err)
+		containers[strings.TrimPrefix(inspect.Container.Name, "/")] = inspect.Container
+	}
+
+	return containers
+}
+
+// networks returns the networks of the project in the fake Docker API, by name.
+func (m *composeMockCli) networks(t *testing.T) map[string]dockernetwork.Inspect {
+	t.Helper()
+
+	list, err := m.NetworkList(context.Background(), dockerclient.NetworkListOptions{
+		Filters: make(dockerclient.Filters).Add("label", LabelProject),
+	})
+	require.NoError(t, err)
+
+	networks := make(map[string]dockernetwork.Inspect, len(list.Items))
+	for _, item := range list.Items {
+		inspect, err := m.NetworkInspect(context.Background(), item.ID, dockerclient.NetworkInspectOptions{})
+		require.NoError(t, err)
+		networks[item.Name] = inspect.Network
+	}
+
+	return networks
+}
+
+// volumes returns the volumes of the fake Docker API, by name.
+func (m *composeMockCli) volumes(t *testing.T) map[string]dockervolume.Volume {
+	t.Helper()
+
+	list, err := m.VolumeList(context.Background(), dockerclient.VolumeListOptions{})
+	require.NoError(t, err)
+
+	volumes := make(map[string]dockervolume.Volume, len(list.Items))
+	for _, v := range list.Items {
+		volumes[v.Name] = v
+	}
+
+	return volumes
+}
+
+// newComposeClient returns a client backed by the given mock.
+func newComposeClient(t *testing.T, m *composeMockCli) client.SDKClient {
+	t.Helper()
+
+	sdk, err := client.New(context.Background(), client.WithDockerAPI(m))
+	require.NoError(t, err)
+
+	return sdk
+}
+
+func loadTestProject(t *testing.T) *Project {
+	t.Helper()
+
+	project, err := Load(filepath.Join("testdata", "compose.yaml"), WithEnv(map[string]string{}))
+	require.NoError(t, err)
+
+	return project
+}
+
+func TestUp(t *testing.T) {
+	m := newComposeMockCli(t)
+	m.exitCodes["shop-migrate-1"] = 0
+
+	sdk := newComposeClient(t, m)
+
+	project := loadTestProject(t)
+
+	stack, err := Up(context.Background(), project, WithClient(sdk))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"db", "migrate", "api", "worker"}, stack.Services())
+	require.Equal(t, []string{"shop-db-1", "shop-migrate-1", "shop-api-1", "shop-worker-1"}, m.started)
+
+	t.Run("networks", func(t *testing.T) {
+		networks := m.networks(t)
+		require.Len(t, networks, 3)
+		require.Contains(t, networks, "shop_backend")
+		require.Contains(t, networks, "shop_default")
+		require.True(t, networks["shop_frontend"].Internal)
+		require.Equal(t, "shop", networks["shop_backend"].Labels[LabelProject])
+		require.Equal(t, "backend", networks["shop_backend"].Labels[LabelNetwork])
+
+		nw, err := stack.Network("backend")
+		require.NoError(t, err)
+		require.Equal(t, "shop_backend", nw.Name())
+
+		_, err = stack.Network("missing")
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+	})
+
+	t.Run("volumes", func(t *testing.T) {
+		volumes := m.volumes(t)
+		require.Len(t, volumes, 1)
+		require.Equal(t, "data", volumes["shop_data"].Labels[LabelVolume])
+
+		v, err := stack.Volume("data")
+		require.NoError(t, err)
+		require.Equal(t, "shop_data", v.Name)
+	})
+
+	t.Run("containers", func(t *testing.T) {
+		containers := m.containers(t)
+
+		db := containers["shop-db-1"]
+		require.Equal(t, "postgres:16-alpine", db.Config.Image)
+		require.Contains(t, db.Config.Env, "POSTGRES_PASSWORD=secret")
+		require.Equal(t, "shop", db.Config.Labels[LabelProject])
+		require.Equal(t, "db", db.Config.Labels[LabelService])
+		require.Equal(t, []string{"CMD", "pg_isready", "-U", "postgres"}, db.Config.Healthcheck.Test)
+		require.Equal(t, []mount.Mount{{Type: mount.TypeVolume, Source: "shop_data", Target: "/var/lib/postgresql/data"}}, db.HostConfig.Mounts)
+		require.Equal(t, []string{"db", "database"}, db.NetworkSettings.Networks["shop_backend"].Aliases)
+
+		api := containers["shop-api-1"]
+		require.Equal(t, "api", api.Config.Labels["tier"])
+		require.Contains(t, api.Config.ExposedPorts, dockernetwork.MustParsePort("80/tcp"))
+		require.Equal(t, []mount.Mount{{
+			Type:     mount.TypeBind,
+			Source:   filepath.Join(project.WorkingDir, "static"),
+			Target:   "/usr/share/nginx/html",
+			ReadOnly: true,
+		}}, api.HostConfig.Mounts)
+		require.Contains(t, api.NetworkSettings.Networks, "shop_frontend")
+
+		worker := containers["shop-worker-1"]
+		require.Equal(t, []string{"sleep", "infinity"}, []string(worker.Config.Cmd))
+		require.Contains(t, worker.NetworkSettings.Networks, "shop_default")
+
+		ctr, err := stack.Container("api")
+		require.NoError(t, err)
+		require.Equal(t, api.ID, ctr.ID())
+
+		_, err = stack.Container("missing")
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+	})
+
+	t.Run("down", func(t *testing.T) {
+		require.NoError(t, stack.Down(context.Background(), WithRemoveVolumes()))
+
+		require.Empty(t, m.containers(t))
+		require.Empty(t, m.networks(t))
+		require.Empty(t, m.volumes(t))
+
+		// the containers are removed in reverse start order
+		require.Equal(t, []string{"shop-worker-1", "shop-api-1", "shop-migrate-1", "shop-db-1"}, m.removed)
+	})
+}
+
+func TestUp_keepVolumes(t *testing.T) {
+	m := newComposeMockCli(t)
+	m.exitCodes["shop-migrate-1"] = 0
+
+	sdk := newComposeClient(t, m)
+
+	stack, err := Up(context.Background(), loadTestProject(t), WithClient(sdk))
+	require.NoError(t, err)
+
+	require.NoError(t, stack.Down(context.Background()))
+	require.Empty(t, m.containers(t))
+	require.Empty(t, m.networks(t))
+	require.Contains(t, m.volumes(t), "shop_data")
+}
+
+func TestUp_failure(t *testing.T) {
+	t.Run("completed-unsuccessfully", func(t *testing.T) {
+		m := newComposeMockCli(t)
+		m.exitCodes["shop-migrate-1"] = 3
+
+		sdk := newComposeClient(t, m)
+
+		stack, err := Up(context.Background(), loadTestProject(t), WithClient(sdk))
+		require.ErrorContains(t, err, `service "migrate": container exited with code 3`)
+		require.Nil(t, stack)
+
+		// the dependents are not started, and the resources are torn down
+		require.Equal(t, []string{"shop-db-1", "shop-migrate-1"}, m.started)
+		require.Empty(t, m.containers(t))
+		require.Empty(t, m.networks(t))
+		require.Contains(t, m.volumes(t), "shop_data")
+	})
+
+	t.Run("start-error", func(t *testing.T) {
+		m := newComposeMockCli(t)
+		m.exitCodes["shop-migrate-1"] = 0
+		m.failStart = "shop-api-1"
+
+		sdk := newComposeClient(t, m)
+
+		_, err := Up(context.Background(), loadTestProject(t), WithClient(sdk))
+		require.ErrorContains(t, err, `service "api"`)
+		require.Empty(t, m.containers(t))
+		require.Empty(t, m.networks(t))
+	})
+
+	t.Run("healthy-without-healthcheck", func(t *testing.T) {
+		m := newComposeMockCli(t)
+
+		sdk := newComposeClient(t, m)
+
+		project := loadTestProject(t)
+		db := project.Services["db"]
+		db.Healthcheck = nil
+		project.Services["db"] = db
+
+		_, err := Up(context.Background(), project, WithClient(sdk))
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+		require.Empty(t, m.started)
+	})
+}
+
+func TestUp_serviceCustomizers(t *testing.T) {
+	m := newComposeMockCli(t)
+	m.exitCodes["shop-migrate-1"] = 0
+
+	sdk := newComposeClient(t, m)
+
+	stack, err := Up(context.Background(), loadTestProject(t), WithClient(sdk),
+		WithServiceCustomizers("worker", container.WithEnv(map[string]string{"DEBUG": "1"})),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, stack.Down(context.Background(), WithRemoveVolumes()))
+	})
+
+	env := m.containers(t)["shop-worker-1"].Config.Env
+	require.Contains(t, env, "DEBUG=1")
+	require.Contains(t, env, "API_URL=http://api")
+	require.True(t, slices.Contains(env, "CACHE_PRICE=$5"))
+}
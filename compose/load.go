@@ -0,0 +1,217 @@
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"gopkg.in/yaml.v3"
+)
+
+// invalidProjectNameChars matches the characters not allowed in project names.
+var invalidProjectNameChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// Load loads the compose project defined in the given file. The working directory
+// of the project is the directory of the file, and the name of the project, if not
+// set in the file, is the name of the directory.
+func Load(path string, opts ...LoadOption) (*Project, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("absolute path: %w", err)
+	}
+
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, fmt.Errorf("open compose file: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(f, append([]LoadOption{WithWorkingDir(filepath.Dir(abs))}, opts...)...)
+}
+
+// Parse parses the compose project defined in r. The working directory of the project
+// is the current directory, unless set with [WithWorkingDir].
+func Parse(r io.Reader, opts ...LoadOption) (*Project, error) {
+	loadOpts := &loadOptions{
+		lookupEnv: os.LookupEnv,
+	}
+	for _, opt := range opts {
+		if err := opt(loadOpts); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read compose file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+
+	if err := interpolateNode(&doc, loadOpts.lookupEnv); err != nil {
+		return nil, fmt.Errorf("interpolate compose file: %w", err)
+	}
+
+	project := &Project{}
+	if err := doc.Decode(project); err != nil {
+		return nil, fmt.Errorf("decode compose file: %w", err)
+	}
+
+	project.WorkingDir = loadOpts.workingDir
+	if project.WorkingDir == "" {
+		if project.WorkingDir, err = os.Getwd(); err != nil {
+			return nil, fmt.Errorf("working dir: %w", err)
+		}
+	}
+
+	switch {
+	case loadOpts.projectName != "":
+		project.Name = loadOpts.projectName
+	case project.Name == "":
+		project.Name = filepath.Base(project.WorkingDir)
+	}
+	project.Name = invalidProjectNameChars.ReplaceAllString(strings.ToLower(project.Name), "")
+
+	if err := project.validate(); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// validate checks that the project is consistent: all the services have an image, and
+// all the networks, volumes and services they refer to are defined in the project.
+func (p *Project) validate() error {
+	if p.Name == "" {
+		return errdefs.ErrInvalidArgument.WithMessage("project name is empty")
+	}
+
+	if len(p.Services) == 0 {
+		return errdefs.ErrInvalidArgument.WithMessage("project has no services")
+	}
+
+	var errs []error
+	for name, svc := range p.Services {
+		if svc.Image == "" {
+			errs = append(errs, fmt.Errorf("service %q: image is required", name))
+		}
+
+		for dep, cfg := range svc.DependsOn {
+			if _, ok := p.Services[dep]; !ok {
+				errs = append(errs, fmt.Errorf("service %q: depends on undefined service %q", name, dep))
+			}
+
+			switch cfg.Condition {
+			case ConditionServiceStarted, ConditionServiceHealthy, ConditionServiceCompletedSuccessfully:
+			default:
+				errs = append(errs, fmt.Errorf("service %q: unsupported condition %q for %q", name, cfg.Condition, dep))
+			}
+		}
+
+		for nw := range svc.Networks {
+			if _, ok := p.Networks[nw]; !ok && nw != defaultNetwork {
+				errs = append(errs, fmt.Errorf("service %q: refers to undefined network %q", name, nw))
+			}
+		}
+
+		for _, v := range svc.Volumes {
+			m, err := parseVolumeMount(v, p.WorkingDir)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("service %q: %w", name, err))
+				continue
+			}
+
+			if _, ok := p.Volumes[m.Source]; m.named && !ok {
+				errs = append(errs, fmt.Errorf("service %q: refers to undefined volume %q", name, m.Source))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errdefs.ErrInvalidArgument.WithMessage(errors.Join(errs...).Error())
+	}
+
+	return nil
+}
+
+// interpolateNode replaces the variables in the scalar values of the given node,
+// and its children, with the values returned by lookupEnv.
+func interpolateNode(node *yaml.Node, lookupEnv func(string) (string, bool)) error {
+	if node.Kind == yaml.ScalarNode {
+		v, err := interpolate(node.Value, lookupEnv)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = v
+		return nil
+	}
+
+	for _, child := range node.Content {
+		if err := interpolateNode(child, lookupEnv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// interpolate replaces the variables in s, following the compose syntax:
+//   - $VAR and ${VAR} are replaced with the value of VAR, or an empty string if it's not set.
+//   - ${VAR:-default} and ${VAR-default} are replaced with default if VAR is unset or empty,
+//     or only if VAR is unset, respectively.
+//   - ${VAR:?error} and ${VAR?error} fail with the given error if VAR is unset or empty,
+//     or only if VAR is unset, respectively.
+//   - $$ is replaced with a literal $.
+func interpolate(s string, lookupEnv func(string) (string, bool)) (string, error) {
+	var errs []error
+
+	out := os.Expand(s, func(expr string) string {
+		if expr == "$" {
+			return "$"
+		}
+
+		i := strings.IndexFunc(expr, func(r rune) bool {
+			return r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z')
+		})
+		if i < 0 {
+			v, _ := lookupEnv(expr)
+			return v
+		}
+
+		name, rest := expr[:i], expr[i:]
+		v, ok := lookupEnv(name)
+
+		for _, op := range []string{":-", ":?", "-", "?"} {
+			arg, found := strings.CutPrefix(rest, op)
+			if !found {
+				continue
+			}
+
+			unset := !ok || (strings.HasPrefix(op, ":") && v == "")
+
+			switch {
+			case !unset:
+				return v
+			case strings.HasSuffix(op, "?"):
+				errs = append(errs, fmt.Errorf("required variable %s is missing: %s", name, arg))
+				return ""
+			default:
+				return arg
+			}
+		}
+
+		errs = append(errs, fmt.Errorf("invalid interpolation format: ${%s}", expr))
+		return ""
+	})
+
+	return out, errors.Join(errs...)
+}
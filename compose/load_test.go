@@ -0,0 +1,175 @@
+package compose
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	project, err := Load(filepath.Join("testdata", "compose.yaml"), WithEnv(map[string]string{}))
+	require.NoError(t, err)
+
+	require.Equal(t, "shop", project.Name)
+	require.True(t, filepath.IsAbs(project.WorkingDir))
+	require.Len(t, project.Services, 4)
+	require.Contains(t, project.Networks, "backend")
+	require.True(t, project.Networks["frontend"].Internal)
+	require.Contains(t, project.Volumes, "data")
+
+	db := project.Services["db"]
+	require.Equal(t, "postgres:16-alpine", db.Image)
+	require.Equal(t, Mapping{"POSTGRES_PASSWORD": "secret", "POSTGRES_DB": "shop"}, db.Environment)
+	require.Equal(t, []string{"database"}, db.Networks["backend"].Aliases)
+	require.Equal(t, HealthcheckTest{"CMD", "pg_isready", "-U", "postgres"}, db.Healthcheck.Test)
+	require.Equal(t, Duration(time.Second), db.Healthcheck.Interval)
+	require.Equal(t, 10, db.Healthcheck.Retries)
+
+	migrate := project.Services["migrate"]
+	require.Equal(t, ShellCommand{"sh", "-c", "echo 'migrated'"}, migrate.Command)
+	require.Equal(t, ConditionServiceHealthy, migrate.DependsOn["db"].Condition)
+
+	api := project.Services["api"]
+	require.Equal(t, []string{"8080:80"}, api.Ports)
+	require.Equal(t, Mapping{"tier": "api"}, api.Labels)
+	require.Equal(t, HealthcheckTest{"CMD-SHELL", "wget -q -O /dev/null http://localhost"}, api.Healthcheck.Test)
+	require.Equal(t, ConditionServiceCompletedSuccessfully, api.DependsOn["migrate"].Condition)
+
+	worker := project.Services["worker"]
+	require.Equal(t, ShellCommand{"sleep", "infinity"}, worker.Command)
+	require.Equal(t, Mapping{"API_URL": "http://api", "CACHE_PRICE": "$5"}, worker.Environment)
+	require.Equal(t, ConditionServiceStarted, worker.DependsOn["api"].Condition)
+
+	order, err := project.startOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"db", "migrate", "api", "worker"}, order)
+
+	t.Run("with-env", func(t *testing.T) {
+		project, err := Load(filepath.Join("testdata", "compose.yaml"), WithEnv(map[string]string{"DB_PASSWORD": "s3cr3t"}))
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", project.Services["db"].Environment["POSTGRES_PASSWORD"])
+	})
+
+	t.Run("with-project-name", func(t *testing.T) {
+		project, err := Load(filepath.Join("testdata", "compose.yaml"), WithProjectName("My.Shop"))
+		require.NoError(t, err)
+		require.Equal(t, "myshop", project.Name)
+	})
+
+	t.Run("file-not-found", func(t *testing.T) {
+		_, err := Load(filepath.Join("testdata", "missing.yaml"))
+		require.Error(t, err)
+	})
+}
+
+func TestParse_invalid(t *testing.T) {
+	parse := func(t *testing.T, yaml string) error {
+		t.Helper()
+
+		_, err := Parse(strings.NewReader(yaml), WithProjectName("test"), WithEnv(map[string]string{}))
+		return err
+	}
+
+	tests := map[string]struct {
+		yaml string
+		err  string
+	}{
+		"no-services": {
+			yaml: "networks: {}",
+			err:  "project has no services",
+		},
+		"no-image": {
+			yaml: "services: {app: {command: [true]}}",
+			err:  `service "app": image is required`,
+		},
+		"undefined-dependency": {
+			yaml: "services: {app: {image: alpine, depends_on: [db]}}",
+			err:  `depends on undefined service "db"`,
+		},
+		"unsupported-condition": {
+			yaml: "services: {app: {image: alpine, depends_on: {db: {condition: service_ready}}}, db: {image: alpine}}",
+			err:  `unsupported condition "service_ready"`,
+		},
+		"undefined-network": {
+			yaml: "services: {app: {image: alpine, networks: [backend]}}",
+			err:  `undefined network "backend"`,
+		},
+		"undefined-volume": {
+			yaml: "services: {app: {image: alpine, volumes: ['data:/data']}}",
+			err:  `undefined volume "data"`,
+		},
+		"required-variable": {
+			yaml: "services: {app: {image: '${IMAGE:?image is required}'}}",
+			err:  "required variable IMAGE is missing: image is required",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := parse(t, tc.yaml)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	t.Run("dependency-cycle", func(t *testing.T) {
+		project, err := Parse(strings.NewReader("services: {a: {image: alpine, depends_on: [b]}, b: {image: alpine, depends_on: [a]}, c: {image: alpine}}"), WithProjectName("test"))
+		require.NoError(t, err)
+
+		_, err = project.startOrder()
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+		require.ErrorContains(t, err, "a, b")
+	})
+}
+
+func TestParseVolumeMount(t *testing.T) {
+	m, err := parseVolumeMount("data:/data:ro", "/work")
+	require.NoError(t, err)
+	require.Equal(t, volumeMount{Source: "data", Target: "/data", ReadOnly: true, named: true}, m)
+
+	m, err = parseVolumeMount("./static:/srv", "/work")
+	require.NoError(t, err)
+	require.Equal(t, volumeMount{Source: filepath.Join("/work", "static"), Target: "/srv"}, m)
+
+	m, err = parseVolumeMount("/cache", "/work")
+	require.NoError(t, err)
+	require.Equal(t, volumeMount{Target: "/cache"}, m)
+
+	_, err = parseVolumeMount("data:relative", "/work")
+	require.Error(t, err)
+}
+
+func TestInterpolate(t *testing.T) {
+	env := func(key string) (string, bool) {
+		v, ok := map[string]string{"SET": "value", "EMPTY": ""}[key]
+		return v, ok
+	}
+
+	tests := map[string]string{
+		"$SET":              "value",
+		"${SET}":            "value",
+		"${UNSET}":          "",
+		"${UNSET:-default}": "default",
+		"${EMPTY:-default}": "default",
+		"${EMPTY-default}":  "",
+		"${UNSET-default}":  "default",
+		"${SET:?error}":     "value",
+		"$$SET":             "$SET",
+		"a $ b":             "a $ b",
+	}
+
+	for in, want := range tests {
+		got, err := interpolate(in, env)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	_, err := interpolate("${EMPTY:?must be set}", env)
+	require.ErrorContains(t, err, "required variable EMPTY is missing: must be set")
+
+	_, err = interpolate("${SET/x}", env)
+	require.ErrorContains(t, err, "invalid interpolation format")
+}
@@ -0,0 +1,81 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/mount"
+)
+
+// volumeMount is a volume of a service, defined with the short syntax:
+// "[SOURCE:]TARGET[:MODE]".
+type volumeMount struct {
+	// Source is the name of the named volume, or the absolute path of the bind mount.
+	// It's empty for anonymous volumes.
+	Source string
+
+	// Target is the path in the container the volume is mounted at.
+	Target string
+
+	// ReadOnly is true if the volume is mounted in read-only mode.
+	ReadOnly bool
+
+	// named is true if the source is a named volume of the project.
+	named bool
+}
+
+// parseVolumeMount parses a volume of a service, defined with the short syntax.
+// The relative paths of the bind mounts are resolved from the working dir.
+func parseVolumeMount(spec, workingDir string) (volumeMount, error) {
+	parts := strings.Split(spec, ":")
+
+	var m volumeMount
+	switch len(parts) {
+	case 1:
+		m.Target = parts[0]
+	case 2, 3:
+		m.Source, m.Target = parts[0], parts[1]
+		if len(parts) == 3 {
+			m.ReadOnly = slices.Contains(strings.Split(parts[2], ","), "ro")
+		}
+	default:
+		return m, fmt.Errorf("invalid volume %q", spec)
+	}
+
+	if !filepath.IsAbs(m.Target) && !strings.HasPrefix(m.Target, "/") {
+		return m, fmt.Errorf("invalid volume %q: target must be an absolute path", spec)
+	}
+
+	switch {
+	case m.Source == "":
+	case m.Source == "~" || strings.HasPrefix(m.Source, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return m, fmt.Errorf("home dir: %w", err)
+		}
+		m.Source = filepath.Join(home, strings.TrimPrefix(m.Source, "~"))
+	case strings.HasPrefix(m.Source, "."):
+		m.Source = filepath.Join(workingDir, m.Source)
+	case filepath.IsAbs(m.Source) || strings.HasPrefix(m.Source, "/"):
+	default:
+		m.named = true
+	}
+
+	return m, nil
+}
+
+// mount returns the Docker mount for the volume, using the given
+// names of the named volumes of the project.
+func (m volumeMount) mount(volumeNames map[string]string) mount.Mount {
+	switch {
+	case m.named:
+		return mount.Mount{Type: mount.TypeVolume, Source: volumeNames[m.Source], Target: m.Target, ReadOnly: m.ReadOnly}
+	case m.Source == "":
+		return mount.Mount{Type: mount.TypeVolume, Target: m.Target, ReadOnly: m.ReadOnly}
+	default:
+		return mount.Mount{Type: mount.TypeBind, Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly}
+	}
+}
@@ -0,0 +1,93 @@
+package compose
+
+import (
+	"errors"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/container"
+)
+
+type loadOptions struct {
+	projectName string
+	workingDir  string
+	lookupEnv   func(string) (string, bool)
+}
+
+// LoadOption is a function that configures how a compose file is loaded.
+type LoadOption func(*loadOptions) error
+
+// WithProjectName sets the name of the project, overriding the one in the compose file.
+func WithProjectName(name string) LoadOption {
+	return func(o *loadOptions) error {
+		if name == "" {
+			return errors.New("project name is empty")
+		}
+		o.projectName = name
+		return nil
+	}
+}
+
+// WithWorkingDir sets the directory the relative paths of the bind mounts are resolved from.
+func WithWorkingDir(dir string) LoadOption {
+	return func(o *loadOptions) error {
+		o.workingDir = dir
+		return nil
+	}
+}
+
+// WithEnv sets the variables used to interpolate the compose file,
+// instead of the environment variables of the current process.
+func WithEnv(env map[string]string) LoadOption {
+	return func(o *loadOptions) error {
+		o.lookupEnv = func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		}
+		return nil
+	}
+}
+
+type upOptions struct {
+	client      client.SDKClient
+	customizers map[string][]container.ContainerCustomizer
+}
+
+// UpOption is a function that configures how a compose project is started.
+type UpOption func(*upOptions) error
+
+// WithClient sets the client used to create the resources of the project.
+// If not set, a new client is created with the default options.
+func WithClient(cli client.SDKClient) UpOption {
+	return func(o *upOptions) error {
+		o.client = cli
+		return nil
+	}
+}
+
+// WithServiceCustomizers adds the given customizers to the container of the given service,
+// which are applied after the ones derived from the compose file.
+func WithServiceCustomizers(service string, customizers ...container.ContainerCustomizer) UpOption {
+	return func(o *upOptions) error {
+		if o.customizers == nil {
+			o.customizers = make(map[string][]container.ContainerCustomizer)
+		}
+		o.customizers[service] = append(o.customizers[service], customizers...)
+		return nil
+	}
+}
+
+type downOptions struct {
+	removeVolumes bool
+}
+
+// DownOption is a function that configures how a compose project is torn down.
+type DownOption func(*downOptions) error
+
+// WithRemoveVolumes removes the named volumes created for the project when tearing it down.
+// By default, they are kept, like docker compose down does.
+func WithRemoveVolumes() DownOption {
+	return func(o *downOptions) error {
+		o.removeVolumes = true
+		return nil
+	}
+}
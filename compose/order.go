@@ -0,0 +1,73 @@
+package compose
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+)
+
+// startOrder returns the names of the services of the project, sorted so that each
+// service comes after the services it depends on. Services without dependencies
+// between them are sorted by name, so the order is stable.
+func (p *Project) startOrder() ([]string, error) {
+	// pending is the number of dependencies of each service not started yet
+	pending := make(map[string]int, len(p.Services))
+	dependents := make(map[string][]string, len(p.Services))
+	for name, svc := range p.Services {
+		pending[name] = len(svc.DependsOn)
+		for dep := range svc.DependsOn {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for _, name := range slices.Sorted(maps.Keys(pending)) {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(p.Services))
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+
+		var next []string
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				next = append(next, dependent)
+			}
+		}
+		slices.Sort(next)
+		ready = append(ready, next...)
+	}
+
+	if len(order) < len(p.Services) {
+		var cycle []string
+		for name, n := range pending {
+			if n > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		slices.Sort(cycle)
+		return nil, errdefs.ErrInvalidArgument.WithMessage("dependency cycle between services: " + strings.Join(cycle, ", "))
+	}
+
+	return order, nil
+}
+
+// conditions returns the conditions the dependents of the given service
+// require it to meet before they are started.
+func (p *Project) conditions(service string) map[string]bool {
+	conditions := make(map[string]bool)
+	for _, svc := range p.Services {
+		if dep, ok := svc.DependsOn[service]; ok {
+			conditions[dep.Condition] = true
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,322 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dependency conditions of a service on another service, set in its depends_on section.
+const (
+	// ConditionServiceStarted waits for the dependency to be started.
+	ConditionServiceStarted = "service_started"
+
+	// ConditionServiceHealthy waits for the dependency to be healthy, using its healthcheck.
+	ConditionServiceHealthy = "service_healthy"
+
+	// ConditionServiceCompletedSuccessfully waits for the dependency to exit with code 0.
+	ConditionServiceCompletedSuccessfully = "service_completed_successfully"
+)
+
+// Project is a compose project, parsed from a compose file.
+type Project struct {
+	// Name is the name of the project, used as prefix for the names of its resources.
+	Name string `yaml:"name"`
+
+	// WorkingDir is the directory the relative paths of the bind mounts are resolved from.
+	WorkingDir string `yaml:"-"`
+
+	// Services are the services of the project, by name.
+	Services map[string]Service `yaml:"services"`
+
+	// Networks are the networks of the project, by name.
+	Networks map[string]Network `yaml:"networks"`
+
+	// Volumes are the named volumes of the project, by name.
+	Volumes map[string]Volume `yaml:"volumes"`
+}
+
+// Service is a service of a compose project, which runs as a single container.
+type Service struct {
+	Image         string            `yaml:"image"`
+	ContainerName string            `yaml:"container_name"`
+	Command       ShellCommand      `yaml:"command"`
+	Entrypoint    ShellCommand      `yaml:"entrypoint"`
+	Environment   Mapping           `yaml:"environment"`
+	Labels        Mapping           `yaml:"labels"`
+	Ports         []string          `yaml:"ports"`
+	Volumes       []string          `yaml:"volumes"`
+	Networks      ServiceNetworks   `yaml:"networks"`
+	DependsOn     DependsOn         `yaml:"depends_on"`
+	Healthcheck   *Healthcheck      `yaml:"healthcheck"`
+	User          string            `yaml:"user"`
+	WorkingDir    string            `yaml:"working_dir"`
+	Privileged    bool              `yaml:"privileged"`
+	ExtraHosts    []string          `yaml:"extra_hosts"`
+	Tmpfs         []string          `yaml:"tmpfs"`
+	Sysctls       map[string]string `yaml:"sysctls"`
+}
+
+// Network is a network of a compose project.
+type Network struct {
+	// Name is the name of the network. By default, it's the name of the project
+	// and the name of the network, separated by an underscore.
+	Name       string  `yaml:"name"`
+	Driver     string  `yaml:"driver"`
+	External   bool    `yaml:"external"`
+	Internal   bool    `yaml:"internal"`
+	Attachable bool    `yaml:"attachable"`
+	EnableIPv6 bool    `yaml:"enable_ipv6"`
+	Labels     Mapping `yaml:"labels"`
+}
+
+// Volume is a named volume of a compose project.
+type Volume struct {
+	// Name is the name of the volume. By default, it's the name of the project
+	// and the name of the volume, separated by an underscore.
+	Name     string  `yaml:"name"`
+	External bool    `yaml:"external"`
+	Labels   Mapping `yaml:"labels"`
+}
+
+// Healthcheck is the healthcheck of a service.
+type Healthcheck struct {
+	Test        HealthcheckTest `yaml:"test"`
+	Interval    Duration        `yaml:"interval"`
+	Timeout     Duration        `yaml:"timeout"`
+	StartPeriod Duration        `yaml:"start_period"`
+	Retries     int             `yaml:"retries"`
+	Disable     bool            `yaml:"disable"`
+}
+
+// Dependency is the dependency of a service on another service.
+type Dependency struct {
+	// Condition is the condition the dependency must meet before starting the service.
+	// Default: [ConditionServiceStarted].
+	Condition string `yaml:"condition"`
+}
+
+// ServiceNetwork is the attachment of a service to a network.
+type ServiceNetwork struct {
+	Aliases []string `yaml:"aliases"`
+}
+
+// ShellCommand is a command, which can be defined as a list of arguments,
+// or as a string, split into arguments following the shell rules.
+type ShellCommand []string
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (c *ShellCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		args, err := splitShellWords(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*c = args
+		return nil
+	}
+
+	var args []string
+	if err := node.Decode(&args); err != nil {
+		return err
+	}
+	*c = args
+	return nil
+}
+
+// HealthcheckTest is the test of a healthcheck, in the format of the Docker API:
+// a string is run with the shell of the container, as "CMD-SHELL".
+type HealthcheckTest []string
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (t *HealthcheckTest) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = HealthcheckTest{"CMD-SHELL", node.Value}
+		return nil
+	}
+
+	var test []string
+	if err := node.Decode(&test); err != nil {
+		return err
+	}
+	*t = test
+	return nil
+}
+
+// Duration is a duration, in the format of [time.ParseDuration], e.g. "1m30s".
+type Duration time.Duration
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Mapping is a set of key-value pairs, like environment variables or labels, which can be
+// defined as a map, or as a list of "KEY=VALUE" items. Items without a value, defined
+// as "KEY" in a list, or as a null value in a map, have an empty value.
+type Mapping map[string]string
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (m *Mapping) UnmarshalYAML(node *yaml.Node) error {
+	mapping := make(Mapping)
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var items []string
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+		for _, item := range items {
+			k, v, _ := strings.Cut(item, "=")
+			mapping[k] = v
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if v.Tag == "!!null" {
+				mapping[k.Value] = ""
+				continue
+			}
+			if v.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: value of %q must be a scalar", v.Line, k.Value)
+			}
+			mapping[k.Value] = v.Value
+		}
+	default:
+		return fmt.Errorf("line %d: must be a map or a list", node.Line)
+	}
+
+	*m = mapping
+	return nil
+}
+
+// ServiceNetworks are the networks a service is attached to, which can be defined
+// as a list of network names, or as a map of network names to their attachments.
+type ServiceNetworks map[string]ServiceNetwork
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (n *ServiceNetworks) UnmarshalYAML(node *yaml.Node) error {
+	networks := make(ServiceNetworks)
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+		for _, name := range names {
+			networks[name] = ServiceNetwork{}
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			var attachment ServiceNetwork
+			if v := node.Content[i+1]; v.Tag != "!!null" {
+				if err := v.Decode(&attachment); err != nil {
+					return err
+				}
+			}
+			networks[node.Content[i].Value] = attachment
+		}
+	default:
+		return fmt.Errorf("line %d: networks must be a map or a list", node.Line)
+	}
+
+	*n = networks
+	return nil
+}
+
+// DependsOn are the dependencies of a service, which can be defined as a list of service
+// names, started before the service, or as a map of service names to their conditions.
+type DependsOn map[string]Dependency
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (d *DependsOn) UnmarshalYAML(node *yaml.Node) error {
+	deps := make(DependsOn)
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+		for _, name := range names {
+			deps[name] = Dependency{Condition: ConditionServiceStarted}
+		}
+	case yaml.MappingNode:
+		var m map[string]Dependency
+		if err := node.Decode(&m); err != nil {
+			return err
+		}
+		for name, dep := range m {
+			if dep.Condition == "" {
+				dep.Condition = ConditionServiceStarted
+			}
+			deps[name] = dep
+		}
+	default:
+		return fmt.Errorf("line %d: depends_on must be a map or a list", node.Line)
+	}
+
+	*d = deps
+	return nil
+}
+
+// splitShellWords splits s into words, following the quoting rules of the shell:
+// words are separated by blanks, and single quotes, double quotes and backslashes
+// can be used to include blanks in a word.
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape in command")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
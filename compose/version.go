@@ -0,0 +1,13 @@
+package compose
+
+import "github.com/docker/go-sdk/client"
+
+const (
+	version     = "0.1.0-alpha001"
+	moduleLabel = client.LabelBase + ".compose"
+)
+
+// Version returns the version of the compose package.
+func Version() string {
+	return version
+}
@@ -0,0 +1,30 @@
+package container
+
+import (
+	"context"
+	"iter"
+
+	"github.com/moby/moby/api/types/events"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// Events returns the events of the container, like "oom" or "die", until the context is done,
+// or an error occurs, which is yielded last. The events can be filtered further using the
+// [client.EventsOption] options, e.g. [client.EventsActions].
+//
+// It can be used to react to the container being killed because it ran out of memory,
+// or exiting unexpectedly:
+//
+//	for event, err := range ctr.Events(ctx, client.EventsActions(events.ActionOOM, events.ActionDie)) {
+//		if err != nil {
+//			return err
+//		}
+//		if code, ok := event.ExitCode(); ok {
+//			log.Printf("container exited with code %d", code)
+//		}
+//	}
+func (c *Container) Events(ctx context.Context, opts ...client.EventsOption) iter.Seq2[client.Event, error] {
+	opts = append([]client.EventsOption{client.EventsResource(events.ContainerEventType, c.ID())}, opts...)
+	return c.dockerClient.SubscribeEvents(ctx, opts...)
+}
@@ -0,0 +1,40 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/container"
+	"github.com/docker/go-sdk/container/wait"
+)
+
+func TestContainer_Events(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := container.Run(ctx,
+		container.WithImage(alpineLatest),
+		container.WithCmd("sh", "-c", "sleep 1 && exit 3"),
+		container.WithWaitStrategy(wait.ForNop(func(_ context.Context, _ wait.StrategyTarget) error { return nil })),
+	)
+	container.Cleanup(t, ctr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	since := client.EventsSince(time.Now().Add(-time.Minute))
+	for event, err := range ctr.Events(ctx, since, client.EventsActions(events.ActionDie)) {
+		require.NoError(t, err)
+		require.Equal(t, ctr.ID(), event.ID)
+
+		code, ok := event.ExitCode()
+		require.True(t, ok)
+		require.Equal(t, 3, code)
+		break
+	}
+}
@@ -0,0 +1,317 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	dockerclient "github.com/moby/moby/client"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/container/exec"
+)
+
+// execWaitMaxInterval is the maximum interval between the inspections of an exec instance
+// whose output is closed, while waiting for its exit code.
+const execWaitMaxInterval = 500 * time.Millisecond
+
+// ttySignals maps the signals that can be delivered to a process attached to a TTY
+// to the control character that makes the terminal raise them.
+var ttySignals = map[string]byte{
+	"SIGINT":  0x03, // Ctrl-C
+	"SIGQUIT": 0x1c, // Ctrl-\
+	"SIGTSTP": 0x1a, // Ctrl-Z
+}
+
+// ExecProcess is a handle to a process started with [Container.ExecStream].
+// It gives access to the process standard streams while the process is running,
+// and allows to wait for its exit code, resize its TTY and send signals to it.
+type ExecProcess struct {
+	dockerClient client.SDKClient
+
+	// execID the ID of the exec instance in the Docker daemon.
+	execID string
+
+	// tty whether the process was started with a TTY attached.
+	tty bool
+
+	// hijack the hijacked connection to the exec instance.
+	hijack dockerclient.HijackedResponse
+
+	stdin  *execStdin
+	stdout *io.PipeReader
+	stderr *io.PipeReader
+
+	// done is closed once the output of the process has been fully consumed.
+	done chan struct{}
+
+	// copyErr the error, if any, found while copying the output of the process.
+	copyErr error
+
+	// mtx protects the fields below.
+	mtx      sync.Mutex
+	exited   bool
+	exitCode int
+}
+
+// ExecStream starts the command in the current container and returns immediately,
+// without waiting for the command to finish. The returned [ExecProcess] exposes the
+// standard input of the process and its standard output and error as separate streams,
+// that can be consumed while the process is running.
+//
+// The standard input is always attached: processes reading from it will block until
+// it is closed, so call [ExecProcess.CloseStdin] once there is nothing else to send.
+//
+// When the process runs without a TTY, the standard output and error are
+// demultiplexed into two synchronous pipes: as with [os/exec.Cmd.StdoutPipe], both
+// streams must be read concurrently, otherwise the process could block writing
+// to the one that is not consumed. With [exec.WithTTY], all the output is sent
+// to [ExecProcess.Stdout] and [ExecProcess.Stderr] is always empty.
+//
+// The process is detached from the connection when the context is done.
+func (c *Container) ExecStream(ctx context.Context, cmd []string, options ...exec.ProcessOption) (*ExecProcess, error) {
+	processOptions := exec.NewProcessOptions(cmd)
+	processOptions.ExecConfig.AttachStdin = true
+
+	for _, o := range options {
+		o.Apply(processOptions)
+	}
+
+	response, err := c.dockerClient.ExecCreate(ctx, c.ID(), processOptions.ExecConfig)
+	if err != nil {
+		return nil, fmt.Errorf("container exec create: %w", err)
+	}
+
+	hijack, err := c.dockerClient.ExecAttach(ctx, response.ID, dockerclient.ExecAttachOptions{
+		TTY:         processOptions.ExecConfig.TTY,
+		ConsoleSize: processOptions.ExecConfig.ConsoleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("container exec attach: %w", err)
+	}
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+
+	p := &ExecProcess{
+		dockerClient: c.dockerClient,
+		execID:       response.ID,
+		tty:          processOptions.ExecConfig.TTY,
+		hijack:       hijack.HijackedResponse,
+		stdout:       stdoutReader,
+		stderr:       stderrReader,
+		done:         make(chan struct{}),
+	}
+	p.stdin = &execStdin{hijack: &p.hijack}
+
+	go p.copyOutput(stdoutWriter, stderrWriter)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.hijack.Close()
+		case <-p.done:
+		}
+	}()
+
+	return p, nil
+}
+
+// copyOutput copies the output of the process to the stdout and stderr pipes,
+// demultiplexing the stream when no TTY is attached.
+func (p *ExecProcess) copyOutput(stdout, stderr *io.PipeWriter) {
+	defer close(p.done)
+	defer p.hijack.Close()
+
+	var err error
+	if p.tty {
+		_, err = io.Copy(stdout, p.hijack.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, p.hijack.Reader)
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		p.copyErr = fmt.Errorf("copying output: %w", err)
+	}
+
+	stdout.CloseWithError(p.copyErr)
+	stderr.CloseWithError(p.copyErr)
+}
+
+// ID returns the ID of the exec instance.
+func (p *ExecProcess) ID() string {
+	return p.execID
+}
+
+// Stdin returns a writer connected to the standard input of the process.
+// Closing it is equivalent to calling [ExecProcess.CloseStdin].
+func (p *ExecProcess) Stdin() io.WriteCloser {
+	return p.stdin
+}
+
+// Stdout returns a reader with the standard output of the process.
+// It returns [io.EOF] once the process has exited and all the output has been read.
+func (p *ExecProcess) Stdout() io.Reader {
+	return p.stdout
+}
+
+// Stderr returns a reader with the standard error of the process.
+// It returns [io.EOF] once the process has exited and all the output has been read.
+func (p *ExecProcess) Stderr() io.Reader {
+	return p.stderr
+}
+
+// CloseStdin closes the standard input of the process, signaling EOF to it.
+// The output streams are kept open until the process exits.
+func (p *ExecProcess) CloseStdin() error {
+	return p.stdin.Close()
+}
+
+// Done returns a channel that is closed once the output of the process
+// has been fully consumed, which happens when the process exits.
+func (p *ExecProcess) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for the process to exit and returns its exit code.
+// The output streams must be consumed for the process to be able to finish.
+// It can be called multiple times, returning the same exit code.
+// If the output of the process could not be fully copied, e.g. because the connection
+// was closed, the copy error is returned together with the exit code.
+func (p *ExecProcess) Wait(ctx context.Context) (int, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-p.done:
+	}
+
+	// copyErr is set before done is closed, so it's safe to read it from here
+	p.mtx.Lock()
+	exited, exitCode := p.exited, p.exitCode
+	p.mtx.Unlock()
+
+	if exited {
+		return exitCode, p.copyErr
+	}
+
+	// The output stream is closed by the daemon when the process exits,
+	// but the exec instance could still be reported as running for
+	// a brief moment, so retry until its exit code is available.
+	interval := 10 * time.Millisecond
+	for {
+		execResp, err := p.dockerClient.ExecInspect(ctx, p.execID, dockerclient.ExecInspectOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("container exec inspect: %w", err)
+		}
+
+		if !execResp.Running {
+			p.mtx.Lock()
+			p.exited = true
+			p.exitCode = execResp.ExitCode
+			p.mtx.Unlock()
+
+			return execResp.ExitCode, p.copyErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*2, execWaitMaxInterval)
+	}
+}
+
+// Resize resizes the TTY of the process.
+// It returns an error if the process was not started with [exec.WithTTY].
+func (p *ExecProcess) Resize(ctx context.Context, height uint, width uint) error {
+	if !p.tty {
+		return errdefs.ErrInvalidArgument.WithMessage("resize is only supported when TTY is enabled")
+	}
+
+	if _, err := p.dockerClient.ExecResize(ctx, p.execID, dockerclient.ExecResizeOptions{
+		Height: height,
+		Width:  width,
+	}); err != nil {
+		return fmt.Errorf("container exec resize: %w", err)
+	}
+
+	return nil
+}
+
+// Signal sends a signal to the process, e.g. "SIGINT".
+//
+// The Docker Engine API does not provide a way to signal an exec instance,
+// so signals are delivered through the TTY of the process, writing the control
+// character that makes the terminal raise them, as an interactive user would do.
+// For that reason, only SIGINT, SIGQUIT and SIGTSTP are supported, and only
+// for processes started with [exec.WithTTY].
+func (p *ExecProcess) Signal(_ context.Context, signal string) error {
+	if !p.tty {
+		return errdefs.ErrNotImplemented.WithMessage("signals are only supported when TTY is enabled")
+	}
+
+	sig := strings.ToUpper(signal)
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+
+	ctrl, ok := ttySignals[sig]
+	if !ok {
+		return errdefs.ErrNotImplemented.WithMessage(fmt.Sprintf("signal %s is not supported", signal))
+	}
+
+	if _, err := p.stdin.Write([]byte{ctrl}); err != nil {
+		return fmt.Errorf("signal %s: %w", sig, err)
+	}
+
+	return nil
+}
+
+// Close closes the connection to the process, without waiting for it to exit.
+// The process keeps running in the container, but its streams are no longer available.
+func (p *ExecProcess) Close() error {
+	p.hijack.Close()
+	return nil
+}
+
+// execStdin is the standard input of an exec instance.
+type execStdin struct {
+	hijack *dockerclient.HijackedResponse
+
+	mtx    sync.Mutex
+	closed bool
+}
+
+// Write writes p to the standard input of the process.
+func (s *execStdin) Write(p []byte) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	return s.hijack.Conn.Write(p)
+}
+
+// Close closes the write side of the hijacked connection.
+// It is safe to call it multiple times.
+func (s *execStdin) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	return s.hijack.CloseWrite()
+}
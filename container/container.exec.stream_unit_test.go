@@ -0,0 +1,256 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/container/exec"
+)
+
+// execMockCli is a mock implementation of client.APIClient, which simulates
+// an exec instance whose output is written by the serve function.
+type execMockCli struct {
+	mockCli
+
+	serve    func(conn net.Conn)
+	exitCode int
+
+	mtx         sync.Mutex
+	createOpts  dockerclient.ExecCreateOptions
+	resizeOpts  dockerclient.ExecResizeOptions
+	inspectRuns int
+}
+
+func (m *execMockCli) ExecCreate(_ context.Context, _ string, options dockerclient.ExecCreateOptions) (dockerclient.ExecCreateResult, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.createOpts = options
+	return dockerclient.ExecCreateResult{ID: "exec-id"}, nil
+}
+
+func (m *execMockCli) ExecAttach(_ context.Context, _ string, _ dockerclient.ExecAttachOptions) (dockerclient.ExecAttachResult, error) {
+	clientConn, serverConn := net.Pipe()
+	go m.serve(serverConn)
+
+	return dockerclient.ExecAttachResult{
+		HijackedResponse: dockerclient.HijackedResponse{
+			Conn:   clientConn,
+			Reader: bufio.NewReader(clientConn),
+		},
+	}, nil
+}
+
+func (m *execMockCli) ExecInspect(_ context.Context, _ string, _ dockerclient.ExecInspectOptions) (dockerclient.ExecInspectResult, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.inspectRuns++
+	// report the exec instance as running the first time, to simulate
+	// the race between the stream being closed and the exit code being set.
+	return dockerclient.ExecInspectResult{
+		ID:       "exec-id",
+		Running:  m.inspectRuns == 1,
+		ExitCode: m.exitCode,
+	}, nil
+}
+
+func (m *execMockCli) ExecResize(_ context.Context, _ string, options dockerclient.ExecResizeOptions) (dockerclient.ExecResizeResult, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.resizeOpts = options
+	return dockerclient.ExecResizeResult{}, nil
+}
+
+// writeFrame writes p to w using the multiplexed stream format.
+func writeFrame(tb testing.TB, w io.Writer, stream byte, p string) {
+	tb.Helper()
+
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(p)))
+
+	_, err := w.Write(append(header, p...))
+	require.NoError(tb, err)
+}
+
+func TestContainer_ExecStream(t *testing.T) {
+	t.Run("separate-streams", func(t *testing.T) {
+		m := &execMockCli{
+			exitCode: 3,
+			serve: func(conn net.Conn) {
+				defer conn.Close()
+
+				// echo the standard input to stdout, and a fixed message to stderr
+				in := make([]byte, len("hello"))
+				_, err := io.ReadFull(conn, in)
+				require.NoError(t, err)
+
+				writeFrame(t, conn, 1, string(in))
+				writeFrame(t, conn, 2, "oops")
+			},
+		}
+
+		ctr := newMockContainer(t, m)
+
+		p, err := ctr.ExecStream(context.Background(), []string{"cat"}, exec.WithUser("root"))
+		require.NoError(t, err)
+		require.Equal(t, "exec-id", p.ID())
+		require.True(t, m.createOpts.AttachStdin)
+		require.Equal(t, "root", m.createOpts.User)
+
+		_, err = p.Stdin().Write([]byte("hello"))
+		require.NoError(t, err)
+
+		var stdout, stderr []byte
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			stdout, _ = io.ReadAll(p.Stdout())
+		}()
+		go func() {
+			defer wg.Done()
+			stderr, _ = io.ReadAll(p.Stderr())
+		}()
+		wg.Wait()
+
+		require.Equal(t, "hello", string(stdout))
+		require.Equal(t, "oops", string(stderr))
+
+		code, err := p.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+
+		// the exit code is cached
+		code, err = p.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+		require.Equal(t, 2, m.inspectRuns)
+
+		require.NoError(t, p.CloseStdin())
+		_, err = p.Stdin().Write([]byte("closed"))
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+
+	t.Run("tty", func(t *testing.T) {
+		received := make(chan byte, 1)
+		m := &execMockCli{
+			serve: func(conn net.Conn) {
+				defer conn.Close()
+
+				b := make([]byte, 1)
+				_, err := io.ReadFull(conn, b)
+				require.NoError(t, err)
+				received <- b[0]
+
+				_, err = conn.Write([]byte("^C"))
+				require.NoError(t, err)
+			},
+		}
+
+		ctr := newMockContainer(t, m)
+
+		p, err := ctr.ExecStream(context.Background(), []string{"sh"}, exec.WithTTY(true))
+		require.NoError(t, err)
+
+		require.NoError(t, p.Resize(context.Background(), 24, 80))
+		require.Equal(t, uint(24), m.resizeOpts.Height)
+		require.Equal(t, uint(80), m.resizeOpts.Width)
+
+		require.ErrorIs(t, p.Signal(context.Background(), "SIGKILL"), errdefs.ErrNotImplemented)
+		require.NoError(t, p.Signal(context.Background(), "int"))
+		require.Equal(t, byte(0x03), <-received)
+
+		stdout, err := io.ReadAll(p.Stdout())
+		require.NoError(t, err)
+		require.Equal(t, "^C", string(stdout))
+
+		stderr, err := io.ReadAll(p.Stderr())
+		require.NoError(t, err)
+		require.Empty(t, stderr)
+
+		code, err := p.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+	})
+
+	t.Run("no-tty/resize-and-signal-error", func(t *testing.T) {
+		m := &execMockCli{
+			serve: func(conn net.Conn) {
+				conn.Close()
+			},
+		}
+
+		ctr := newMockContainer(t, m)
+
+		p, err := ctr.ExecStream(context.Background(), []string{"true"})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, p.Resize(context.Background(), 24, 80), errdefs.ErrInvalidArgument)
+		require.ErrorIs(t, p.Signal(context.Background(), "SIGINT"), errdefs.ErrNotImplemented)
+
+		<-p.Done()
+	})
+
+	t.Run("wait/copy-error", func(t *testing.T) {
+		m := &execMockCli{
+			exitCode: 3,
+			serve: func(conn net.Conn) {
+				defer conn.Close()
+				// a frame of an unknown stream makes the demultiplexing fail
+				writeFrame(t, conn, 9, "garbage")
+			},
+		}
+
+		ctr := newMockContainer(t, m)
+
+		p, err := ctr.ExecStream(context.Background(), []string{"true"})
+		require.NoError(t, err)
+
+		go func() { _, _ = io.Copy(io.Discard, p.Stderr()) }()
+		_, _ = io.Copy(io.Discard, p.Stdout())
+
+		// the exit code is returned together with the copy error
+		code, err := p.Wait(context.Background())
+		require.ErrorContains(t, err, "copying output")
+		require.Equal(t, 3, code)
+
+		code, err = p.Wait(context.Background())
+		require.ErrorContains(t, err, "copying output")
+		require.Equal(t, 3, code)
+	})
+
+	t.Run("wait/context-done", func(t *testing.T) {
+		m := &execMockCli{
+			serve: func(conn net.Conn) {
+				// never write anything, the connection is closed by the context
+				_, _ = io.Copy(io.Discard, conn)
+			},
+		}
+
+		ctr := newMockContainer(t, m)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p, err := ctr.ExecStream(ctx, []string{"sleep", "infinity"})
+		require.NoError(t, err)
+
+		cancel()
+
+		_, err = p.Wait(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// the connection is closed once the context is done
+		<-p.Done()
+	})
+}
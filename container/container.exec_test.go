@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -102,3 +103,59 @@ func TestContainer_Exec(t *testing.T) {
 		})
 	})
 }
+
+func TestContainer_ExecStream(t *testing.T) {
+	ctr, err := container.Run(context.Background(),
+		// using an image that has a long-running command
+		container.WithImage(nginxAlpineImage),
+	)
+	container.Cleanup(t, ctr)
+	require.NoError(t, err)
+
+	t.Run("stdin-stdout-stderr", func(t *testing.T) {
+		p, err := ctr.ExecStream(context.Background(), []string{"sh", "-c", "cat; echo 'to stderr' 1>&2; exit 3"})
+		require.NoError(t, err)
+		defer p.Close()
+
+		var stdout, stderr bytes.Buffer
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(&stdout, p.Stdout())
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(&stderr, p.Stderr())
+		}()
+
+		_, err = p.Stdin().Write([]byte("from stdin\n"))
+		require.NoError(t, err)
+		require.NoError(t, p.CloseStdin())
+
+		code, err := p.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+
+		wg.Wait()
+		require.Equal(t, "from stdin\n", stdout.String())
+		require.Equal(t, "to stderr\n", stderr.String())
+	})
+
+	t.Run("tty/signal", func(t *testing.T) {
+		p, err := ctr.ExecStream(context.Background(), []string{"sleep", "300"}, exec.WithTTY(true))
+		require.NoError(t, err)
+		defer p.Close()
+
+		go func() {
+			_, _ = io.Copy(io.Discard, p.Stdout())
+		}()
+
+		require.NoError(t, p.Resize(context.Background(), 40, 120))
+		require.NoError(t, p.Signal(context.Background(), "SIGINT"))
+
+		code, err := p.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 130, code)
+	})
+}
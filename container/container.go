@@ -15,6 +15,10 @@ import (
 type Container struct {
 	dockerClient client.SDKClient
 
+	// ownsClient is true if the docker client was created for the container,
+	// which closes it when it's terminated. Clients passed by the caller are not closed.
+	ownsClient bool
+
 	// containerID the Container ID
 	containerID string
 
@@ -75,9 +79,14 @@ func (c *Container) WaitingFor() wait.Strategy {
 	return c.waitingFor
 }
 
-// Host gets host (ip or name) of the docker daemon where the container port is exposed
-// Warning: this is based on your Docker host setting. Will fail if using an SSH tunnel
+// Host gets host (ip or name) of the docker daemon where the container port is exposed.
+// If the client forwards the ports of a remote docker daemon, see [client.WithPortForwarding],
+// it returns 127.0.0.1, as the ports returned by [Container.MappedPort] are local.
 func (c *Container) Host(ctx context.Context) (string, error) {
+	if c.dockerClient.ForwardsPorts() {
+		return "127.0.0.1", nil
+	}
+
 	host, err := c.dockerClient.DaemonHostWithContext(ctx)
 	if err != nil {
 		return "", err
@@ -86,9 +95,11 @@ func (c *Container) Host(ctx context.Context) (string, error) {
 }
 
 // FromID builds a container struct from a container ID, using the Docker API to inspect the container.
-// If dockerClient is nil, a new client will be created using the default configuration.
+// If dockerClient is nil, a new client will be created using the default configuration,
+// and closed when the container is terminated.
 func FromID(ctx context.Context, dockerClient client.SDKClient, containerID string) (*Container, error) {
-	if dockerClient == nil {
+	ownsClient := dockerClient == nil
+	if ownsClient {
 		sdk, err := client.New(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("new docker client: %w", err)
@@ -96,6 +107,20 @@ func FromID(ctx context.Context, dockerClient client.SDKClient, containerID stri
 		dockerClient = sdk
 	}
 
+	ctr, err := fromID(ctx, dockerClient, containerID)
+	if err != nil {
+		if ownsClient {
+			_ = dockerClient.Close()
+		}
+		return nil, err
+	}
+
+	ctr.ownsClient = ownsClient
+	return ctr, nil
+}
+
+// fromID builds a container struct from a container ID, using the given docker client.
+func fromID(ctx context.Context, dockerClient client.SDKClient, containerID string) (*Container, error) {
 	summary, err := dockerClient.FindContainerByID(ctx, containerID)
 	if err != nil {
 		return nil, fmt.Errorf("find container by ID: %w", err)
@@ -107,9 +132,11 @@ func FromID(ctx context.Context, dockerClient client.SDKClient, containerID stri
 }
 
 // FromResponse builds a container struct from the response of the Docker API.
-// If dockerClient is nil, a new client will be created using the default configuration.
+// If dockerClient is nil, a new client will be created using the default configuration,
+// and closed when the container is terminated.
 func FromResponse(ctx context.Context, dockerClient client.SDKClient, response container.Summary) (*Container, error) {
-	if dockerClient == nil {
+	ownsClient := dockerClient == nil
+	if ownsClient {
 		sdk, err := client.New(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("new docker client: %w", err)
@@ -132,6 +159,7 @@ func FromResponse(ctx context.Context, dockerClient client.SDKClient, response c
 
 	ctr := &Container{
 		dockerClient: dockerClient,
+		ownsClient:   ownsClient,
 		containerID:  response.ID,
 		shortID:      shortID,
 		image:        response.Image,
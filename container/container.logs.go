@@ -2,16 +2,171 @@ package container
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/moby/moby/client"
 )
 
+// streamHeaderSize is the size of the header of each frame in the multiplexed stream format.
+const streamHeaderSize = 8
+
+// logFrameChunkSize is the size of the chunks the frames of the multiplexed stream are read in,
+// so that the memory used to read them is bounded, whatever the frame size in their header.
+const logFrameChunkSize = 32 * 1024
+
+// maxLogLineSize is the maximum size of a log line returned by [Container.LogEntries].
+// Longer lines are split into multiple entries, bounding the memory used to read them.
+const maxLogLineSize = 1024 * 1024
+
+// LogStream identifies the standard stream a log entry was written to.
+type LogStream string
+
+const (
+	// LogStreamStdout identifies the standard output of the container.
+	LogStreamStdout LogStream = "stdout"
+
+	// LogStreamStderr identifies the standard error of the container.
+	LogStreamStderr LogStream = "stderr"
+)
+
+// LogEntry is a single line of the container logs.
+type LogEntry struct {
+	// Stream is the stream the line was written to.
+	// It's always [LogStreamStdout] for containers with TTY enabled.
+	Stream LogStream
+
+	// Timestamp is the time the line was written, as recorded by the Docker daemon.
+	Timestamp time.Time
+
+	// Line is the content of the line, without the trailing newline.
+	Line string
+}
+
+// logsOptions is a type that holds the options for reading the container logs.
+type logsOptions struct {
+	follow     bool
+	since      time.Time
+	until      time.Time
+	tail       int
+	timestamps bool
+	stdout     bool
+	stderr     bool
+}
+
+// LogsOption is a type that represents an option for reading the container logs.
+type LogsOption func(*logsOptions)
+
+// newLogsOptions returns a fully initialised logsOptions.
+// Defaults: both stdout and stderr, all the lines, no follow and no timestamps.
+func newLogsOptions(opts ...LogsOption) *logsOptions {
+	options := &logsOptions{
+		tail:   -1,
+		stdout: true,
+		stderr: true,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// toDockerOptions converts the options to the options of the Docker API.
+func (o *logsOptions) toDockerOptions() client.ContainerLogsOptions {
+	options := client.ContainerLogsOptions{
+		ShowStdout: o.stdout,
+		ShowStderr: o.stderr,
+		Follow:     o.follow,
+		Timestamps: o.timestamps,
+	}
+
+	if !o.since.IsZero() {
+		options.Since = o.since.Format(time.RFC3339Nano)
+	}
+
+	if !o.until.IsZero() {
+		options.Until = o.until.Format(time.RFC3339Nano)
+	}
+
+	if o.tail >= 0 {
+		options.Tail = strconv.Itoa(o.tail)
+	}
+
+	return options
+}
+
+// LogsFollow returns a LogsOption that keeps the logs stream open,
+// returning new lines as they are written, until the context is done.
+// Default: false.
+func LogsFollow() LogsOption {
+	return func(o *logsOptions) {
+		o.follow = true
+	}
+}
+
+// LogsSince returns a LogsOption that only returns the lines written after the given time.
+// Default: all the lines.
+func LogsSince(since time.Time) LogsOption {
+	return func(o *logsOptions) {
+		o.since = since
+	}
+}
+
+// LogsUntil returns a LogsOption that only returns the lines written before the given time.
+// Default: all the lines.
+func LogsUntil(until time.Time) LogsOption {
+	return func(o *logsOptions) {
+		o.until = until
+	}
+}
+
+// LogsTail returns a LogsOption that only returns the last n lines of the logs.
+// Default: all the lines.
+func LogsTail(n int) LogsOption {
+	return func(o *logsOptions) {
+		if n < 0 {
+			n = -1
+		}
+		o.tail = n
+	}
+}
+
+// LogsTimestamps returns a LogsOption that prefixes each line with the time it was written,
+// in [time.RFC3339Nano] format. It's ignored by [Container.LogEntries], which always
+// reads the timestamps to populate [LogEntry.Timestamp].
+// Default: false.
+func LogsTimestamps() LogsOption {
+	return func(o *logsOptions) {
+		o.timestamps = true
+	}
+}
+
+// LogsStdoutOnly returns a LogsOption that only returns the lines written to stdout.
+// Default: both stdout and stderr.
+func LogsStdoutOnly() LogsOption {
+	return func(o *logsOptions) {
+		o.stdout = true
+		o.stderr = false
+	}
+}
+
+// LogsStderrOnly returns a LogsOption that only returns the lines written to stderr.
+// Default: both stdout and stderr.
+func LogsStderrOnly() LogsOption {
+	return func(o *logsOptions) {
+		o.stdout = false
+		o.stderr = true
+	}
+}
+
 // Logger returns the logger for the container.
 func (c *Container) Logger() *slog.Logger {
 	return c.logger
@@ -20,36 +175,271 @@ func (c *Container) Logger() *slog.Logger {
 // Logs will fetch both STDOUT and STDERR from the current container. Returns a
 // ReadCloser and leaves it up to the caller to extract what it wants.
 func (c *Container) Logs(ctx context.Context) (io.ReadCloser, error) {
-	options := client.ContainerLogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-	}
+	return c.LogsWithOptions(ctx)
+}
 
-	rc, err := c.dockerClient.ContainerLogs(ctx, c.ID(), options)
+// LogsWithOptions fetches the logs of the current container, using the given options
+// to follow the logs, or to filter them by stream, time or number of lines.
+// Returns a ReadCloser with the combined output of the selected streams, without
+// the multiplexing headers, and leaves it up to the caller to close it.
+func (c *Container) LogsWithOptions(ctx context.Context, opts ...LogsOption) (io.ReadCloser, error) {
+	rc, tty, err := c.openLogs(ctx, newLogsOptions(opts...))
 	if err != nil {
 		return nil, err
 	}
 
+	// If TTY is enabled, logs are not multiplexed - return them directly
+	if tty {
+		return rc, nil
+	}
+
+	// TTY is disabled, logs are multiplexed with stream headers - parse them
+	return c.parseMultiplexedLogs(rc), nil
+}
+
+// StreamLogs returns the logs of the current container, from the beginning, keeping the
+// stream open to return new output as it is written, until the container stops or the
+// context is done. It's equivalent to calling [Container.LogsWithOptions] with the
+// [LogsFollow] option.
+func (c *Container) StreamLogs(ctx context.Context) (io.ReadCloser, error) {
+	return c.LogsWithOptions(ctx, LogsFollow())
+}
+
+// LogEntries returns an iterator over the lines of the container logs, using the given
+// options to follow the logs, or to filter them by stream, time or number of lines.
+// Each line is returned as a [LogEntry], identifying the stream it was written to
+// and the time it was written.
+//
+// The iteration ends when all the lines have been returned, or, when following the logs,
+// when the container stops. If the logs cannot be read, or the context is done, the error
+// is returned as the last element of the iteration.
+func (c *Container) LogEntries(ctx context.Context, opts ...LogsOption) iter.Seq2[LogEntry, error] {
+	return func(yield func(LogEntry, error) bool) {
+		options := newLogsOptions(opts...)
+		options.timestamps = true
+
+		rc, tty, err := c.openLogs(ctx, options)
+		if err != nil {
+			yield(LogEntry{}, err)
+			return
+		}
+		defer rc.Close()
+
+		// the iteration must not continue once the consumer stopped it,
+		// e.g. breaking out of the loop after cancelling the context
+		stopped := false
+		err = readLogEntries(rc, tty, func(entry LogEntry, err error) bool {
+			if !yield(entry, err) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		if err != nil {
+			yield(LogEntry{}, err)
+		}
+	}
+}
+
+// FollowLogs returns an iterator over the lines of the container logs, as they are written,
+// until the container stops or the context is done. It's equivalent to calling
+// [Container.LogEntries] with the [LogsFollow] option.
+func (c *Container) FollowLogs(ctx context.Context, opts ...LogsOption) iter.Seq2[LogEntry, error] {
+	return c.LogEntries(ctx, append(opts, LogsFollow())...)
+}
+
+// openLogs opens the logs stream of the container, reporting if the container
+// has TTY enabled, which determines the format of the stream.
+func (c *Container) openLogs(ctx context.Context, options *logsOptions) (io.ReadCloser, bool, error) {
+	rc, err := c.dockerClient.ContainerLogs(ctx, c.ID(), options.toDockerOptions())
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Check if the container has TTY enabled, to determine the log format
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
 		rc.Close()
-		return nil, fmt.Errorf("inspect container: %w", err)
+		return nil, false, fmt.Errorf("inspect container: %w", err)
 	}
 
-	// If TTY is enabled, logs are not multiplexed - return them directly
-	if inspect.Container.Config.Tty {
-		return rc, nil
+	return rc, inspect.Container.Config.Tty, nil
+}
+
+// readLogEntries reads the log lines from r, passing them to yield until it returns false.
+// The lines are expected to be prefixed with their timestamps. If tty is false, r must use
+// the multiplexed format, to tell stdout from stderr.
+// It returns nil when r is exhausted.
+func readLogEntries(r io.Reader, tty bool, yield func(LogEntry, error) bool) error {
+	br := bufio.NewReader(r)
+
+	lines := map[LogStream]*logLineBuffer{
+		LogStreamStdout: {stream: LogStreamStdout},
+		LogStreamStderr: {stream: LogStreamStderr},
 	}
 
-	// TTY is disabled, logs are multiplexed with stream headers - parse them
-	return c.parseMultiplexedLogs(rc), nil
+	// flush emits the pending partial lines, if any.
+	flush := func() bool {
+		for _, s := range []LogStream{LogStreamStdout, LogStreamStderr} {
+			if entry, ok := lines[s].flush(); ok && !yield(entry, nil) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if tty {
+		// with TTY enabled, there are no frames: each line is a new message
+		lineStart := true
+		for {
+			chunk, err := br.ReadSlice('\n')
+			if len(chunk) > 0 && !lines[LogStreamStdout].write(chunk, lineStart, yield) {
+				return nil
+			}
+			lineStart = len(chunk) > 0 && chunk[len(chunk)-1] == '\n'
+			if err != nil {
+				if errors.Is(err, bufio.ErrBufferFull) {
+					continue
+				}
+				if !flush() {
+					return nil
+				}
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	header := make([]byte, streamHeaderSize)
+	chunk := make([]byte, logFrameChunkSize)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if !flush() {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		stream := LogStreamStdout
+		if header[0] == 2 {
+			stream = LogStreamStderr
+		}
+
+		// the frame is read in chunks, only its first chunk starting the log message
+		newMessage := true
+		for size := int(binary.BigEndian.Uint32(header[4:])); size > 0; {
+			n := min(size, len(chunk))
+			if _, err := io.ReadFull(br, chunk[:n]); err != nil {
+				if !flush() {
+					return nil
+				}
+				return err
+			}
+
+			if !lines[stream].write(chunk[:n], newMessage, yield) {
+				return nil
+			}
+			newMessage = false
+			size -= n
+		}
+	}
+}
+
+// logLineBuffer assembles the log lines written to a stream, which could be
+// split across multiple frames for long lines.
+type logLineBuffer struct {
+	stream    LogStream
+	timestamp time.Time
+	buf       bytes.Buffer
+}
+
+// write appends the chunk to the pending line, passing every complete line to yield.
+// If newMessage is true, the chunk is the beginning of a log message, and it is
+// prefixed with the timestamp of the message.
+// It returns false if yield returned false.
+func (b *logLineBuffer) write(chunk []byte, newMessage bool, yield func(LogEntry, error) bool) bool {
+	if newMessage {
+		if ts, rest, ok := parseLogTimestamp(chunk); ok {
+			if b.buf.Len() == 0 {
+				b.timestamp = ts
+			}
+			chunk = rest
+		}
+	}
+
+	for len(chunk) > 0 {
+		n := min(len(chunk), maxLogLineSize-b.buf.Len())
+
+		i := bytes.IndexByte(chunk[:n], '\n')
+		if i < 0 {
+			b.buf.Write(chunk[:n])
+			chunk = chunk[n:]
+			if b.buf.Len() < maxLogLineSize {
+				return true
+			}
+		} else {
+			b.buf.Write(chunk[:i])
+			chunk = chunk[i+1:]
+		}
+
+		if !yield(b.next(), nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flush returns the pending line, if any, resetting the buffer.
+func (b *logLineBuffer) flush() (LogEntry, bool) {
+	if b.buf.Len() == 0 {
+		return LogEntry{}, false
+	}
+
+	return b.next(), true
+}
+
+// next returns the pending line, which could be empty, resetting the buffer.
+func (b *logLineBuffer) next() LogEntry {
+	entry := LogEntry{
+		Stream:    b.stream,
+		Timestamp: b.timestamp,
+		Line:      string(bytes.TrimSuffix(b.buf.Bytes(), []byte("\r"))),
+	}
+	b.buf.Reset()
+
+	return entry
+}
+
+// parseLogTimestamp parses the timestamp the Docker daemon prefixes the log messages with,
+// returning the rest of the message.
+func parseLogTimestamp(b []byte) (time.Time, []byte, bool) {
+	i := bytes.IndexByte(b, ' ')
+	if i < 0 {
+		return time.Time{}, b, false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, string(b[:i]))
+	if err != nil {
+		return time.Time{}, b, false
+	}
+
+	return ts, b[i+1:], true
 }
 
 // parseMultiplexedLogs handles the multiplexed log format used when TTY is disabled
 func (c *Container) parseMultiplexedLogs(rc io.ReadCloser) io.ReadCloser {
-	const streamHeaderSize = 8
-
 	pr, pw := io.Pipe()
 	r := bufio.NewReader(rc)
 
@@ -99,7 +489,20 @@ func (c *Container) parseMultiplexedLogs(rc io.ReadCloser) io.ReadCloser {
 		}
 	}()
 
-	return pr
+	return &demuxedLogsReader{PipeReader: pr, rc: rc}
+}
+
+// demuxedLogsReader is the reader returned by [Container.parseMultiplexedLogs].
+// Closing it also closes the underlying logs stream, so that the parsing goroutine
+// is not left blocked on a followed stream that has no new output.
+type demuxedLogsReader struct {
+	*io.PipeReader
+	rc io.Closer
+}
+
+// Close closes the reader and the underlying logs stream.
+func (r *demuxedLogsReader) Close() error {
+	return errors.Join(r.PipeReader.Close(), r.rc.Close())
 }
 
 // printLogs is a helper function that will print the logs of a Docker container
@@ -162,3 +162,51 @@ func TestContainer_Logs_TTYEnabled(t *testing.T) {
 	logs := strings.TrimSpace(string(b))
 	require.Contains(t, logs, "tty output")
 }
+
+func TestContainer_FollowLogs(t *testing.T) {
+	ctx := context.Background()
+	ctr, err := container.Run(ctx,
+		container.WithImage(alpineLatest),
+		container.WithCmd("sh", "-c", "echo 'stdout line' && echo 'stderr line' 1>&2 && sleep 1 && echo 'later line' && sleep infinity"),
+	)
+	container.Cleanup(t, ctr)
+	require.NoError(t, err)
+
+	followCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var entries []container.LogEntry
+	for entry, err := range ctr.FollowLogs(followCtx) {
+		require.NoError(t, err)
+		entries = append(entries, entry)
+		if entry.Line == "later line" {
+			break
+		}
+	}
+
+	require.Len(t, entries, 3)
+	require.ElementsMatch(t, []container.LogStream{container.LogStreamStdout, container.LogStreamStderr}, []container.LogStream{entries[0].Stream, entries[1].Stream})
+	require.Equal(t, container.LogStreamStdout, entries[2].Stream)
+	for _, entry := range entries {
+		require.False(t, entry.Timestamp.IsZero())
+	}
+
+	t.Run("tail", func(t *testing.T) {
+		var lines []string
+		for entry, err := range ctr.LogEntries(ctx, container.LogsTail(1)) {
+			require.NoError(t, err)
+			lines = append(lines, entry.Line)
+		}
+		require.Equal(t, []string{"later line"}, lines)
+	})
+
+	t.Run("stderr-only", func(t *testing.T) {
+		r, err := ctr.LogsWithOptions(ctx, container.LogsStderrOnly())
+		require.NoError(t, err)
+		defer r.Close()
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "stderr line", strings.TrimSpace(string(b)))
+	})
+}
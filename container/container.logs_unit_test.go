@@ -0,0 +1,279 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	dockercontainer "github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// logsMockCli is a mock implementation of client.APIClient, which returns
+// the given logs for a container with or without TTY enabled.
+type logsMockCli struct {
+	mockCli
+
+	logs []byte
+	tty  bool
+
+	// logsReader, if set, is returned instead of logs.
+	logsReader io.ReadCloser
+
+	options dockerclient.ContainerLogsOptions
+}
+
+func (m *logsMockCli) ContainerLogs(_ context.Context, _ string, options dockerclient.ContainerLogsOptions) (dockerclient.ContainerLogsResult, error) {
+	m.options = options
+	if m.logsReader != nil {
+		return m.logsReader, nil
+	}
+	return io.NopCloser(bytes.NewReader(m.logs)), nil
+}
+
+func (m *logsMockCli) ContainerInspect(_ context.Context, _ string, _ dockerclient.ContainerInspectOptions) (dockerclient.ContainerInspectResult, error) {
+	return dockerclient.ContainerInspectResult{
+		Container: dockercontainer.InspectResponse{
+			Config: &dockercontainer.Config{Tty: m.tty},
+		},
+	}, nil
+}
+
+func collectLogEntries(t *testing.T, ctr *Container, opts ...LogsOption) ([]LogEntry, error) {
+	t.Helper()
+
+	var entries []LogEntry
+	for entry, err := range ctr.LogEntries(context.Background(), opts...) {
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func TestContainer_LogsWithOptions(t *testing.T) {
+	since := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	until := since.Add(time.Hour)
+
+	var buf bytes.Buffer
+	writeFrame(t, &buf, 1, "out\n")
+	writeFrame(t, &buf, 2, "err\n")
+
+	m := &logsMockCli{logs: buf.Bytes()}
+	ctr := newMockContainer(t, m)
+
+	rc, err := ctr.LogsWithOptions(context.Background(),
+		LogsFollow(),
+		LogsSince(since),
+		LogsUntil(until),
+		LogsTail(10),
+		LogsTimestamps(),
+		LogsStderrOnly(),
+	)
+	require.NoError(t, err)
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "out\nerr\n", string(b))
+
+	require.Equal(t, dockerclient.ContainerLogsOptions{
+		ShowStderr: true,
+		Follow:     true,
+		Since:      "2025-01-02T03:04:05.000000006Z",
+		Until:      "2025-01-02T04:04:05.000000006Z",
+		Tail:       "10",
+		Timestamps: true,
+	}, m.options)
+
+	t.Run("defaults", func(t *testing.T) {
+		_, err := ctr.Logs(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, dockerclient.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+		}, m.options)
+	})
+}
+
+func TestContainer_LogEntries(t *testing.T) {
+	ts1 := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	ts2 := ts1.Add(time.Second)
+
+	t.Run("multiplexed", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" first\n")
+		writeFrame(t, &buf, 2, ts1.Format(time.RFC3339Nano)+" oops\n")
+		// a long line split in two frames
+		writeFrame(t, &buf, 1, ts2.Format(time.RFC3339Nano)+" second ")
+		writeFrame(t, &buf, 1, ts2.Format(time.RFC3339Nano)+" line\n")
+		// no trailing newline
+		writeFrame(t, &buf, 2, ts2.Format(time.RFC3339Nano)+" last")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr, LogsTail(5))
+		require.NoError(t, err)
+		require.Equal(t, []LogEntry{
+			{Stream: LogStreamStdout, Timestamp: ts1, Line: "first"},
+			{Stream: LogStreamStderr, Timestamp: ts1, Line: "oops"},
+			{Stream: LogStreamStdout, Timestamp: ts2, Line: "second line"},
+			{Stream: LogStreamStderr, Timestamp: ts2, Line: "last"},
+		}, entries)
+
+		// timestamps are always requested, to populate the entries
+		require.True(t, m.options.Timestamps)
+		require.False(t, m.options.Follow)
+		require.Equal(t, "5", m.options.Tail)
+	})
+
+	t.Run("tty", func(t *testing.T) {
+		logs := ts1.Format(time.RFC3339Nano) + " first\r\n" +
+			ts2.Format(time.RFC3339Nano) + " second\r\n" +
+			ts2.Format(time.RFC3339Nano) + " \r\n"
+
+		m := &logsMockCli{logs: []byte(logs), tty: true}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr)
+		require.NoError(t, err)
+		require.Equal(t, []LogEntry{
+			{Stream: LogStreamStdout, Timestamp: ts1, Line: "first"},
+			{Stream: LogStreamStdout, Timestamp: ts2, Line: "second"},
+			{Stream: LogStreamStdout, Timestamp: ts2, Line: ""},
+		}, entries)
+	})
+
+	t.Run("long-line", func(t *testing.T) {
+		line := strings.Repeat("a", maxLogLineSize+10)
+
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" "+line+"\n")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Len(t, entries[0].Line, maxLogLineSize)
+		require.Len(t, entries[1].Line, 10)
+	})
+
+	t.Run("truncated-frame", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" first\n")
+		buf.Write([]byte{1, 0, 0, 0, 0, 0, 0, 10, 'a'})
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("oversized-frame-header", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" first\n")
+		// the header announces a 4GiB frame, which is not allocated upfront
+		buf.Write([]byte{1, 0, 0, 0, 0xff, 0xff, 0xff, 0xff})
+		buf.WriteString(ts2.Format(time.RFC3339Nano) + " partial")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Equal(t, []LogEntry{{Stream: LogStreamStdout, Timestamp: ts1, Line: "first"}}, entries)
+	})
+
+	t.Run("frame-larger-than-chunk", func(t *testing.T) {
+		line := strings.Repeat("b", 3*logFrameChunkSize)
+
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" "+line+"\n")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		entries, err := collectLogEntries(t, ctr)
+		require.NoError(t, err)
+		require.Equal(t, []LogEntry{{Stream: LogStreamStdout, Timestamp: ts1, Line: line}}, entries)
+	})
+
+	t.Run("follow/context-done", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		m := &logsMockCli{logsReader: pr}
+		ctr := newMockContainer(t, m)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			writeFrame(t, pw, 1, ts1.Format(time.RFC3339Nano)+" ready\n")
+			// simulate the HTTP body being closed once the context is done
+			<-ctx.Done()
+			pw.CloseWithError(ctx.Err())
+		}()
+
+		var lines []string
+		var iterErr error
+		for entry, err := range ctr.FollowLogs(ctx) {
+			if err != nil {
+				iterErr = err
+				break
+			}
+			lines = append(lines, entry.Line)
+			cancel()
+		}
+
+		require.ErrorIs(t, iterErr, context.Canceled)
+		require.Equal(t, []string{"ready"}, lines)
+		require.True(t, m.options.Follow)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" first\nsecond\n")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		var lines []string
+		for entry, err := range ctr.LogEntries(context.Background()) {
+			require.NoError(t, err)
+			lines = append(lines, entry.Line)
+			break
+		}
+		require.Equal(t, []string{"first"}, lines)
+	})
+
+	t.Run("break/context-done", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeFrame(t, &buf, 1, ts1.Format(time.RFC3339Nano)+" first\nsecond\n")
+
+		m := &logsMockCli{logs: buf.Bytes()}
+		ctr := newMockContainer(t, m)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// breaking out of the loop after cancelling the context must not resume the iteration
+		var lines []string
+		for entry, err := range ctr.LogEntries(ctx) {
+			require.NoError(t, err)
+			lines = append(lines, entry.Line)
+			cancel()
+			break
+		}
+		require.Equal(t, []string{"first"}, lines)
+	})
+}
@@ -3,6 +3,9 @@ package container
 import (
 	"context"
 	"net/netip"
+	"slices"
+
+	"github.com/docker/go-sdk/client"
 )
 
 // ContainerIP gets the IP address of the primary network within the container.
@@ -40,6 +43,8 @@ func (c *Container) ContainerIPs(ctx context.Context) ([]netip.Addr, error) {
 }
 
 // NetworkAliases gets the aliases of the container for the networks it is attached to.
+// For Podman, the alias it adds automatically for the short ID of the container is
+// not included, as Docker reports it in the DNS names of the container instead.
 func (c *Container) NetworkAliases(ctx context.Context) (map[string][]string, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
@@ -50,8 +55,23 @@ func (c *Container) NetworkAliases(ctx context.Context) (map[string][]string, er
 
 	a := map[string][]string{}
 
+	// the capabilities of the daemon are only probed if the alias is found
+	podman := false
+	for _, nw := range networks {
+		if slices.Contains(nw.Aliases, c.shortID) {
+			podman = client.IsPodman(ctx, c.dockerClient)
+			break
+		}
+	}
+
 	for k := range networks {
-		a[k] = networks[k].Aliases
+		aliases := networks[k].Aliases
+		if podman {
+			aliases = slices.DeleteFunc(slices.Clone(aliases), func(alias string) bool {
+				return alias == c.shortID
+			})
+		}
+		a[k] = aliases
 	}
 
 	return a, nil
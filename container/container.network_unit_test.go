@@ -0,0 +1,53 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// aliasesClient is a client.SDKClient for a daemon of the given engine,
+// whose containers are attached to the given networks.
+type aliasesClient struct {
+	client.SDKClient
+
+	engine   client.Engine
+	networks map[string]*network.EndpointSettings
+}
+
+func (c *aliasesClient) Capabilities(_ context.Context) (client.Capabilities, error) {
+	return client.Capabilities{Engine: c.engine}, nil
+}
+
+func (c *aliasesClient) ContainerInspect(_ context.Context, containerID string, _ dockerclient.ContainerInspectOptions) (dockerclient.ContainerInspectResult, error) {
+	return dockerclient.ContainerInspectResult{Container: dockercontainer.InspectResponse{
+		ID:              containerID,
+		NetworkSettings: &dockercontainer.NetworkSettings{Networks: c.networks},
+	}}, nil
+}
+
+func TestContainer_NetworkAliases_podman(t *testing.T) {
+	newContainer := func(engine client.Engine) *Container {
+		return &Container{
+			dockerClient: &aliasesClient{engine: engine, networks: map[string]*network.EndpointSettings{
+				"backend": {Aliases: []string{"fedcba987654", "db"}},
+			}},
+			containerID: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			shortID:     "fedcba987654",
+		}
+	}
+
+	aliases, err := newContainer(client.EnginePodman).NetworkAliases(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"backend": {"db"}}, aliases)
+
+	aliases, err = newContainer(client.EngineDocker).NetworkAliases(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"backend": {"fedcba987654", "db"}}, aliases)
+}
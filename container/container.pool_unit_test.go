@@ -0,0 +1,78 @@
+package container
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+// closeCountingClient is a client which counts the calls to Close.
+type closeCountingClient struct {
+	client.SDKClient
+
+	closed atomic.Int32
+}
+
+func (c *closeCountingClient) Close() error {
+	c.closed.Add(1)
+	return c.SDKClient.Close()
+}
+
+func TestRun_clientPool(t *testing.T) {
+	ctx := context.Background()
+
+	var clients []client.SDKClient
+	for range 2 {
+		fake := clienttest.NewFakeAPI()
+		_, err := fake.AddImage("alpine:latest")
+		require.NoError(t, err)
+
+		cli, err := clienttest.NewFakeClient(ctx, fake)
+		require.NoError(t, err)
+		clients = append(clients, &closeCountingClient{SDKClient: cli})
+	}
+
+	pool, err := client.NewPool(ctx, client.WithPoolClients(clients...))
+	require.NoError(t, err)
+
+	for i := range 4 {
+		ctr, err := Run(ctx, WithClientPool(pool), WithImage("alpine:latest"))
+		require.NoError(t, err)
+		require.Same(t, clients[i%2], ctr.Client())
+
+		// the container is managed through the client it was scheduled on
+		_, err = clients[i%2].ContainerInspect(ctx, ctr.ID(), dockerclient.ContainerInspectOptions{})
+		require.NoError(t, err)
+		require.NoError(t, ctr.Terminate(ctx))
+	}
+
+	t.Run("client-takes-precedence", func(t *testing.T) {
+		ctr, err := Run(ctx, WithClientPool(pool), WithClient(clients[1]), WithImage("alpine:latest"))
+		require.NoError(t, err)
+		require.Same(t, clients[1], ctr.Client())
+		require.NoError(t, ctr.Terminate(ctx))
+	})
+
+	t.Run("nil-pool", func(t *testing.T) {
+		_, err := Run(ctx, WithClientPool(nil), WithImage("alpine:latest"))
+		require.Error(t, err)
+	})
+
+	t.Run("closed-by-pool", func(t *testing.T) {
+		// the containers do not close the clients of the pool
+		for _, cli := range clients {
+			require.Zero(t, cli.(*closeCountingClient).closed.Load())
+		}
+
+		require.NoError(t, pool.Close())
+		for _, cli := range clients {
+			require.Equal(t, int32(1), cli.(*closeCountingClient).closed.Load())
+		}
+	})
+}
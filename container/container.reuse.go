@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+)
+
+// configHashLabel is the label used to store the hash of the configuration
+// a container was created with, to detect if the definition changed when reusing it.
+const configHashLabel = moduleLabel + ".config-hash"
+
+// configHash returns the hash of the configuration used to create the container.
+// The configuration is serialised to JSON, which sorts the keys of the maps,
+// so the hash is stable as long as the configuration does not change.
+func configHash(opts any) (string, error) {
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return "", fmt.Errorf("marshal config: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// findReusableContainer returns the container with the name of the definition,
+// if it exists and it was created from the same configuration, identified by its hash.
+// If the configuration changed, the existing container is removed, so that a new one
+// can be created with the same name.
+// It returns nil if there is no container to reuse.
+func findReusableContainer(ctx context.Context, def *Definition, hash string) (*container.Summary, error) {
+	summary, err := def.dockerClient.FindContainerByName(ctx, def.name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find container by name: %w", err)
+	}
+
+	if summary.Labels[configHashLabel] == hash {
+		return summary, nil
+	}
+
+	def.dockerClient.Logger().Info("Container definition changed, recreating it", "name", def.name, "containerID", summary.ID)
+
+	stale, err := FromResponse(ctx, def.dockerClient, *summary)
+	if err != nil {
+		return nil, fmt.Errorf("from response: %w", err)
+	}
+
+	if err := stale.Terminate(ctx); err != nil {
+		return nil, fmt.Errorf("terminate stale container: %w", err)
+	}
+
+	return nil, nil
+}
+
+// reuse returns the container for the given summary, starting it if it's not running,
+// and waiting for it to be ready using the wait strategy of the definition.
+func reuse(ctx context.Context, def *Definition, summary container.Summary) (*Container, error) {
+	ctr, err := FromResponse(ctx, def.dockerClient, summary)
+	if err != nil {
+		return nil, fmt.Errorf("from response: %w", err)
+	}
+
+	ctr.ownsClient = def.ownsClient
+	ctr.waitingFor = def.waitingFor
+	ctr.image = def.image
+	ctr.exposedPorts = def.exposedPorts
+	ctr.lifecycleHooks = def.lifecycleHooks
+
+	ctr.logger.Info("Reusing container", "name", def.name, "containerID", ctr.ShortID())
+
+	if !def.started {
+		return ctr, nil
+	}
+
+	if !ctr.isRunning {
+		if err := ctr.Start(ctx); err != nil {
+			return ctr, fmt.Errorf("start container: %w", err)
+		}
+		return ctr, nil
+	}
+
+	// The container is already running, so the starting hooks are not executed,
+	// but it could have been started by another process and not be ready yet.
+	if ctr.waitingFor != nil {
+		if err := ctr.waitingFor.WaitUntilReady(ctx, ctr); err != nil {
+			return ctr, fmt.Errorf("wait until ready: %w", err)
+		}
+	}
+
+	if err := ctr.readiedHook(ctx); err != nil {
+		return ctr, fmt.Errorf("readied hook: %w", err)
+	}
+
+	return ctr, nil
+}
+
+// reuseRequiresName validates that a definition in reuse mode has a name,
+// which is used to look up the container to reuse.
+func (d *Definition) reuseRequiresName() error {
+	if d.reuse && d.name == "" {
+		return errdefs.ErrInvalidArgument.WithMessage("reuse requires a container name, use WithName")
+	}
+	return nil
+}
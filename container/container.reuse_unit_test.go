@@ -0,0 +1,143 @@
+package container
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	dockercontainer "github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/container/wait"
+)
+
+// reuseMockCli is a mock implementation of client.APIClient, which keeps
+// track of a single container, to simulate reusing it across runs.
+type reuseMockCli struct {
+	mockCli
+
+	existing *dockercontainer.Summary
+
+	creates int
+	starts  int
+	removes int
+}
+
+func (m *reuseMockCli) ImageInspect(_ context.Context, _ string, _ ...dockerclient.ImageInspectOption) (dockerclient.ImageInspectResult, error) {
+	return dockerclient.ImageInspectResult{}, nil
+}
+
+func (m *reuseMockCli) ContainerList(_ context.Context, _ dockerclient.ContainerListOptions) (dockerclient.ContainerListResult, error) {
+	if m.existing == nil {
+		return dockerclient.ContainerListResult{}, nil
+	}
+	return dockerclient.ContainerListResult{Items: []dockercontainer.Summary{*m.existing}}, nil
+}
+
+func (m *reuseMockCli) ContainerCreate(_ context.Context, options dockerclient.ContainerCreateOptions) (dockerclient.ContainerCreateResult, error) {
+	if m.existing != nil {
+		return dockerclient.ContainerCreateResult{}, errdefs.ErrConflict.WithMessage("container name is already in use")
+	}
+
+	m.creates++
+	id := strings.Repeat(string(rune('0'+m.creates)), 64)
+	m.existing = &dockercontainer.Summary{
+		ID:     id,
+		Names:  []string{"/" + options.Name},
+		Image:  options.Config.Image,
+		Labels: options.Config.Labels,
+		State:  dockercontainer.StateCreated,
+	}
+
+	return dockerclient.ContainerCreateResult{ID: id}, nil
+}
+
+func (m *reuseMockCli) ContainerStart(_ context.Context, _ string, _ dockerclient.ContainerStartOptions) (dockerclient.ContainerStartResult, error) {
+	m.starts++
+	m.existing.State = dockercontainer.StateRunning
+	return dockerclient.ContainerStartResult{}, nil
+}
+
+func (m *reuseMockCli) ContainerStop(_ context.Context, _ string, _ dockerclient.ContainerStopOptions) (dockerclient.ContainerStopResult, error) {
+	m.existing.State = dockercontainer.StateExited
+	return dockerclient.ContainerStopResult{}, nil
+}
+
+func (m *reuseMockCli) ContainerRemove(_ context.Context, _ string, _ dockerclient.ContainerRemoveOptions) (dockerclient.ContainerRemoveResult, error) {
+	m.removes++
+	m.existing = nil
+	return dockerclient.ContainerRemoveResult{}, nil
+}
+
+func TestRun_withReuse(t *testing.T) {
+	m := &reuseMockCli{}
+	sdk := newMockClient(t, m)
+
+	var waits atomic.Int32
+	run := func(t *testing.T, opts ...ContainerCustomizer) *Container {
+		t.Helper()
+
+		ctr, err := Run(context.Background(), append([]ContainerCustomizer{
+			WithClient(sdk),
+			WithImage("alpine:latest"),
+			WithName("reused"),
+			WithReuse(),
+			WithWaitStrategy(wait.ForNop(func(_ context.Context, _ wait.StrategyTarget) error {
+				waits.Add(1)
+				return nil
+			})),
+		}, opts...)...)
+		require.NoError(t, err)
+		require.NotNil(t, ctr)
+
+		return ctr
+	}
+
+	first := run(t)
+	require.Equal(t, 1, m.creates)
+	require.Equal(t, 1, m.starts)
+	require.Equal(t, int32(1), waits.Load())
+	require.NotEmpty(t, m.existing.Labels[configHashLabel])
+
+	t.Run("running", func(t *testing.T) {
+		ctr := run(t)
+		require.Equal(t, first.ID(), ctr.ID())
+		require.True(t, ctr.IsRunning())
+		require.Equal(t, 1, m.creates)
+		require.Equal(t, 1, m.starts)
+		// the wait strategy is executed again
+		require.Equal(t, int32(2), waits.Load())
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		m.existing.State = dockercontainer.StateExited
+
+		ctr := run(t)
+		require.Equal(t, first.ID(), ctr.ID())
+		require.True(t, ctr.IsRunning())
+		require.Equal(t, 1, m.creates)
+		require.Equal(t, 2, m.starts)
+		require.Equal(t, int32(3), waits.Load())
+	})
+
+	t.Run("definition-changed", func(t *testing.T) {
+		ctr := run(t, WithEnv(map[string]string{"FOO": "bar"}))
+		require.NotEqual(t, first.ID(), ctr.ID())
+		require.Equal(t, 1, m.removes)
+		require.Equal(t, 2, m.creates)
+		require.Equal(t, int32(4), waits.Load())
+
+		// the same definition reuses the new container
+		again := run(t, WithEnv(map[string]string{"FOO": "bar"}))
+		require.Equal(t, ctr.ID(), again.ID())
+		require.Equal(t, 2, m.creates)
+	})
+
+	t.Run("no-name", func(t *testing.T) {
+		_, err := Run(context.Background(), WithClient(sdk), WithImage("alpine:latest"), WithReuse())
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/container"
 	apinetwork "github.com/moby/moby/api/types/network"
 	dockerclient "github.com/moby/moby/client"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/go-sdk/client"
 )
@@ -15,7 +18,10 @@ import (
 // Run is a convenience function that creates a new container and starts it.
 // By default, the container is started after creation, unless requested otherwise
 // using the [WithNoStart] option.
-func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
+//
+// The run is traced with the tracer provider of the client, see [client.WithTracerProvider]:
+// its span has a child span for each lifecycle phase, pull, create, start and wait.
+func Run(ctx context.Context, opts ...ContainerCustomizer) (ctr *Container, err error) {
 	def := Definition{
 		env:     make(map[string]string),
 		started: true,
@@ -30,6 +36,7 @@ func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
 			return nil
 		},
 		def.validateMounts,
+		def.reuseRequiresName,
 	}
 
 	for _, opt := range opts {
@@ -42,19 +49,44 @@ func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
 		return nil, fmt.Errorf("validate: %w", err)
 	}
 
+	if def.dockerClient == nil && def.clientPool != nil {
+		sdk, err := def.clientPool.Pick(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("pick client: %w", err)
+		}
+		def.dockerClient = sdk
+	}
+
 	if def.dockerClient == nil {
 		sdk, err := client.New(ctx)
 		if err != nil {
 			return nil, err
 		}
-		// use the default docker client
+		// use the default docker client, which is owned by the container
 		def.dockerClient = sdk
+		def.ownsClient = true
 	}
 
+	defer func() {
+		// the client owned by the container is closed when it's terminated,
+		// or now if there is no container to terminate
+		if def.ownsClient && ctr == nil {
+			_ = def.dockerClient.Close()
+		}
+	}()
+
+	ctx, span := def.dockerClient.TracerProvider().Tracer(tracerName).Start(ctx, "container.Run",
+		trace.WithAttributes(imageAttribute(def.image)))
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	env := []string{}
 	for envKey, envVar := range def.env {
 		env = append(env, envKey+"="+envVar)
 	}
+	// sort the environment variables, so that the container configuration is stable
+	slices.Sort(env)
 
 	if def.labels == nil {
 		def.labels = make(map[string]string)
@@ -91,14 +123,19 @@ func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
 		combineContainerHooks(defaultHooks, origLifecycleHooks),
 	}
 
-	err := def.creatingHook(ctx)
+	err = def.creatingHook(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Image substitution must be done after the creating hook has been called,
 	// as the image could have been overridden in there.
-	for _, is := range def.imageSubstitutors {
+	// Snapshots are local images, so they are never substituted.
+	imageSubstitutors := def.imageSubstitutors
+	if def.snapshot {
+		imageSubstitutors = nil
+	}
+	for _, is := range imageSubstitutors {
 		modifiedTag, err := is.Substitute(def.image)
 		if err != nil {
 			return nil, fmt.Errorf("failed to substitute image %s with %s: %w", def.image, is.Description(), err)
@@ -114,20 +151,55 @@ func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
 	// as it could have been overridden in there.
 	dockerInput.Image = def.image
 
-	resp, err := def.dockerClient.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{
+	createOptions := dockerclient.ContainerCreateOptions{
 		Config:           dockerInput,
 		HostConfig:       hostConfig,
 		NetworkingConfig: networkingConfig,
 		Platform:         def.platform,
 		Name:             def.name,
-	})
+	}
+
+	if def.reuse {
+		hash, err := configHash(createOptions)
+		if err != nil {
+			return nil, fmt.Errorf("config hash: %w", err)
+		}
+		dockerInput.Labels[configHashLabel] = hash
+		// reused containers outlive the session that created them.
+		dockerInput.Labels[client.LabelReaperSkip] = "true"
+
+		summary, err := findReusableContainer(ctx, &def, hash)
+		if err != nil {
+			return nil, fmt.Errorf("find reusable container: %w", err)
+		}
+
+		if summary != nil {
+			return reuse(ctx, &def, *summary)
+		}
+	}
+
+	createCtx, createSpan := startSpan(ctx, "container.create", imageAttribute(def.image))
+	resp, err := def.dockerClient.ContainerCreate(createCtx, createOptions)
+	if err == nil {
+		createSpan.SetAttributes(client.AttributeContainerID.String(resp.ID))
+		span.SetAttributes(client.AttributeContainerID.String(resp.ID))
+	}
+	endSpan(createSpan, err)
 	if err != nil {
+		if def.reuse && errdefs.IsConflict(err) {
+			// the container was created by another process in the meantime, so reuse it.
+			summary, findErr := def.dockerClient.FindContainerByName(ctx, def.name)
+			if findErr == nil && summary.Labels[configHashLabel] == dockerInput.Labels[configHashLabel] {
+				return reuse(ctx, &def, *summary)
+			}
+		}
 		return nil, fmt.Errorf("container create: %w", err)
 	}
 
 	// This should match the fields set in ContainerFromDockerResponse.
-	ctr := &Container{
+	ctr = &Container{
 		dockerClient:   def.dockerClient,
+		ownsClient:     def.ownsClient,
 		containerID:    resp.ID,
 		shortID:        resp.ID[:12],
 		waitingFor:     def.waitingFor,
@@ -169,7 +241,10 @@ func Run(ctx context.Context, opts ...ContainerCustomizer) (*Container, error) {
 	}
 
 	if def.started {
-		if err := ctr.Start(ctx); err != nil {
+		startCtx, phaseSpan := startSpan(ctx, "container.start", client.AttributeContainerID.String(ctr.ID()))
+		err := ctr.Start(startCtx)
+		endSpan(phaseSpan, err)
+		if err != nil {
 			return ctr, fmt.Errorf("start container: %w", err)
 		}
 	}
@@ -707,6 +707,45 @@ func TestRun_addSDKLabels(t *testing.T) {
 	require.Equal(t, container.Version(), inspect.Container.Config.Labels[client.LabelBase+".container"])
 }
 
+func TestRun_withReuse(t *testing.T) {
+	name := "reuse-" + strings.ReplaceAll(t.Name(), "/", "-")
+	opts := []container.ContainerCustomizer{
+		container.WithImage(nginxAlpineImage),
+		container.WithName(name),
+		container.WithReuse(),
+		container.WithWaitStrategy(wait.ForListeningPort(apinetwork.MustParsePort("80/tcp"))),
+	}
+
+	first, err := container.Run(context.Background(), opts...)
+	container.Cleanup(t, first)
+	require.NoError(t, err)
+
+	t.Run("running", func(t *testing.T) {
+		ctr, err := container.Run(context.Background(), opts...)
+		require.NoError(t, err)
+		require.Equal(t, first.ID(), ctr.ID())
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		require.NoError(t, first.Stop(context.Background()))
+
+		ctr, err := container.Run(context.Background(), opts...)
+		require.NoError(t, err)
+		require.Equal(t, first.ID(), ctr.ID())
+
+		state, err := ctr.State(context.Background())
+		require.NoError(t, err)
+		require.True(t, state.Running)
+	})
+
+	t.Run("definition-changed", func(t *testing.T) {
+		ctr, err := container.Run(context.Background(), append(opts, container.WithEnv(map[string]string{"FOO": "bar"}))...)
+		container.Cleanup(t, ctr)
+		require.NoError(t, err)
+		require.NotEqual(t, first.ID(), ctr.ID())
+	})
+}
+
 //go:embed testdata/hello.sh
 var helloBytes []byte
 
@@ -0,0 +1,63 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dockerclient "github.com/moby/moby/client"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// ListBySession returns all the containers, running or not, created by the client
+// with the given session ID. See [client.SDKClient.SessionID].
+// If dockerClient is nil, a new client is created with the default options.
+func ListBySession(ctx context.Context, dockerClient client.SDKClient, sessionID string) ([]*Container, error) {
+	if dockerClient == nil {
+		sdk, err := client.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("new docker client: %w", err)
+		}
+		dockerClient = sdk
+	}
+
+	list, err := dockerClient.ContainerList(ctx, dockerclient.ContainerListOptions{
+		All:     true,
+		Filters: client.SessionFilters(sessionID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("container list: %w", err)
+	}
+
+	ctrs := make([]*Container, 0, len(list.Items))
+	for _, summary := range list.Items {
+		ctr, err := FromResponse(ctx, dockerClient, summary)
+		if err != nil {
+			return nil, fmt.Errorf("from response: %w", err)
+		}
+		ctrs = append(ctrs, ctr)
+	}
+
+	return ctrs, nil
+}
+
+// TerminateBySession terminates all the containers created by the client with the given
+// session ID, using the given terminate options. It tries to terminate all of them,
+// returning the errors of the ones that could not be terminated.
+// If dockerClient is nil, a new client is created with the default options.
+func TerminateBySession(ctx context.Context, dockerClient client.SDKClient, sessionID string, opts ...TerminateOption) error {
+	ctrs, err := ListBySession(ctx, dockerClient, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ctr := range ctrs {
+		if err := ctr.Terminate(ctx, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("terminate container %s: %w", ctr.ShortID(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	dockercontainer "github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+)
+
+func TestListBySession(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clienttest.NewFakeAPI()
+	_, err := fake.AddImage("alpine:latest")
+	require.NoError(t, err)
+
+	// create returns the ID of a new container of the given session, started unless it's exited
+	create := func(t *testing.T, sessionID string, exited bool) string {
+		t.Helper()
+
+		resp, err := fake.ContainerCreate(ctx, dockerclient.ContainerCreateOptions{
+			Config: &dockercontainer.Config{Image: "alpine:latest", Labels: map[string]string{client.LabelSessionID: sessionID}},
+		})
+		require.NoError(t, err)
+
+		_, err = fake.ContainerStart(ctx, resp.ID, dockerclient.ContainerStartOptions{})
+		require.NoError(t, err)
+		if exited {
+			require.NoError(t, fake.Exit(resp.ID, 0))
+		}
+
+		return resp.ID
+	}
+
+	running := create(t, "session-1", false)
+	exited := create(t, "session-1", true)
+	other := create(t, "session-2", false)
+
+	sdk, err := clienttest.NewFakeClient(ctx, fake)
+	require.NoError(t, err)
+
+	ctrs, err := ListBySession(ctx, sdk, "session-1")
+	require.NoError(t, err)
+
+	states := make(map[string]bool, len(ctrs))
+	for _, ctr := range ctrs {
+		states[ctr.ID()] = ctr.IsRunning()
+	}
+	require.Equal(t, map[string]bool{running: true, exited: false}, states)
+
+	t.Run("terminate", func(t *testing.T) {
+		require.NoError(t, TerminateBySession(ctx, sdk, "session-2"))
+
+		_, err := fake.ContainerInspect(ctx, other, dockerclient.ContainerInspectOptions{})
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+
+		_, err = fake.ContainerInspect(ctx, running, dockerclient.ContainerInspectOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("no-containers", func(t *testing.T) {
+		ctrs, err := ListBySession(ctx, sdk, "unknown")
+		require.NoError(t, err)
+		require.Empty(t, ctrs)
+	})
+}
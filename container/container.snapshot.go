@@ -0,0 +1,136 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// snapshotLabel is the label of the images created with [Container.Snapshot],
+// which holds the image of the container the snapshot was taken from.
+const snapshotLabel = moduleLabel + ".snapshot-of"
+
+// snapshotOptions are the options for taking a snapshot of a container.
+type snapshotOptions struct {
+	ignoreMounts bool
+}
+
+// SnapshotOption is a type that represents an option for taking a snapshot of a container.
+type SnapshotOption func(*snapshotOptions)
+
+// SnapshotIgnoreMounts returns a SnapshotOption that takes the snapshot even if the container
+// has volumes or other mounts, e.g. because the data to snapshot is written outside of them.
+// The data of the mounts is still not captured.
+func SnapshotIgnoreMounts() SnapshotOption {
+	return func(o *snapshotOptions) {
+		o.ignoreMounts = true
+	}
+}
+
+// Snapshot commits the current filesystem of the container to an image with the given tag,
+// so that new containers can be started from it with [WithSnapshot], skipping the slow
+// initialisation of the original one, e.g. seeding a database.
+// The configuration of the container is preserved in the image: its command, entrypoint,
+// environment, exposed ports, healthcheck, user, working directory and labels, so the
+// new containers behave like the original one. The labels tying the container to the
+// session of its client are left out, i.e. the session ID, the run metadata, the reaper
+// skip and the hash of the reused configuration: the containers started from the image
+// get the ones of their own client, and the image is not removed by the reaper, so it
+// must be removed once it's no longer needed, e.g. with image.Remove.
+// The container is paused while it's being committed. Returns the ID of the image.
+//
+// The data of the volumes and other mounts of the container is not part of its filesystem,
+// so it's not captured by the snapshot: the snapshot fails with a failed precondition error
+// if the container has any, e.g. the volume declared with VOLUME by the image of a database,
+// unless [SnapshotIgnoreMounts] is passed because the data is written outside of them,
+// e.g. setting PGDATA to another directory for PostgreSQL.
+func (c *Container) Snapshot(ctx context.Context, tag string, opts ...SnapshotOption) (string, error) {
+	if tag == "" {
+		return "", errdefs.ErrInvalidArgument.WithMessage("snapshot tag is empty")
+	}
+
+	var options snapshotOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("inspect container: %w", err)
+	}
+
+	if paths := mountPaths(inspect.Container); len(paths) > 0 && !options.ignoreMounts {
+		return "", errdefs.ErrFailedPrecondition.WithMessage(
+			"snapshot does not capture the data of the mounts of the container: " + strings.Join(paths, ", "))
+	}
+
+	cfg := snapshotConfig(inspect.Container.Config)
+	cfg.Labels[snapshotLabel] = c.image
+
+	resp, err := c.dockerClient.ContainerCommit(ctx, c.ID(), dockerclient.ContainerCommitOptions{
+		Reference: tag,
+		Comment:   "snapshot of container " + c.ShortID(),
+		Config:    cfg,
+	})
+	if err != nil {
+		return "", fmt.Errorf("container commit: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+// snapshotConfig returns the configuration of the image of a snapshot, keeping
+// the fields of the container configuration that are inherited by the containers
+// created from an image, and leaving out the ones of the container instance,
+// like its hostname, and the labels of the session of the client.
+func snapshotConfig(cfg *container.Config) *container.Config {
+	if cfg == nil {
+		return &container.Config{Labels: make(map[string]string)}
+	}
+
+	labels := maps.Clone(cfg.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	maps.DeleteFunc(labels, func(key, _ string) bool {
+		return key == client.LabelSessionID || key == client.LabelReaperSkip || key == configHashLabel ||
+			strings.HasPrefix(key, client.LabelRunMetadata+".")
+	})
+
+	return &container.Config{
+		User:         cfg.User,
+		ExposedPorts: maps.Clone(cfg.ExposedPorts),
+		Env:          cfg.Env,
+		Cmd:          cfg.Cmd,
+		Healthcheck:  cfg.Healthcheck,
+		Volumes:      maps.Clone(cfg.Volumes),
+		WorkingDir:   cfg.WorkingDir,
+		Entrypoint:   cfg.Entrypoint,
+		Labels:       labels,
+		StopSignal:   cfg.StopSignal,
+		Shell:        cfg.Shell,
+	}
+}
+
+// mountPaths returns the sorted paths of the volumes declared by the container,
+// and of its mounts, whose data is not committed with its filesystem.
+func mountPaths(inspect container.InspectResponse) []string {
+	var paths []string
+	if inspect.Config != nil {
+		paths = slices.Collect(maps.Keys(inspect.Config.Volumes))
+	}
+	for _, m := range inspect.Mounts {
+		paths = append(paths, m.Destination)
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
@@ -0,0 +1,52 @@
+package container_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/container"
+	"github.com/docker/go-sdk/container/exec"
+	"github.com/docker/go-sdk/image"
+)
+
+func TestContainer_Snapshot_run(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := container.Run(ctx,
+		container.WithImage(alpineLatest),
+		container.WithCmd("sleep", "infinity"),
+		container.WithEnv(map[string]string{"SEEDED_BY": "snapshot-test"}),
+	)
+	container.Cleanup(t, ctr)
+	require.NoError(t, err)
+
+	code, _, err := ctr.Exec(ctx, []string{"sh", "-c", "echo seeded > /seed.txt"})
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	const tag = "go-sdk-snapshot-test:latest"
+
+	id, err := ctr.Snapshot(ctx, tag)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	t.Cleanup(func() {
+		_, err := image.Remove(context.Background(), tag, image.WithRemoveClient(ctr.Client()))
+		require.NoError(t, err)
+	})
+
+	restored, err := container.Run(ctx, container.WithClient(ctr.Client()), container.WithSnapshot(tag))
+	container.Cleanup(t, restored)
+	require.NoError(t, err)
+
+	// the file system and the configuration of the original container are preserved
+	code, reader, err := restored.Exec(ctx, []string{"sh", "-c", "cat /seed.txt && echo $SEEDED_BY"}, exec.Multiplexed())
+	require.NoError(t, err)
+	require.Equal(t, 0, code)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "seeded\nsnapshot-test\n", string(out))
+}
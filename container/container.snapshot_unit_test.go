@@ -0,0 +1,165 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// snapshotMockCli is a mock implementation of client.APIClient, which
+// records the options used to commit a container and to create containers.
+type snapshotMockCli struct {
+	mockCli
+
+	config *dockercontainer.Config
+	mounts []dockercontainer.MountPoint
+	images map[string]bool
+
+	commitID      string
+	commitOptions dockerclient.ContainerCommitOptions
+	createOptions dockerclient.ContainerCreateOptions
+}
+
+func (m *snapshotMockCli) ContainerInspect(_ context.Context, containerID string, _ dockerclient.ContainerInspectOptions) (dockerclient.ContainerInspectResult, error) {
+	return dockerclient.ContainerInspectResult{Container: dockercontainer.InspectResponse{
+		ID:     containerID,
+		Config: m.config,
+		Mounts: m.mounts,
+		State:  &dockercontainer.State{Status: dockercontainer.StateRunning, Running: true},
+	}}, nil
+}
+
+func (m *snapshotMockCli) ContainerCommit(_ context.Context, containerID string, options dockerclient.ContainerCommitOptions) (dockerclient.ContainerCommitResult, error) {
+	m.commitID = containerID
+	m.commitOptions = options
+	return dockerclient.ContainerCommitResult{ID: "sha256:snapshot"}, nil
+}
+
+func (m *snapshotMockCli) ImageInspect(_ context.Context, image string, _ ...dockerclient.ImageInspectOption) (dockerclient.ImageInspectResult, error) {
+	if !m.images[image] {
+		return dockerclient.ImageInspectResult{}, errdefs.ErrNotFound.WithMessage("no such image: " + image)
+	}
+	return dockerclient.ImageInspectResult{}, nil
+}
+
+func (m *snapshotMockCli) ContainerCreate(_ context.Context, options dockerclient.ContainerCreateOptions) (dockerclient.ContainerCreateResult, error) {
+	m.createOptions = options
+	return dockerclient.ContainerCreateResult{ID: "0123456789ab0123456789ab0123456789ab0123456789ab0123456789ab0123"}, nil
+}
+
+func TestContainer_Snapshot(t *testing.T) {
+	m := &snapshotMockCli{
+		config: &dockercontainer.Config{
+			Hostname:     "original",
+			Image:        "postgres:16-alpine",
+			Env:          []string{"POSTGRES_PASSWORD=secret"},
+			Cmd:          []string{"postgres"},
+			Entrypoint:   []string{"docker-entrypoint.sh"},
+			ExposedPorts: network.PortSet{network.MustParsePort("5432/tcp"): {}},
+			WorkingDir:   "/data",
+			Labels: map[string]string{
+				"app":                               "db",
+				client.LabelBase:                    "true",
+				client.LabelSessionID:               "session-1",
+				client.LabelRunMetadata + ".ci.job": "42",
+				client.LabelReaperSkip:              "true",
+				configHashLabel:                     "hash",
+			},
+		},
+	}
+	sdk := newMockClient(t, m)
+
+	ctr := &Container{
+		dockerClient: sdk,
+		containerID:  "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		shortID:      "fedcba987654",
+		image:        "postgres:16-alpine",
+		logger:       sdk.Logger(),
+	}
+
+	id, err := ctr.Snapshot(context.Background(), "seeded-db:latest")
+	require.NoError(t, err)
+	require.Equal(t, "sha256:snapshot", id)
+	require.Equal(t, ctr.ID(), m.commitID)
+	require.Equal(t, "seeded-db:latest", m.commitOptions.Reference)
+
+	cfg := m.commitOptions.Config
+	require.Empty(t, cfg.Hostname)
+	require.Equal(t, m.config.Env, cfg.Env)
+	require.Equal(t, m.config.Cmd, cfg.Cmd)
+	require.Equal(t, m.config.Entrypoint, cfg.Entrypoint)
+	require.Equal(t, m.config.ExposedPorts, cfg.ExposedPorts)
+	require.Equal(t, "/data", cfg.WorkingDir)
+	// the labels of the session of the client are left out, so the image is not reaped with it
+	require.Equal(t, map[string]string{"app": "db", client.LabelBase: "true", snapshotLabel: "postgres:16-alpine"}, cfg.Labels)
+	// the labels of the container are not modified
+	require.NotContains(t, m.config.Labels, snapshotLabel)
+
+	t.Run("empty-tag", func(t *testing.T) {
+		_, err := ctr.Snapshot(context.Background(), "")
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+	})
+
+	t.Run("volumes", func(t *testing.T) {
+		m.commitID = ""
+		m.config.Volumes = map[string]struct{}{"/var/lib/postgresql/data": {}}
+		m.mounts = []dockercontainer.MountPoint{
+			{Type: "volume", Destination: "/var/lib/postgresql/data"},
+			{Type: "bind", Source: "/tmp/init", Destination: "/docker-entrypoint-initdb.d"},
+		}
+		defer func() {
+			m.config.Volumes = nil
+			m.mounts = nil
+		}()
+
+		// the data of the volumes would be silently lost
+		_, err := ctr.Snapshot(context.Background(), "seeded-db:latest")
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+		require.ErrorContains(t, err, "/docker-entrypoint-initdb.d, /var/lib/postgresql/data")
+		require.Empty(t, m.commitID)
+
+		// the data is written outside of the volumes
+		_, err = ctr.Snapshot(context.Background(), "seeded-db:latest", SnapshotIgnoreMounts())
+		require.NoError(t, err)
+		require.Equal(t, ctr.ID(), m.commitID)
+	})
+}
+
+func TestRun_withSnapshot(t *testing.T) {
+	m := &snapshotMockCli{images: map[string]bool{"seeded-db:latest": true}}
+	sdk := newMockClient(t, m)
+
+	t.Run("not-substituted", func(t *testing.T) {
+		_, err := Run(context.Background(),
+			WithClient(sdk),
+			WithImageSubstitutors(NewCustomHubSubstitutor("registry.example.com")),
+			WithSnapshot("seeded-db:latest"),
+			WithAlwaysPull(),
+			WithNoStart(),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "seeded-db:latest", m.createOptions.Config.Image)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := Run(context.Background(),
+			WithClient(sdk),
+			WithSnapshot("missing:latest"),
+			WithNoStart(),
+		)
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+		require.ErrorContains(t, err, "snapshot missing:latest")
+	})
+
+	t.Run("empty-tag", func(t *testing.T) {
+		_, err := Run(context.Background(), WithClient(sdk), WithSnapshot(""))
+		require.ErrorContains(t, err, "snapshot tag is empty")
+	})
+}
@@ -17,7 +17,6 @@ func (c *Container) Start(ctx context.Context) error {
 	if _, err := c.dockerClient.ContainerStart(ctx, c.ID(), client.ContainerStartOptions{}); err != nil {
 		return fmt.Errorf("container start: %w", err)
 	}
-	defer c.dockerClient.Close()
 
 	err = c.startedHook(ctx)
 	if err != nil {
@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// newRunDaemon returns the docker host of a daemon with the given name, answering the
+// requests needed to run a container.
+func newRunDaemon(t *testing.T, name string) string {
+	t.Helper()
+
+	const id = "0123456789abcdef0123456789abcdef"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.52")
+		w.Header().Set("Content-Type", "application/json")
+
+		switch path := r.URL.Path; {
+		case strings.HasSuffix(path, "/_ping"):
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(path, "/info"):
+			_ = json.NewEncoder(w).Encode(system.Info{Name: name})
+		case strings.Contains(path, "/images/") && strings.HasSuffix(path, "/json"):
+			_ = json.NewEncoder(w).Encode(image.InspectResponse{ID: "sha256:" + id})
+		case strings.HasSuffix(path, "/containers/create"):
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(container.CreateResponse{ID: id})
+		case strings.HasSuffix(path, "/containers/"+id+"/start"), strings.HasSuffix(path, "/containers/"+id+"/stop"),
+			r.Method == http.MethodDelete && strings.HasSuffix(path, "/containers/"+id):
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(path, "/containers/"+id+"/json"):
+			_ = json.NewEncoder(w).Encode(container.InspectResponse{
+				ID:     id,
+				State:  &container.State{Status: container.StateRunning, Running: true},
+				Config: &container.Config{},
+			})
+		default:
+			t.Logf("unexpected request: %s %s", r.Method, path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return "tcp://" + srv.Listener.Addr().String()
+}
+
+func TestRun_contextWatch(t *testing.T) {
+	hostA := newRunDaemon(t, "daemon-a")
+	hostB := newRunDaemon(t, "daemon-b")
+
+	dockercontext.SetupTestDockerContexts(t, 1, 1)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	_, err := dockercontext.New("context-a", dockercontext.WithHost(hostA), dockercontext.AsCurrent())
+	require.NoError(t, err)
+	_, err = dockercontext.New("context-b", dockercontext.WithHost(hostB))
+	require.NoError(t, err)
+
+	cli, err := client.New(context.Background(), client.WithoutReaper(),
+		client.WithContextWatch(dockercontext.WithWatchInterval(10*time.Millisecond)))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cli.Close())
+	}()
+
+	ctr, err := Run(context.Background(), WithClient(cli), WithImage(nginxAlpineImage))
+	require.NoError(t, err)
+	require.False(t, ctr.ownsClient)
+
+	// the client passed to the container keeps following the current context once it's started
+	require.NoError(t, dockercontext.Use("context-b"))
+	require.Eventually(t, func() bool { return cli.DaemonHost() == hostB }, 5*time.Second, 10*time.Millisecond)
+
+	info, err := cli.Info(context.Background(), dockerclient.InfoOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "daemon-b", info.Info.Name)
+
+	// the client passed to the container is not closed when it's terminated
+	require.NoError(t, ctr.Terminate(context.Background()))
+	require.NoError(t, dockercontext.Use("context-a"))
+	require.Eventually(t, func() bool { return cli.DaemonHost() == hostA }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRun_defaultClient(t *testing.T) {
+	t.Setenv("DOCKER_HOST", newRunDaemon(t, "daemon"))
+	t.Setenv(client.EnvReaperDisabled, "true")
+
+	ctr, err := Run(context.Background(), WithImage(nginxAlpineImage))
+	require.NoError(t, err)
+	require.True(t, ctr.ownsClient)
+
+	// the default client is owned by the container, which closes it once terminated
+	require.NoError(t, ctr.Terminate(context.Background()))
+	require.False(t, ctr.ownsClient)
+}
@@ -0,0 +1,202 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+)
+
+// Stats is a sample of the resource usage of a container, computed
+// from the statistics returned by the Docker daemon.
+type Stats struct {
+	// Read is the time the sample was collected at.
+	Read time.Time
+
+	// CPUPercentage is the percentage of the host's CPU used by the container,
+	// since the previous sample. It can be greater than 100 on multi-core hosts.
+	CPUPercentage float64
+
+	// MemoryUsage is the memory used by the container, in bytes, excluding the page cache.
+	MemoryUsage uint64
+
+	// MemoryLimit is the memory limit of the container, in bytes.
+	MemoryLimit uint64
+
+	// MemoryPercentage is the percentage of the memory limit used by the container.
+	MemoryPercentage float64
+
+	// NetworkRx is the number of bytes received by the container, on all its networks.
+	NetworkRx uint64
+
+	// NetworkTx is the number of bytes sent by the container, on all its networks.
+	NetworkTx uint64
+
+	// BlockRead is the number of bytes read by the container from block devices.
+	BlockRead uint64
+
+	// BlockWrite is the number of bytes written by the container to block devices.
+	BlockWrite uint64
+
+	// PIDs is the number of processes, or threads, of the container.
+	PIDs uint64
+
+	// Raw is the sample returned by the Docker daemon.
+	Raw container.StatsResponse
+}
+
+// Stats returns a sample of the resource usage of the container.
+// The daemon collects two samples, one second apart, to compute the CPU percentage.
+func (c *Container) Stats(ctx context.Context) (Stats, error) {
+	resp, err := c.dockerClient.ContainerStats(ctx, c.ID(), dockerclient.ContainerStatsOptions{
+		IncludePreviousSample: true,
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return Stats{}, fmt.Errorf("decode stats: %w", err)
+	}
+
+	return newStats(raw), nil
+}
+
+// StreamStats returns the samples of the resource usage of the container, as they
+// are collected by the daemon, roughly every second, until the context is done,
+// the container stops, or an error occurs, which is yielded last.
+// As there is no previous sample to compare with, the CPU percentage of the
+// first sample is computed since the container started.
+func (c *Container) StreamStats(ctx context.Context) iter.Seq2[Stats, error] {
+	return func(yield func(Stats, error) bool) {
+		resp, err := c.dockerClient.ContainerStats(ctx, c.ID(), dockerclient.ContainerStatsOptions{
+			Stream: true,
+		})
+		if err != nil {
+			yield(Stats{}, fmt.Errorf("container stats: %w", err))
+			return
+		}
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var raw container.StatsResponse
+			if err := dec.Decode(&raw); err != nil {
+				if ctx.Err() != nil {
+					yield(Stats{}, ctx.Err())
+					return
+				}
+
+				if !errors.Is(err, io.EOF) {
+					yield(Stats{}, fmt.Errorf("decode stats: %w", err))
+				}
+				return
+			}
+
+			if !yield(newStats(raw), nil) {
+				return
+			}
+		}
+	}
+}
+
+// newStats computes the resource usage of the given sample, following
+// the same rules as the docker stats command.
+func newStats(raw container.StatsResponse) Stats {
+	s := Stats{
+		Read: raw.Read,
+		PIDs: raw.PidsStats.Current,
+		Raw:  raw,
+	}
+
+	for _, nw := range raw.Networks {
+		s.NetworkRx += nw.RxBytes
+		s.NetworkTx += nw.TxBytes
+	}
+
+	if raw.OSType == "windows" {
+		s.CPUPercentage = windowsCPUPercentage(raw)
+		s.MemoryUsage = raw.MemoryStats.PrivateWorkingSet
+		s.BlockRead = raw.StorageStats.ReadSizeBytes
+		s.BlockWrite = raw.StorageStats.WriteSizeBytes
+		return s
+	}
+
+	s.CPUPercentage = unixCPUPercentage(raw)
+	s.MemoryUsage = memoryUsageNoCache(raw.MemoryStats)
+	s.MemoryLimit = raw.MemoryStats.Limit
+	if s.MemoryLimit > 0 {
+		s.MemoryPercentage = float64(s.MemoryUsage) / float64(s.MemoryLimit) * 100
+	}
+
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			s.BlockRead += entry.Value
+		case "write":
+			s.BlockWrite += entry.Value
+		}
+	}
+
+	return s
+}
+
+// unixCPUPercentage returns the CPU percentage of a Linux container,
+// comparing the CPU usage of the container with the one of the host.
+func unixCPUPercentage(raw container.StatsResponse) float64 {
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+
+	onlineCPUs := float64(raw.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// windowsCPUPercentage returns the CPU percentage of a Windows container, comparing
+// the CPU usage of the container with the time elapsed since the previous sample.
+func windowsCPUPercentage(raw container.StatsResponse) float64 {
+	if raw.PreRead.IsZero() {
+		return 0
+	}
+
+	// the CPU usage is reported in intervals of 100 nanoseconds
+	possibleIntervals := float64(raw.Read.Sub(raw.PreRead).Nanoseconds()) / 100 * float64(raw.NumProcs)
+	if possibleIntervals <= 0 {
+		return 0
+	}
+
+	intervalsUsed := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	return intervalsUsed / possibleIntervals * 100
+}
+
+// memoryUsageNoCache returns the memory usage, excluding the inactive page cache,
+// which the kernel can reclaim. The stat name depends on the cgroup version.
+func memoryUsageNoCache(mem container.MemoryStats) uint64 {
+	// cgroup v1
+	if v, ok := mem.Stats["total_inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+
+	// cgroup v2
+	if v, ok := mem.Stats["inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+
+	return mem.Usage
+}
@@ -0,0 +1,46 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/container"
+)
+
+func TestContainer_StatsWithMemoryLimit(t *testing.T) {
+	const memoryLimit = 64 * 1024 * 1024
+
+	ctx := context.Background()
+	ctr, err := container.Run(ctx,
+		container.WithImage(alpineLatest),
+		container.WithCmd("sleep", "infinity"),
+		container.WithHostConfigModifier(func(hc *dockercontainer.HostConfig) {
+			hc.Memory = memoryLimit
+		}),
+	)
+	container.Cleanup(t, ctr)
+	require.NoError(t, err)
+
+	stats, err := ctr.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(memoryLimit), stats.MemoryLimit)
+	require.Positive(t, stats.MemoryUsage)
+	require.Less(t, stats.MemoryUsage, uint64(memoryLimit))
+
+	t.Run("stream", func(t *testing.T) {
+		var samples int
+		for stats, err := range ctr.StreamStats(ctx) {
+			require.NoError(t, err)
+			require.Equal(t, uint64(memoryLimit), stats.MemoryLimit)
+
+			samples++
+			if samples == 2 {
+				break
+			}
+		}
+		require.Equal(t, 2, samples)
+	})
+}
@@ -0,0 +1,155 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	dockercontainer "github.com/moby/moby/api/types/container"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+)
+
+// statsMockCli is a mock implementation of client.APIClient, which returns
+// the given samples as the statistics of the container.
+type statsMockCli struct {
+	mockCli
+
+	samples []dockercontainer.StatsResponse
+	options dockerclient.ContainerStatsOptions
+}
+
+func (m *statsMockCli) ContainerStats(_ context.Context, _ string, options dockerclient.ContainerStatsOptions) (dockerclient.ContainerStatsResult, error) {
+	m.options = options
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range m.samples {
+		if err := enc.Encode(s); err != nil {
+			return dockerclient.ContainerStatsResult{}, err
+		}
+	}
+
+	return dockerclient.ContainerStatsResult{Body: io.NopCloser(&buf)}, nil
+}
+
+// linuxSample returns a sample of a Linux container, using 2 CPUs.
+func linuxSample(totalUsage, systemUsage, preTotalUsage, preSystemUsage uint64) dockercontainer.StatsResponse {
+	return dockercontainer.StatsResponse{
+		OSType: "linux",
+		Read:   time.Unix(100, 0).UTC(),
+		CPUStats: dockercontainer.CPUStats{
+			CPUUsage:    dockercontainer.CPUUsage{TotalUsage: totalUsage},
+			SystemUsage: systemUsage,
+			OnlineCPUs:  2,
+		},
+		PreCPUStats: dockercontainer.CPUStats{
+			CPUUsage:    dockercontainer.CPUUsage{TotalUsage: preTotalUsage},
+			SystemUsage: preSystemUsage,
+		},
+		MemoryStats: dockercontainer.MemoryStats{
+			Usage: 300,
+			Limit: 1000,
+			Stats: map[string]uint64{"inactive_file": 100},
+		},
+		Networks: map[string]dockercontainer.NetworkStats{
+			"eth0": {RxBytes: 10, TxBytes: 20},
+			"eth1": {RxBytes: 1, TxBytes: 2},
+		},
+		BlkioStats: dockercontainer.BlkioStats{
+			IoServiceBytesRecursive: []dockercontainer.BlkioStatEntry{
+				{Op: "read", Value: 5},
+				{Op: "Write", Value: 7},
+			},
+		},
+		PidsStats: dockercontainer.PidsStats{Current: 3},
+	}
+}
+
+func TestContainer_Stats(t *testing.T) {
+	m := &statsMockCli{
+		samples: []dockercontainer.StatsResponse{linuxSample(300, 2000, 100, 1000)},
+	}
+	ctr := newMockContainer(t, m)
+
+	stats, err := ctr.Stats(context.Background())
+	require.NoError(t, err)
+	require.True(t, m.options.IncludePreviousSample)
+	require.False(t, m.options.Stream)
+
+	require.Equal(t, time.Unix(100, 0).UTC(), stats.Read)
+	// (300-100)/(2000-1000) * 2 CPUs * 100
+	require.InDelta(t, 40.0, stats.CPUPercentage, 0.001)
+	require.Equal(t, uint64(200), stats.MemoryUsage)
+	require.Equal(t, uint64(1000), stats.MemoryLimit)
+	require.InDelta(t, 20.0, stats.MemoryPercentage, 0.001)
+	require.Equal(t, uint64(11), stats.NetworkRx)
+	require.Equal(t, uint64(22), stats.NetworkTx)
+	require.Equal(t, uint64(5), stats.BlockRead)
+	require.Equal(t, uint64(7), stats.BlockWrite)
+	require.Equal(t, uint64(3), stats.PIDs)
+
+	t.Run("no-samples", func(t *testing.T) {
+		ctr := newMockContainer(t, &statsMockCli{})
+
+		_, err := ctr.Stats(context.Background())
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("windows", func(t *testing.T) {
+		m := &statsMockCli{
+			samples: []dockercontainer.StatsResponse{{
+				OSType:      "windows",
+				Read:        time.Unix(101, 0),
+				PreRead:     time.Unix(100, 0),
+				NumProcs:    2,
+				CPUStats:    dockercontainer.CPUStats{CPUUsage: dockercontainer.CPUUsage{TotalUsage: 15_000_000}},
+				PreCPUStats: dockercontainer.CPUStats{CPUUsage: dockercontainer.CPUUsage{TotalUsage: 5_000_000}},
+				MemoryStats: dockercontainer.MemoryStats{PrivateWorkingSet: 42},
+			}},
+		}
+		ctr := newMockContainer(t, m)
+
+		stats, err := ctr.Stats(context.Background())
+		require.NoError(t, err)
+		// 10M intervals of 100ns used, out of 1s * 2 CPUs = 20M intervals
+		require.InDelta(t, 50.0, stats.CPUPercentage, 0.001)
+		require.Equal(t, uint64(42), stats.MemoryUsage)
+	})
+}
+
+func TestContainer_StreamStats(t *testing.T) {
+	m := &statsMockCli{
+		samples: []dockercontainer.StatsResponse{
+			// the first sample has no previous sample, so it's computed since the container started
+			linuxSample(100, 1000, 0, 0),
+			linuxSample(300, 2000, 100, 1000),
+			linuxSample(400, 3000, 300, 2000),
+		},
+	}
+	ctr := newMockContainer(t, m)
+
+	var cpu []float64
+	for stats, err := range ctr.StreamStats(context.Background()) {
+		require.NoError(t, err)
+		cpu = append(cpu, stats.CPUPercentage)
+	}
+	require.True(t, m.options.Stream)
+	require.Len(t, cpu, 3)
+	require.InDelta(t, 20.0, cpu[0], 0.001)
+	require.InDelta(t, 40.0, cpu[1], 0.001)
+	require.InDelta(t, 20.0, cpu[2], 0.001)
+
+	t.Run("stop", func(t *testing.T) {
+		var samples int
+		for _, err := range ctr.StreamStats(context.Background()) {
+			require.NoError(t, err)
+			samples++
+			break
+		}
+		require.Equal(t, 1, samples)
+	})
+}
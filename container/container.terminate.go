@@ -107,6 +107,7 @@ func isNil(val any) bool {
 // Terminate calls stops and then removes the container including its volumes.
 // If its image was built it and all child images are also removed unless
 // the [FromDockerfile.KeepImage] on the [ContainerRequest] was set to true.
+// The docker client created for the container, when none was passed, is closed.
 //
 // The following hooks are called in order:
 //   - [LifecycleHooks.PreTerminates]
@@ -137,5 +138,12 @@ func (c *Container) Terminate(ctx context.Context, opts ...TerminateOption) erro
 		errs = append(errs, err)
 	}
 
+	if c.ownsClient {
+		if err = c.dockerClient.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close docker client: %w", err))
+		}
+		c.ownsClient = false
+	}
+
 	return errors.Join(errs...)
 }
@@ -26,5 +26,6 @@ func TestFromResponse(t *testing.T) {
 	require.Equal(t, "nginx:latest", ctr.Image())
 	require.Equal(t, []string{"80/tcp", "8080/udp"}, ctr.exposedPorts)
 	require.NotNil(t, ctr.dockerClient)
+	require.True(t, ctr.ownsClient)
 	require.NotNil(t, ctr.logger)
 }
@@ -27,6 +27,13 @@ type Definition struct {
 	// dockerClient the docker client to use for the container.
 	dockerClient client.SDKClient
 
+	// ownsClient is true if the docker client was created for the container,
+	// which closes it when it's terminated.
+	ownsClient bool
+
+	// clientPool the pool of clients to schedule the container on, if no docker client is set.
+	clientPool *client.Pool
+
 	// configModifier the modifier for the config before container creation
 	configModifier func(*container.Config)
 
@@ -93,6 +100,12 @@ type Definition struct {
 
 	// started whether to auto-start the container.
 	started bool
+
+	// reuse whether to reuse an existing container with the same name.
+	reuse bool
+
+	// snapshot whether the image is a local snapshot of another container.
+	snapshot bool
 }
 
 // validate validates the definition.
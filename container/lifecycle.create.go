@@ -11,6 +11,7 @@ import (
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 	dockerclient "github.com/moby/moby/client"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/docker/go-connections/nat"
 	"github.com/docker/go-sdk/client"
@@ -104,7 +105,10 @@ var defaultReadinessHook = func() LifecycleHooks {
 						strategyDesc = s.String()
 					}
 					c.Logger().Info("Waiting for container to be ready", "containerID", c.ShortID(), "image", c.Image(), "strategy", strategyDesc)
-					if err := strategy.WaitUntilReady(ctx, waiter); err != nil {
+					waitCtx, span := startSpan(ctx, "container.wait", attribute.String("docker.wait.strategy", strategyDesc))
+					err := strategy.WaitUntilReady(waitCtx, waiter)
+					endSpan(span, err)
+					if err != nil {
 						return fmt.Errorf("wait until ready: %w", err)
 					}
 				}
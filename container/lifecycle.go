@@ -286,7 +286,7 @@ var defaultPullHook = []DefinitionHook{
 
 		var shouldPullImage bool
 
-		if def.alwaysPullImage {
+		if def.alwaysPullImage && !def.snapshot {
 			shouldPullImage = true // If requested always attempt to pull image
 		} else {
 			img, err := def.dockerClient.ImageInspect(ctx, def.image)
@@ -294,6 +294,10 @@ var defaultPullHook = []DefinitionHook{
 				if !errdefs.IsNotFound(err) {
 					return err
 				}
+				if def.snapshot {
+					// snapshots are local images, which cannot be pulled
+					return fmt.Errorf("snapshot %s: %w", def.image, err)
+				}
 				shouldPullImage = true
 			}
 			if platform != nil && (img.Architecture != platform.Architecture || img.Os != platform.OS) {
@@ -318,7 +322,10 @@ var defaultPullHook = []DefinitionHook{
 			}
 			pullOpts = append(pullOpts, image.WithPullOptions(pullOpt))
 
-			if err := image.Pull(ctx, def.image, pullOpts...); err != nil {
+			pullCtx, span := startSpan(ctx, "container.pull", imageAttribute(def.image))
+			err := image.Pull(pullCtx, def.image, pullOpts...)
+			endSpan(span, err)
+			if err != nil {
 				return err
 			}
 		}
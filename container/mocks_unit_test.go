@@ -0,0 +1,50 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// mockCli is embedded by the mocks of the endpoints the fake Docker API of the clienttest
+// package doesn't implement, e.g. the exec, logs and commit ones, so that they can be
+// used to run the containers of the unit tests.
+type mockCli struct {
+	dockerclient.APIClient
+}
+
+func (m *mockCli) Ping(_ context.Context, _ dockerclient.PingOptions) (dockerclient.PingResult, error) {
+	return dockerclient.PingResult{}, nil
+}
+
+func (m *mockCli) Close() error {
+	return nil
+}
+
+// newMockClient returns an SDK client backed by the given mock, applying the given options.
+func newMockClient(t *testing.T, m dockerclient.APIClient, opts ...client.ClientOption) client.SDKClient {
+	t.Helper()
+
+	sdk, err := client.New(context.Background(), append([]client.ClientOption{client.WithDockerAPI(m)}, opts...)...)
+	require.NoError(t, err)
+
+	return sdk
+}
+
+// newMockContainer returns a container backed by the given mock.
+func newMockContainer(t *testing.T, m dockerclient.APIClient) *Container {
+	t.Helper()
+
+	sdk := newMockClient(t, m)
+
+	return &Container{
+		dockerClient: sdk,
+		containerID:  "container-id",
+		shortID:      "container-id",
+		logger:       sdk.Logger(),
+	}
+}
@@ -38,7 +38,8 @@ func (opt CustomizeDefinitionOption) Customize(def *Definition) error {
 	return opt(def)
 }
 
-// WithClient sets the client for a container
+// WithClient sets the client for a container. The client is owned by the caller,
+// which closes it: it is not closed when the container is terminated.
 func WithClient(cli client.SDKClient) CustomizeDefinitionOption {
 	return func(def *Definition) error {
 		def.dockerClient = cli
@@ -47,6 +48,22 @@ func WithClient(cli client.SDKClient) CustomizeDefinitionOption {
 	}
 }
 
+// WithClientPool schedules the container on one of the clients of the pool, picked with
+// the scheduling strategy of the pool. The container keeps using the client it was scheduled on,
+// returned by [Container.Client]. If a client is set with [WithClient], it takes precedence.
+// The clients are owned by the pool, which closes them: they are not closed when the container
+// is terminated.
+func WithClientPool(pool *client.Pool) CustomizeDefinitionOption {
+	return func(def *Definition) error {
+		if pool == nil {
+			return errors.New("client pool is nil")
+		}
+		def.clientPool = pool
+
+		return nil
+	}
+}
+
 // WithConfigModifier allows to override the default container config
 func WithConfigModifier(modifier func(config *container.Config)) CustomizeDefinitionOption {
 	return func(def *Definition) error {
@@ -192,6 +209,36 @@ func WithName(containerName string) CustomizeDefinitionOption {
 	}
 }
 
+// WithReuse will reuse an existing container with the name set by [WithName],
+// instead of creating a new one. If the container exists, it's started if it's
+// not running, and the wait strategy is executed again before returning it.
+// The hash of the container configuration is stored in a label, and if the
+// definition changed since the container was created, the existing container
+// is removed and a new one is created.
+// Reused containers are never removed by the reaper of the client.
+// Reusing a container requires a name.
+func WithReuse() CustomizeDefinitionOption {
+	return func(def *Definition) error {
+		def.reuse = true
+		return nil
+	}
+}
+
+// WithSnapshot will start the container from the image of a snapshot, taken with
+// [Container.Snapshot], inheriting the configuration of the container the snapshot
+// was taken from. As the snapshot is a local image, it's never pulled nor replaced
+// by the image substitutors, and running the container fails if it does not exist.
+func WithSnapshot(tag string) CustomizeDefinitionOption {
+	return func(def *Definition) error {
+		if tag == "" {
+			return errors.New("snapshot tag is empty")
+		}
+		def.image = tag
+		def.snapshot = true
+		return nil
+	}
+}
+
 // WithNoStart will prevent the container from being started after creation.
 func WithNoStart() CustomizeDefinitionOption {
 	return func(def *Definition) error {
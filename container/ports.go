@@ -57,14 +57,16 @@ func (c *Container) PortEndpoint(ctx context.Context, port network.Port, proto s
 	return proto + "://" + hostPort, nil
 }
 
-// MappedPort gets externally mapped port for a container port
+// MappedPort gets externally mapped port for a container port.
+// If the client forwards the ports of a remote docker daemon, see [client.WithPortForwarding],
+// it returns the local port forwarding to the mapped TCP port.
 func (c *Container) MappedPort(ctx context.Context, port network.Port) (network.Port, error) {
 	inspect, err := c.Inspect(ctx)
 	if err != nil {
 		return network.Port{}, fmt.Errorf("inspect: %w", err)
 	}
 	if inspect.Container.HostConfig.NetworkMode == "host" {
-		return port, nil
+		return c.forwardedPort(ctx, port)
 	}
 
 	ports := inspect.Container.NetworkSettings.Ports
@@ -79,8 +81,32 @@ func (c *Container) MappedPort(ctx context.Context, port network.Port) (network.
 		if len(p) == 0 {
 			continue
 		}
-		return network.ParsePort(p[0].HostPort + "/" + string(k.Proto()))
+		mapped, err := network.ParsePort(p[0].HostPort + "/" + string(k.Proto()))
+		if err != nil {
+			return network.Port{}, err
+		}
+		return c.forwardedPort(ctx, mapped)
 	}
 
 	return network.Port{}, errdefs.ErrNotFound.WithMessage(fmt.Sprintf("port %q not found", port))
 }
+
+// forwardedPort returns the local port forwarding to the given port of the docker host,
+// if the client forwards ports. Otherwise, or if it's not a TCP port, it returns the port.
+func (c *Container) forwardedPort(ctx context.Context, port network.Port) (network.Port, error) {
+	if !c.dockerClient.ForwardsPorts() || port.Proto() != network.TCP {
+		return port, nil
+	}
+
+	addr, err := c.dockerClient.ForwardPort(ctx, port.Num())
+	if err != nil {
+		return network.Port{}, fmt.Errorf("forward port: %w", err)
+	}
+
+	_, local, err := net.SplitHostPort(addr)
+	if err != nil {
+		return network.Port{}, fmt.Errorf("split host port: %w", err)
+	}
+
+	return network.ParsePort(local + "/" + string(network.TCP))
+}
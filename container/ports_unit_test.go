@@ -0,0 +1,66 @@
+package container
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// forwardingClient is a client.SDKClient forwarding the ports of a remote docker
+// daemon, which maps each port of the docker host to port+10000.
+type forwardingClient struct {
+	client.SDKClient
+
+	ports network.PortMap
+}
+
+func (c *forwardingClient) ForwardsPorts() bool {
+	return true
+}
+
+func (c *forwardingClient) ForwardPort(_ context.Context, port uint16) (string, error) {
+	return "127.0.0.1:" + strconv.Itoa(int(port)+10000), nil
+}
+
+func (c *forwardingClient) ContainerInspect(_ context.Context, containerID string, _ dockerclient.ContainerInspectOptions) (dockerclient.ContainerInspectResult, error) {
+	return dockerclient.ContainerInspectResult{Container: dockercontainer.InspectResponse{
+		ID:              containerID,
+		HostConfig:      &dockercontainer.HostConfig{},
+		NetworkSettings: &dockercontainer.NetworkSettings{Ports: c.ports},
+	}}, nil
+}
+
+func TestContainer_portForwarding(t *testing.T) {
+	ctr := &Container{
+		dockerClient: &forwardingClient{ports: network.PortMap{
+			network.MustParsePort("80/tcp"): {{HostPort: "32768"}},
+			network.MustParsePort("53/udp"): {{HostPort: "32769"}},
+		}},
+		containerID: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		shortID:     "fedcba987654",
+	}
+
+	host, err := ctr.Host(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", host)
+
+	port, err := ctr.MappedPort(context.Background(), network.MustParsePort("80/tcp"))
+	require.NoError(t, err)
+	require.Equal(t, network.MustParsePort("42768/tcp"), port)
+
+	endpoint, err := ctr.PortEndpoint(context.Background(), network.MustParsePort("80/tcp"), "http")
+	require.NoError(t, err)
+	require.Equal(t, "http://127.0.0.1:42768", endpoint)
+
+	// only TCP ports are forwarded
+	port, err = ctr.MappedPort(context.Background(), network.MustParsePort("53/udp"))
+	require.NoError(t, err)
+	require.Equal(t, network.MustParsePort("32769/udp"), port)
+}
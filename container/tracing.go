@@ -0,0 +1,34 @@
+package container
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// tracerName is the name of the tracer of the spans of the lifecycle phases of the containers.
+const tracerName = "github.com/docker/go-sdk/container"
+
+// startSpan starts a child span of the span in the context, with the same tracer provider.
+// If there's no span in the context, the span is not recorded.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span, recording the error, if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// imageAttribute returns the attribute of the spans with the name of the image.
+func imageAttribute(image string) attribute.KeyValue {
+	return client.AttributeImageName.String(image)
+}
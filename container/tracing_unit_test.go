@@ -0,0 +1,63 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/client/clienttest"
+	"github.com/docker/go-sdk/container/wait"
+)
+
+// readyStrategy is a wait strategy for containers that are ready once started.
+type readyStrategy struct{}
+
+func (*readyStrategy) WaitUntilReady(_ context.Context, _ wait.StrategyTarget) error {
+	return nil
+}
+
+func TestRun_tracing(t *testing.T) {
+	// the pull reads the credentials of the registry from the docker config
+	dockerConfig := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dockerConfig, "config.json"), []byte("{}"), 0o600))
+	t.Setenv("DOCKER_CONFIG", dockerConfig)
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	// the image is missing, so that it's pulled
+	sdk, err := clienttest.NewFakeClient(context.Background(), clienttest.NewFakeAPI(), client.WithTracerProvider(tp))
+	require.NoError(t, err)
+
+	ctr, err := Run(context.Background(), WithClient(sdk), WithImage("nginx:alpine"), WithWaitStrategy(&readyStrategy{}))
+	require.NoError(t, err)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Ended() {
+		byName[span.Name()] = span
+	}
+
+	run := byName["container.Run"]
+	require.NotNil(t, run)
+	require.Contains(t, run.Attributes(), client.AttributeImageName.String("nginx:alpine"))
+	require.Contains(t, run.Attributes(), client.AttributeContainerID.String(ctr.ID()))
+
+	// the lifecycle phases are children of the run, except the wait, which is part of the start
+	for name, parent := range map[string]string{
+		"container.pull":   "container.Run",
+		"container.create": "container.Run",
+		"container.start":  "container.Run",
+		"container.wait":   "container.start",
+	} {
+		span := byName[name]
+		require.NotNil(t, span, name)
+		require.Equal(t, byName[parent].SpanContext().SpanID(), span.Parent().SpanID(), name)
+		require.Equal(t, run.SpanContext().TraceID(), span.SpanContext().TraceID(), name)
+	}
+}
@@ -42,6 +42,10 @@ func (st mockExecTarget) Logs(_ context.Context) (io.ReadCloser, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (st mockExecTarget) StreamLogs(_ context.Context) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (st mockExecTarget) Exec(ctx context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
 	var reader io.Reader
 	if st.response != "" {
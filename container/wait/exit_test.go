@@ -36,6 +36,10 @@ func (st *exitStrategyTarget) Logs(_ context.Context) (io.ReadCloser, error) {
 	return nil, nil
 }
 
+func (st *exitStrategyTarget) StreamLogs(_ context.Context) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (st *exitStrategyTarget) Exec(_ context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
 	return 0, nil, nil
 }
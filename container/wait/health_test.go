@@ -38,6 +38,10 @@ func (st *healthStrategyTarget) Logs(_ context.Context) (io.ReadCloser, error) {
 	return nil, nil
 }
 
+func (st *healthStrategyTarget) StreamLogs(_ context.Context) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (st *healthStrategyTarget) Exec(_ context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
 	return 0, nil, nil
 }
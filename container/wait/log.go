@@ -31,7 +31,19 @@ func NewPermanentError(err error) *PermanentError {
 	return &PermanentError{err: err}
 }
 
-// LogStrategy will wait until a given log entry shows up in the docker logs
+// maxLogWindowSize is the maximum size of the logs kept by [LogStrategy] to match the log entry.
+// Once the logs are larger, the oldest ones are dropped, keeping only the count of their matches,
+// bounding the memory used to read the logs.
+const maxLogWindowSize = 1024 * 1024
+
+// logReadSize is the size of the chunks the logs are read in by [LogStrategy].
+const logReadSize = 32 * 1024
+
+// LogStrategy will wait until a given log entry shows up in the docker logs.
+// The logs are followed as they are written, and the log entry is matched against
+// the logs read so far, so it can span multiple lines. Only the last MiB of the logs
+// is kept to match the log entry: beyond that, "^" no longer matches the start of
+// the logs, and a log entry cannot span the logs dropped from it.
 type LogStrategy struct {
 	// all Strategies should have a startupTimeout to avoid waiting infinitely
 	timeout *time.Duration
@@ -42,17 +54,8 @@ type LogStrategy struct {
 	Occurrence   int
 	PollInterval time.Duration
 
-	// check is the function that will be called to check if the log entry is present.
-	check func([]byte) error
-
 	// submatchCallback is a callback that will be called with the sub matches of the regexp.
 	submatchCallback func(pattern string, matches [][][]byte) error
-
-	// re is the optional compiled regexp.
-	re *regexp.Regexp
-
-	// log byte slice version of [LogStrategy.Log] used for count checks.
-	log []byte
 }
 
 // NewLogStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
@@ -76,7 +79,8 @@ func (ws *LogStrategy) AsRegexp() *LogStrategy {
 }
 
 // Submatch configures a function that will be called with the result of
-// [regexp.Regexp.FindAllSubmatch], allowing the caller to process the results.
+// [regexp.Regexp.FindAllSubmatch] on the logs kept, allowing the caller to process the results.
+// Only the last MiB of the logs is kept, so the sub matches of the older logs are not passed.
 // If the callback returns nil, the strategy will be considered successful.
 // Returning a [PermanentError] will stop the wait and return an error, otherwise
 // it will retry until the timeout is reached.
@@ -93,7 +97,9 @@ func (ws *LogStrategy) WithTimeout(timeout time.Duration) *LogStrategy {
 	return ws
 }
 
-// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds,
+// which is the time to wait before following the logs again, if the log stream ends
+// while the container is still running.
 func (ws *LogStrategy) WithPollInterval(pollInterval time.Duration) *LogStrategy {
 	ws.PollInterval = pollInterval
 	return ws
@@ -145,85 +151,165 @@ func (ws *LogStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget
 		timeout = *ws.timeout
 	}
 
-	switch {
-	case ws.submatchCallback != nil:
-		ws.re = regexp.MustCompile(ws.Log)
-		ws.check = ws.checkSubmatch
-	case ws.IsRegexp:
-		ws.re = regexp.MustCompile(ws.Log)
-		ws.check = ws.checkRegexp
-	default:
-		ws.log = []byte(ws.Log)
-		ws.check = ws.checkCount
+	var re *regexp.Regexp
+	if ws.IsRegexp || ws.submatchCallback != nil {
+		re = regexp.MustCompile(ws.Log)
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	var lastLen int
 	var lastError error
 	for {
+		reader, err := target.StreamLogs(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return errors.Join(lastError, ctx.Err())
+			}
+			return fmt.Errorf("stream logs: %w", err)
+		}
+
+		// the stream returns the logs from the beginning, so start matching from scratch.
+		m := &logMatcher{ws: ws, re: re}
+		err = m.checkLogs(reader)
+		reader.Close()
+		if err == nil {
+			return nil
+		}
+
+		var errPermanent *PermanentError
+		if errors.As(err, &errPermanent) {
+			return err
+		}
+
+		if !errors.Is(err, errLogsEnded) {
+			lastError = err
+		}
+
+		if ctx.Err() != nil {
+			return errors.Join(lastError, ctx.Err())
+		}
+
+		// The log stream ended without a match: it happens when the container
+		// is no longer running, or when the connection to the daemon is lost.
+		if err := checkTarget(ctx, target); err != nil {
+			return err
+		}
+
 		select {
 		case <-ctx.Done():
 			return errors.Join(lastError, ctx.Err())
-		default:
-			checkErr := checkTarget(ctx, target)
-
-			reader, err := target.Logs(ctx)
-			if err != nil {
-				// TODO: fix as this will wait for timeout if the logs are not available.
-				time.Sleep(ws.PollInterval)
-				continue
-			}
+		case <-time.After(ws.PollInterval):
+		}
+	}
+}
+
+// errLogsEnded is returned by [logMatcher.checkLogs] when the logs ended without any output.
+var errLogsEnded = errors.New("logs ended")
+
+// logMatcher matches the log entry of a [LogStrategy] against the logs of a single stream,
+// as they are read, so that a strategy can be used concurrently by multiple containers.
+type logMatcher struct {
+	ws *LogStrategy
 
-			b, err := io.ReadAll(reader)
-			if err != nil {
-				// TODO: fix as this will wait for timeout if the logs are not readable.
-				time.Sleep(ws.PollInterval)
-				continue
+	// re is the compiled regexp, nil if the log entry is a plain string.
+	re *regexp.Regexp
+
+	// window holds the logs read so far, up to maxLogWindowSize.
+	window []byte
+
+	// droppedMatches is the count of the matches found in the logs dropped from the window.
+	droppedMatches int
+}
+
+// checkLogs checks the logs read from r, until the log entry is found, or r is exhausted.
+// It returns nil if the log entry was found, or the last error returned by the check otherwise.
+func (m *logMatcher) checkLogs(r io.Reader) error {
+	buf := make([]byte, logReadSize)
+
+	checkErr := errLogsEnded
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			m.window = append(m.window, buf[:n]...)
+
+			checkErr = m.check()
+			if checkErr == nil {
+				return nil
 			}
 
-			if lastLen == len(b) && checkErr != nil {
-				// Log length hasn't changed so we're not making progress.
+			var errPermanent *PermanentError
+			if errors.As(checkErr, &errPermanent) {
 				return checkErr
 			}
 
-			if err := ws.check(b); err != nil {
-				var errPermanent *PermanentError
-				if errors.As(err, &errPermanent) {
-					return err
-				}
-
-				lastError = err
-				lastLen = len(b)
-				time.Sleep(ws.PollInterval)
-				continue
+			if len(m.window) > maxLogWindowSize {
+				m.slide()
 			}
+		}
 
-			return nil
+		if err != nil {
+			return checkErr
 		}
 	}
 }
 
-// checkCount checks if the log entry is present in the logs using a string count.
-func (ws *LogStrategy) checkCount(b []byte) error {
-	if count := bytes.Count(b, ws.log); count < ws.Occurrence {
-		return fmt.Errorf("%q matched %d times, expected %d", ws.Log, count, ws.Occurrence)
+// check checks if the log entry is present in the logs read so far.
+func (m *logMatcher) check() error {
+	switch {
+	case m.ws.submatchCallback != nil:
+		return m.ws.submatchCallback(m.ws.Log, m.re.FindAllSubmatch(m.window, -1))
+	case m.re != nil:
+		if matches := m.droppedMatches + len(m.re.FindAllIndex(m.window, -1)); matches < m.ws.Occurrence {
+			return fmt.Errorf("`%s` matched %d times, expected %d", m.ws.Log, matches, m.ws.Occurrence)
+		}
+	default:
+		if matches := m.droppedMatches + bytes.Count(m.window, []byte(m.ws.Log)); matches < m.ws.Occurrence {
+			return fmt.Errorf("%q matched %d times, expected %d", m.ws.Log, matches, m.ws.Occurrence)
+		}
 	}
 
 	return nil
 }
 
-// checkRegexp checks if the log entry is present in the logs using a regexp count.
-func (ws *LogStrategy) checkRegexp(b []byte) error {
-	if matches := ws.re.FindAll(b, -1); len(matches) < ws.Occurrence {
-		return fmt.Errorf("`%s` matched %d times, expected %d", ws.Log, len(matches), ws.Occurrence)
+// slide drops the oldest half of the window, keeping the count of the matches found
+// in the dropped logs. A match spanning the cut is kept in the window.
+func (m *logMatcher) slide() {
+	cut := len(m.window) - maxLogWindowSize/2
+
+	for _, loc := range m.findAllIndex() {
+		if loc[0] >= cut {
+			break
+		}
+		if loc[1] > cut {
+			cut = loc[0]
+			break
+		}
+
+		m.droppedMatches++
 	}
 
-	return nil
+	m.window = bytes.Clone(m.window[cut:])
 }
 
-// checkSubmatch checks if the log entry is present in the logs using a regexp sub match callback.
-func (ws *LogStrategy) checkSubmatch(b []byte) error {
-	return ws.submatchCallback(ws.Log, ws.re.FindAllSubmatch(b, -1))
+// findAllIndex returns the locations of the successive matches of the log entry in the window.
+func (m *logMatcher) findAllIndex() [][]int {
+	if m.re != nil {
+		return m.re.FindAllIndex(m.window, -1)
+	}
+
+	log := []byte(m.ws.Log)
+	if len(log) == 0 {
+		return nil
+	}
+
+	var locs [][]int
+	for offset := 0; ; {
+		i := bytes.Index(m.window[offset:], log)
+		if i < 0 {
+			return locs
+		}
+		locs = append(locs, []int{offset + i, offset + i + len(log)})
+		offset += i + len(log)
+	}
 }
@@ -2,6 +2,7 @@ package wait_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -83,10 +84,10 @@ func TestWaitForLog(t *testing.T) {
 	t.Run("submatch/temporary-error", func(t *testing.T) {
 		target := newRunningTarget()
 		expect := target.EXPECT()
-		expect.Logs(anyContext).Return(readCloser(""), nil).Once()                 // No matches.
-		expect.Logs(anyContext).Return(readCloser("ip1m, ip2m"), nil).Once()       // Two matches.
-		expect.Logs(anyContext).Return(readCloser("ip1m, ip2m, ip3m"), nil).Once() // Three matches.
-		expect.Logs(anyContext).Return(readCloser("ip1m, ip2m, ip3m, ip4m"), nil)  // Four matches.
+		expect.StreamLogs(anyContext).Return(readCloser(""), nil).Once()                 // No matches.
+		expect.StreamLogs(anyContext).Return(readCloser("ip1m, ip2m"), nil).Once()       // Two matches.
+		expect.StreamLogs(anyContext).Return(readCloser("ip1m, ip2m, ip3m"), nil).Once() // Three matches.
+		expect.StreamLogs(anyContext).Return(readCloser("ip1m, ip2m, ip3m, ip4m"), nil)  // Four matches.
 
 		wg := wait.NewLogStrategy(`ip(\d)m`).WithTimeout(400 * time.Second).Submatch(func(pattern string, submatches [][][]byte) error {
 			switch len(submatches) {
@@ -184,7 +185,7 @@ func TestWaitShouldFailWithExactNumberOfOccurrences(t *testing.T) {
 
 func TestWaitForLogFailsDueToOOMKilledContainer(t *testing.T) {
 	target := &wait.MockStrategyTarget{
-		LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+		StreamLogsImpl: func(_ context.Context) (io.ReadCloser, error) {
 			return readCloser(""), nil
 		},
 		StateImpl: func(_ context.Context) (*container.State, error) {
@@ -213,7 +214,7 @@ func TestWaitForLogFailsDueToOOMKilledContainer(t *testing.T) {
 
 func TestWaitForLogFailsDueToExitedContainer(t *testing.T) {
 	target := &wait.MockStrategyTarget{
-		LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+		StreamLogsImpl: func(_ context.Context) (io.ReadCloser, error) {
 			return readCloser(""), nil
 		},
 		StateImpl: func(_ context.Context) (*container.State, error) {
@@ -243,7 +244,7 @@ func TestWaitForLogFailsDueToExitedContainer(t *testing.T) {
 
 func TestWaitForLogFailsDueToUnexpectedContainerStatus(t *testing.T) {
 	target := &wait.MockStrategyTarget{
-		LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+		StreamLogsImpl: func(_ context.Context) (io.ReadCloser, error) {
 			return readCloser(""), nil
 		},
 		StateImpl: func(_ context.Context) (*container.State, error) {
@@ -270,6 +271,174 @@ func TestWaitForLogFailsDueToUnexpectedContainerStatus(t *testing.T) {
 	})
 }
 
+func TestWaitForLogFollowsStream(t *testing.T) {
+	t.Run("incremental", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		target := newRunningTarget()
+		target.EXPECT().StreamLogs(anyContext).Return(pr, nil).Once()
+
+		go func() {
+			_, _ = io.WriteString(pw, "starting\n")
+			_, _ = io.WriteString(pw, "docker is ")
+			_, _ = io.WriteString(pw, "ready\n")
+			_, _ = io.WriteString(pw, "docker is ready\n")
+		}()
+
+		wg := wait.ForLog("docker is ready").WithOccurrence(2).WithTimeout(logTimeout)
+		err := wg.WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("stream-error", func(t *testing.T) {
+		streamErr := errors.New("stream error")
+		target := newRunningTarget()
+		target.EXPECT().StreamLogs(anyContext).Return(nil, streamErr).Once()
+
+		start := time.Now()
+		wg := wait.ForLog("docker").WithTimeout(time.Minute)
+		err := wg.WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, streamErr)
+		require.Less(t, time.Since(start), time.Minute)
+	})
+
+	t.Run("stream-ended/running", func(t *testing.T) {
+		target := newRunningTarget()
+		expect := target.EXPECT()
+		expect.StreamLogs(anyContext).Return(readCloser("kubernetes\n"), nil).Once()
+		expect.StreamLogs(anyContext).Return(readCloser("kubernetes\ndocker\n"), nil).Once()
+
+		wg := wait.ForLog("docker").WithTimeout(logTimeout).WithPollInterval(time.Millisecond)
+		err := wg.WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		target := newRunningTarget()
+		target.EXPECT().StreamLogs(anyContext).RunAndReturn(func(ctx context.Context) (io.ReadCloser, error) {
+			go func() {
+				_, _ = io.WriteString(pw, "kubernetes\n")
+				// the stream is closed once the context is done
+				<-ctx.Done()
+				pw.CloseWithError(ctx.Err())
+			}()
+			return pr, nil
+		}).Once()
+
+		wg := wait.ForLog("docker").WithTimeout(100 * time.Millisecond)
+		err := wg.WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, `"docker" matched 0 times, expected 1`)
+	})
+
+	t.Run("long-line", func(t *testing.T) {
+		target := wait.NopStrategyTarget{
+			ReaderCloser: readCloser(strings.Repeat("a", 256*1024) + "docker\n"),
+		}
+
+		wg := wait.ForLog("docker").WithTimeout(logTimeout)
+		err := wg.WaitUntilReady(context.Background(), &target)
+		require.NoError(t, err)
+	})
+}
+
+func TestWaitForLogMultiLine(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		target := wait.NopStrategyTarget{ReaderCloser: readCloser(loremIpsum)}
+
+		wg := wait.ForLog("Donec et mollis dolor.\nPraesent").WithTimeout(logTimeout)
+		require.NoError(t, wg.WaitUntilReady(context.Background(), &target))
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		target := wait.NopStrategyTarget{ReaderCloser: readCloser(loremIpsum)}
+
+		wg := wait.ForLog(`(?s)congue ligula.*mollis dolor`).AsRegexp().WithTimeout(logTimeout)
+		require.NoError(t, wg.WaitUntilReady(context.Background(), &target))
+	})
+
+	t.Run("regexp/anchors", func(t *testing.T) {
+		// without the multi-line flag, ^ and $ match the start and the end of the logs
+		target := wait.NopStrategyTarget{ReaderCloser: readCloser(loremIpsum)}
+		wg := wait.ForLog(`^Lorem ipsum(?s:.*)consectetur adipiscing elit\.$`).AsRegexp().WithTimeout(logTimeout)
+		require.NoError(t, wg.WaitUntilReady(context.Background(), &target))
+
+		target = wait.NopStrategyTarget{ReaderCloser: readCloser(loremIpsum)}
+		wg = wait.ForLog(`^Donec`).AsRegexp().WithTimeout(100 * time.Millisecond)
+		require.Error(t, wg.WaitUntilReady(context.Background(), &target))
+	})
+
+	t.Run("split-across-reads", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		target := newRunningTarget()
+		target.EXPECT().StreamLogs(anyContext).Return(pr, nil).Once()
+
+		go func() {
+			_, _ = io.WriteString(pw, "database system is ready\n")
+			_, _ = io.WriteString(pw, "to accept connections\n")
+		}()
+
+		wg := wait.ForLog("ready\nto accept").WithTimeout(logTimeout)
+		require.NoError(t, wg.WaitUntilReady(context.Background(), target))
+	})
+
+	t.Run("window-slides", func(t *testing.T) {
+		// the matches in the logs dropped from the window are still counted
+		chunk := "docker\n" + strings.Repeat("a", 600*1024) + "\n"
+		target := wait.NopStrategyTarget{ReaderCloser: readCloser(strings.Repeat(chunk, 4))}
+
+		wg := wait.ForLog("docker").WithOccurrence(4).WithTimeout(logTimeout)
+		require.NoError(t, wg.WaitUntilReady(context.Background(), &target))
+
+		running := newRunningTarget()
+		running.EXPECT().StreamLogs(anyContext).RunAndReturn(func(context.Context) (io.ReadCloser, error) {
+			return readCloser(strings.Repeat(chunk, 4)), nil
+		})
+		wg = wait.ForLog("dock.r").AsRegexp().WithOccurrence(5).WithTimeout(100 * time.Millisecond)
+		require.ErrorContains(t, wg.WaitUntilReady(context.Background(), running), "matched 4 times, expected 5")
+	})
+
+	t.Run("window-slides/submatch", func(t *testing.T) {
+		// only the sub matches of the logs kept in the window are passed
+		filler := strings.Repeat("a", 600*1024) + "\n"
+		target := wait.NopStrategyTarget{ReaderCloser: readCloser("ip1m\n" + filler + "ip2m\n" + filler + "ip3m\n" + filler)}
+
+		wg := wait.ForLog(`ip(\d)m`).WithTimeout(logTimeout).Submatch(func(pattern string, submatches [][][]byte) error {
+			if len(submatches) == 0 || string(submatches[len(submatches)-1][1]) != "3" {
+				return fmt.Errorf("%q did not match ip3m yet", pattern)
+			}
+			if string(submatches[0][1]) == "1" {
+				return wait.NewPermanentError(errors.New("the sub matches of the dropped logs are passed"))
+			}
+			return nil
+		})
+		require.NoError(t, wg.WaitUntilReady(context.Background(), &target))
+	})
+}
+
+func TestWaitForLogConcurrent(t *testing.T) {
+	// the same strategy can wait for multiple containers at once
+	wg := wait.ForLog("docker").WithOccurrence(2).WithTimeout(logTimeout)
+
+	errs := make(chan error, 8)
+	for range cap(errs) {
+		go func() {
+			target := wait.NopStrategyTarget{ReaderCloser: readCloser("docker\nkubernetes\ndocker\n")}
+			errs <- wg.WaitUntilReady(context.Background(), &target)
+		}()
+	}
+
+	for range cap(errs) {
+		require.NoError(t, <-errs)
+	}
+}
+
 // readCloser returns an io.ReadCloser that reads from s.
 func readCloser(s string) io.ReadCloser {
 	return io.NopCloser(strings.NewReader((s)))
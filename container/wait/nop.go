@@ -71,6 +71,10 @@ func (st *NopStrategyTarget) Logs(_ context.Context) (io.ReadCloser, error) {
 	return st.ReaderCloser, nil
 }
 
+func (st *NopStrategyTarget) StreamLogs(_ context.Context) (io.ReadCloser, error) {
+	return st.ReaderCloser, nil
+}
+
 func (st *NopStrategyTarget) Exec(_ context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
 	return 0, nil, nil
 }
@@ -504,6 +504,64 @@ func (_c *mockStrategyTarget_State_Call) RunAndReturn(run func(context.Context)
 	return _c
 }
 
+// StreamLogs provides a mock function with given fields: _a0
+func (_m *mockStrategyTarget) StreamLogs(_a0 context.Context) (io.ReadCloser, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamLogs")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (io.ReadCloser, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) io.ReadCloser); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockStrategyTarget_StreamLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamLogs'
+type mockStrategyTarget_StreamLogs_Call struct {
+	*mock.Call
+}
+
+// StreamLogs is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *mockStrategyTarget_Expecter) StreamLogs(_a0 interface{}) *mockStrategyTarget_StreamLogs_Call {
+	return &mockStrategyTarget_StreamLogs_Call{Call: _e.mock.On("StreamLogs", _a0)}
+}
+
+func (_c *mockStrategyTarget_StreamLogs_Call) Run(run func(_a0 context.Context)) *mockStrategyTarget_StreamLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *mockStrategyTarget_StreamLogs_Call) Return(_a0 io.ReadCloser, _a1 error) *mockStrategyTarget_StreamLogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockStrategyTarget_StreamLogs_Call) RunAndReturn(run func(context.Context) (io.ReadCloser, error)) *mockStrategyTarget_StreamLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockStrategyTarget creates a new instance of mockStrategyTarget. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockStrategyTarget(t interface {
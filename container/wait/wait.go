@@ -30,6 +30,9 @@ type StrategyTarget interface {
 	Inspect(context.Context) (dockerclient.ContainerInspectResult, error)
 	MappedPort(context.Context, network.Port) (network.Port, error)
 	Logs(context.Context) (io.ReadCloser, error)
+	// StreamLogs returns the logs of the target from the beginning, keeping the stream
+	// open to return new output until the target stops or the context is done.
+	StreamLogs(context.Context) (io.ReadCloser, error)
 	Exec(context.Context, []string, ...exec.ProcessOption) (int, io.Reader, error)
 	State(context.Context) (*container.State, error)
 	CopyFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error)
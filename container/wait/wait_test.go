@@ -21,6 +21,7 @@ type MockStrategyTarget struct {
 	PortsImpl             func(context.Context) (network.PortMap, error)
 	MappedPortImpl        func(context.Context, network.Port) (network.Port, error)
 	LogsImpl              func(context.Context) (io.ReadCloser, error)
+	StreamLogsImpl        func(context.Context) (io.ReadCloser, error)
 	ExecImpl              func(context.Context, []string, ...exec.ProcessOption) (int, io.Reader, error)
 	StateImpl             func(context.Context) (*container.State, error)
 	CopyFromContainerImpl func(context.Context, string) (io.ReadCloser, error)
@@ -43,6 +44,10 @@ func (st *MockStrategyTarget) Logs(ctx context.Context) (io.ReadCloser, error) {
 	return st.LogsImpl(ctx)
 }
 
+func (st *MockStrategyTarget) StreamLogs(ctx context.Context) (io.ReadCloser, error) {
+	return st.StreamLogsImpl(ctx)
+}
+
 func (st *MockStrategyTarget) Exec(ctx context.Context, cmd []string, options ...exec.ProcessOption) (int, io.Reader, error) {
 	return st.ExecImpl(ctx, cmd, options...)
 }
@@ -5,14 +5,15 @@ import (
 	"fmt"
 
 	"github.com/opencontainers/go-digest"
-
-	"github.com/docker/go-sdk/config"
 )
 
 // New creates a new context.
 //
 // If the context already exists, it returns an error.
 //
+// The TLS material passed with the [WithTLSCA], [WithTLSCert] and [WithTLSKey] options
+// is validated and written to the TLS store of the context.
+//
 // If the [AsCurrent] option is passed, it updates the Docker config
 // file, setting the current context to the new context.
 func New(name string, opts ...CreateContextOption) (*Context, error) {
@@ -35,6 +36,11 @@ func New(name string, opts ...CreateContextOption) (*Context, error) {
 		}
 	}
 
+	tlsData, err := defaultOptions.tlsData()
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
 	ctx := &Context{
 		Name:        name,
 		encodedName: digest.FromString(name).Encoded(),
@@ -42,12 +48,13 @@ func New(name string, opts ...CreateContextOption) (*Context, error) {
 			Description:      defaultOptions.description,
 			additionalFields: defaultOptions.additionalFields,
 		},
-		Endpoints: map[string]*endpoint{
-			"docker": {
+		Endpoints: map[string]*Endpoint{
+			DockerEndpoint: {
 				Host:          defaultOptions.host,
 				SkipTLSVerify: defaultOptions.skipTLSVerify,
 			},
 		},
+		tls: tlsData,
 	}
 
 	metaRoot, err := metaRoot()
@@ -61,17 +68,21 @@ func New(name string, opts ...CreateContextOption) (*Context, error) {
 		return nil, fmt.Errorf("add context: %w", err)
 	}
 
-	// set the context as the current context if the option is set
-	if defaultOptions.current {
-		cfg, err := config.Load()
-		if err != nil {
-			return nil, fmt.Errorf("load config: %w", err)
-		}
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("tls root: %w", err), s.delete(ctx.encodedName))
+	}
 
-		cfg.CurrentContext = ctx.Name
+	ts := &tlsStore{root: tlsRoot}
 
-		if err := cfg.Save(); err != nil {
-			return nil, fmt.Errorf("save config: %w", err)
+	if err := ts.save(ctx.encodedName, DockerEndpoint, ctx.tls); err != nil {
+		return nil, errors.Join(fmt.Errorf("save tls: %w", err), s.delete(ctx.encodedName), ts.delete(ctx.encodedName))
+	}
+
+	// set the context as the current context if the option is set
+	if defaultOptions.current {
+		if err := setCurrent(ctx.Name); err != nil {
+			return nil, err
 		}
 
 		ctx.isCurrent = true
@@ -3,11 +3,9 @@ package context
 import (
 	"errors"
 	"fmt"
-
-	"github.com/docker/go-sdk/config"
 )
 
-// Delete deletes a context. The context must exist: it must have been created with [New]
+// Delete deletes a context, with its TLS material. The context must exist: it must have been created with [New]
 // or inspected with [Inspect].
 // If the context is the default context, the current context will be reset to the default context.
 func (ctx *Context) Delete() error {
@@ -27,17 +25,21 @@ func (ctx *Context) Delete() error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
-	if ctx.isCurrent {
-		// reset the current context to the default context
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("load config: %w", err)
-		}
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return fmt.Errorf("tls root: %w", err)
+	}
+
+	ts := &tlsStore{root: tlsRoot}
 
-		cfg.CurrentContext = DefaultContextName
+	if err := ts.delete(ctx.encodedName); err != nil {
+		return fmt.Errorf("delete tls: %w", err)
+	}
 
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("save config: %w", err)
+	if ctx.isCurrent {
+		// reset the current context to the default context
+		if err := setCurrent(DefaultContextName); err != nil {
+			return err
 		}
 	}
 
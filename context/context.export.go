@@ -0,0 +1,201 @@
+package context
+
+// The format of the exported contexts has been extracted from https://github.com/docker/cli,
+// more especifically from https://github.com/docker/cli/blob/master/cli/context/store/store.go
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxImportFileSize is the maximum size of a file of an imported context.
+const maxImportFileSize = 10 << 20
+
+// Export writes the given context to w, as a tar archive compatible with `docker context export`
+// and `docker context import`: its metadata, in the meta.json file, and the TLS material of all
+// its endpoints, in the tls/<endpoint> directories.
+func Export(name string, w io.Writer) error {
+	ctx, err := Inspect(name)
+	if err != nil {
+		return fmt.Errorf("inspect context: %w", err)
+	}
+
+	meta, err := json.Marshal(&ctx)
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return fmt.Errorf("tls root: %w", err)
+	}
+
+	ts := &tlsStore{root: tlsRoot}
+
+	tlsFiles, err := ts.listFiles(ctx.encodedName)
+	if err != nil {
+		return fmt.Errorf("list tls files: %w", err)
+	}
+
+	tw := tar.NewWriter(w)
+
+	if err := writeTarFile(tw, metaFile, meta, 0o644); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: tlsDir, Mode: 0o700, Typeflag: tar.TypeDir}); err != nil {
+		return fmt.Errorf("write %s: %w", tlsDir, err)
+	}
+
+	for endpointName, files := range tlsFiles {
+		dir := path.Join(tlsDir, endpointName)
+		if err := tw.WriteHeader(&tar.Header{Name: dir, Mode: 0o700, Typeflag: tar.TypeDir}); err != nil {
+			return fmt.Errorf("write %s: %w", dir, err)
+		}
+
+		for _, file := range files {
+			data, err := ts.readFile(ctx.encodedName, endpointName, file)
+			if err != nil {
+				return fmt.Errorf("read tls file: %w", err)
+			}
+
+			if err := writeTarFile(tw, path.Join(dir, file), data, 0o600); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+
+	return nil
+}
+
+// writeTarFile writes a regular file to the tar archive.
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode int64) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Import creates a new context with the given name from r, a tar archive written by [Export]
+// or by `docker context export`. The name of the context in the archive is replaced by the
+// given one.
+//
+// If the context already exists, it returns an error.
+func Import(name string, r io.Reader) (*Context, error) {
+	switch name {
+	case "":
+		return nil, errors.New("name is required")
+	case DefaultContextName:
+		return nil, errors.New("name cannot be 'default'")
+	}
+
+	if _, err := Inspect(name); err == nil {
+		return nil, fmt.Errorf("context %s already exists", name)
+	}
+
+	var meta []byte
+	tlsFiles := make(map[[2]string][]byte)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar: %w", err)
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxImportFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		if len(data) > maxImportFileSize {
+			return nil, fmt.Errorf("file %s is too large", hdr.Name)
+		}
+
+		switch {
+		case hdr.Name == metaFile:
+			meta = data
+		case strings.HasPrefix(hdr.Name, tlsDir+"/"):
+			parts := strings.Split(strings.TrimPrefix(hdr.Name, tlsDir+"/"), "/")
+			if len(parts) != 2 || !isValidPathElement(parts[0]) || !isValidPathElement(parts[1]) {
+				return nil, fmt.Errorf("invalid tls file: %s", hdr.Name)
+			}
+			tlsFiles[[2]string{parts[0], parts[1]}] = data
+		default:
+			return nil, fmt.Errorf("unexpected file: %s", hdr.Name)
+		}
+	}
+
+	if meta == nil {
+		return nil, fmt.Errorf("missing %s", metaFile)
+	}
+
+	var ctx Context
+	if err := json.Unmarshal(meta, &ctx); err != nil {
+		return nil, fmt.Errorf("parse metadata: %w", err)
+	}
+
+	ep, ok := ctx.Endpoints[DockerEndpoint]
+	if !ok || ep == nil || ep.Host == "" {
+		return nil, ErrDockerHostNotSet
+	}
+
+	ctx.Name = name
+	ctx.encodedName = ""
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return nil, fmt.Errorf("meta root: %w", err)
+	}
+
+	s := &store{root: metaRoot}
+
+	if err := s.add(&ctx); err != nil {
+		return nil, fmt.Errorf("add context: %w", err)
+	}
+
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("tls root: %w", err), s.delete(ctx.encodedName))
+	}
+
+	ts := &tlsStore{root: tlsRoot}
+
+	for file, data := range tlsFiles {
+		if err := ts.writeFile(ctx.encodedName, file[0], file[1], data); err != nil {
+			return nil, errors.Join(fmt.Errorf("save tls: %w", err), s.delete(ctx.encodedName), ts.delete(ctx.encodedName))
+		}
+	}
+
+	imported, err := Inspect(name)
+	if err != nil {
+		return nil, fmt.Errorf("inspect context: %w", err)
+	}
+
+	return &imported, nil
+}
+
+// isValidPathElement returns true if the given name is a single, non-special, path element.
+func isValidPathElement(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
@@ -0,0 +1,129 @@
+package context_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/context"
+)
+
+func TestExportImport(t *testing.T) {
+	ca, cert, key := newTestCertificates(t)
+
+	t.Run("round-trip", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.New("remote", context.WithHost("tcp://127.0.0.1:2376"),
+			context.WithDescription("remote daemon"),
+			context.WithAdditionalFields(map[string]any{"owner": "platform"}),
+			context.WithTLSCA(ca), context.WithTLSCert(cert), context.WithTLSKey(key))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, context.Export("remote", &buf))
+
+		// the archive has the layout of the docker CLI
+		files := readTar(t, buf.Bytes())
+		require.Equal(t, []string{"meta.json", "tls/", "tls/docker/", "tls/docker/ca.pem", "tls/docker/cert.pem", "tls/docker/key.pem"}, sortedKeys(files))
+		require.Equal(t, key, files["tls/docker/key.pem"])
+
+		var meta map[string]any
+		require.NoError(t, json.Unmarshal(files["meta.json"], &meta))
+		require.Equal(t, "remote", meta["Name"])
+
+		// import it on a fresh machine, with another name
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		imported, err := context.Import("imported", bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, "imported", imported.Name)
+		require.Equal(t, "tcp://127.0.0.1:2376", imported.Endpoints["docker"].Host)
+		require.Equal(t, "remote daemon", imported.Metadata.Description)
+		require.Equal(t, map[string]any{"owner": "platform"}, imported.Metadata.Fields())
+		require.Equal(t, &context.TLSData{CA: ca, Cert: cert, Key: key}, imported.TLS())
+
+		_, err = context.Import("imported", bytes.NewReader(buf.Bytes()))
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("error/not-found", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		err := context.Export("context-not-found", io.Discard)
+		require.ErrorIs(t, err, context.ErrDockerContextNotFound)
+	})
+
+	t.Run("error/invalid-archive", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		tests := map[string]map[string]string{
+			"missing-meta":     {"tls/docker/ca.pem": string(ca)},
+			"unexpected-file":  {"meta.json": `{"Name":"x","Endpoints":{"docker":{"Host":"tcp://127.0.0.1:2375"}}}`, "foo": "bar"},
+			"path-traversal":   {"meta.json": `{"Name":"x","Endpoints":{"docker":{"Host":"tcp://127.0.0.1:2375"}}}`, "tls/../../key.pem": "key"},
+			"missing-host":     {"meta.json": `{"Name":"x","Endpoints":{"docker":{}}}`},
+			"invalid-metadata": {"meta.json": `not json`},
+		}
+
+		for name, files := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := context.Import("imported", bytes.NewReader(writeTar(t, files)))
+				require.Error(t, err)
+
+				_, err = context.Inspect("imported")
+				require.ErrorIs(t, err, context.ErrDockerContextNotFound)
+			})
+		}
+	})
+}
+
+// readTar returns the files of the tar archive by name, with nil content for the directories.
+func readTar(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		if hdr.Typeflag == tar.TypeDir {
+			files[hdr.Name+"/"] = nil
+			continue
+		}
+		files[hdr.Name] = content
+	}
+}
+
+// writeTar returns a tar archive with the given files.
+func writeTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+// sortedKeys returns the sorted names of the files.
+func sortedKeys(files map[string][]byte) []string {
+	return slices.Sorted(maps.Keys(files))
+}
@@ -50,6 +50,9 @@ var (
 
 	// TCPSchema is the schema to use for TCP connections.
 	TCPSchema = "tcp://"
+
+	// SSHSchema is the schema to use for connections to a remote Docker daemon through SSH.
+	SSHSchema = "ssh://"
 )
 
 // DockerHostFromContext returns the Docker host from the given context.
@@ -60,12 +63,26 @@ func DockerHostFromContext(ctxName string) (string, error) {
 	}
 
 	// Inspect already validates that the docker endpoint is set
-	return ctx.Endpoints["docker"].Host, nil
+	return ctx.Endpoints[DockerEndpoint].Host, nil
 }
 
-// Inspect returns the given context.
+// Inspect returns the given context, with the TLS material of its docker endpoint, if any.
 // It returns an error if the context is not found or if the docker endpoint is not set.
 func Inspect(ctxName string) (Context, error) {
+	return loadContext(ctxName, true)
+}
+
+// Load returns the given context, with the TLS material of its docker endpoint, if any.
+// Unlike [Inspect], it doesn't require the context to have a docker endpoint, e.g. to read
+// a context with only a kubernetes endpoint, or the endpoint of a plugin, with [Context.Endpoint].
+// It returns an error if the context is not found.
+func Load(ctxName string) (Context, error) {
+	return loadContext(ctxName, false)
+}
+
+// loadContext returns the given context from the store, with the TLS material of its docker endpoint,
+// requiring its docker endpoint to be set if requireDocker is true.
+func loadContext(ctxName string, requireDocker bool) (Context, error) {
 	metaRoot, err := metaRoot()
 	if err != nil {
 		return Context{}, fmt.Errorf("meta root: %w", err)
@@ -73,7 +90,29 @@ func Inspect(ctxName string) (Context, error) {
 
 	s := &store{root: metaRoot}
 
-	return s.inspect(ctxName)
+	var ctx Context
+	if requireDocker {
+		ctx, err = s.inspect(ctxName)
+	} else {
+		ctx, err = s.get(ctxName)
+	}
+	if err != nil {
+		return Context{}, err
+	}
+
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return Context{}, fmt.Errorf("tls root: %w", err)
+	}
+
+	ts := &tlsStore{root: tlsRoot}
+
+	ctx.tls, err = ts.load(ctx.encodedName, DockerEndpoint)
+	if err != nil {
+		return Context{}, fmt.Errorf("load tls: %w", err)
+	}
+
+	return ctx, nil
 }
 
 // List returns the list of contexts available in the Docker configuration.
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Update updates an existing context with the given options, which are applied on top
+// of its current definition: e.g. [WithHost] replaces the host of its docker endpoint,
+// keeping its description and TLS material.
+//
+// The TLS material passed with the [WithTLSCA], [WithTLSCert] and [WithTLSKey] options
+// replaces the one in the TLS store of the context.
+//
+// If the [AsCurrent] option is passed, it updates the Docker config
+// file, setting the current context to the updated context.
+func Update(name string, opts ...CreateContextOption) (*Context, error) {
+	if name == DefaultContextName {
+		return nil, errors.New("cannot update the default context")
+	}
+
+	ctx, err := Inspect(name)
+	if err != nil {
+		return nil, fmt.Errorf("inspect context: %w", err)
+	}
+
+	if ctx.Metadata == nil {
+		ctx.Metadata = &Metadata{}
+	}
+
+	// Inspect already validates that the docker endpoint is set
+	ep := ctx.Endpoints[DockerEndpoint]
+
+	options := &contextOptions{
+		host:             ep.Host,
+		description:      ctx.Metadata.Description,
+		additionalFields: ctx.Metadata.Fields(),
+		skipTLSVerify:    ep.SkipTLSVerify,
+	}
+	if ctx.tls != nil {
+		options.tls = *ctx.tls
+	}
+
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	tlsData, err := options.tlsData()
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
+	ctx.Metadata.Description = options.description
+	ctx.Metadata.additionalFields = options.additionalFields
+	ep.Host = options.host
+	ep.SkipTLSVerify = options.skipTLSVerify
+	ctx.tls = tlsData
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return nil, fmt.Errorf("meta root: %w", err)
+	}
+
+	s := &store{root: metaRoot}
+
+	if err := s.save(&ctx); err != nil {
+		return nil, fmt.Errorf("save context: %w", err)
+	}
+
+	tlsRoot, err := tlsRoot()
+	if err != nil {
+		return nil, fmt.Errorf("tls root: %w", err)
+	}
+
+	ts := &tlsStore{root: tlsRoot}
+
+	if err := ts.deleteEndpoint(ctx.encodedName, DockerEndpoint); err != nil {
+		return nil, fmt.Errorf("delete tls: %w", err)
+	}
+
+	if err := ts.save(ctx.encodedName, DockerEndpoint, ctx.tls); err != nil {
+		return nil, fmt.Errorf("save tls: %w", err)
+	}
+
+	if options.current {
+		if err := setCurrent(ctx.Name); err != nil {
+			return nil, err
+		}
+
+		ctx.isCurrent = true
+	}
+
+	return &ctx, nil
+}
@@ -0,0 +1,80 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/context"
+)
+
+func TestUpdate(t *testing.T) {
+	ca, cert, key := newTestCertificates(t)
+
+	t.Run("success", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.New("test", context.WithHost("tcp://127.0.0.1:1234"),
+			context.WithDescription("test description"), context.WithTLSCA(ca))
+		require.NoError(t, err)
+
+		updated, err := context.Update("test", context.WithHost("tcp://127.0.0.1:2376"),
+			context.WithTLSCert(cert), context.WithTLSKey(key))
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:2376", updated.Endpoints["docker"].Host)
+
+		inspected, err := context.Inspect("test")
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:2376", inspected.Endpoints["docker"].Host)
+		// the fields not updated are kept
+		require.Equal(t, "test description", inspected.Metadata.Description)
+		require.Equal(t, &context.TLSData{CA: ca, Cert: cert, Key: key}, inspected.TLS())
+
+		list, err := context.List()
+		require.NoError(t, err)
+		require.Len(t, list, 6)
+	})
+
+	t.Run("as-current", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+		t.Setenv("DOCKER_HOST", "")
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		_, err := context.New("test", context.WithHost("tcp://127.0.0.1:1234"))
+		require.NoError(t, err)
+
+		_, err = context.Update("test", context.AsCurrent())
+		require.NoError(t, err)
+
+		current, err := context.Current()
+		require.NoError(t, err)
+		require.Equal(t, "test", current)
+	})
+
+	t.Run("error/invalid-tls", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.New("test", context.WithHost("tcp://127.0.0.1:1234"))
+		require.NoError(t, err)
+
+		_, err = context.Update("test", context.WithTLSKey(key))
+		require.ErrorContains(t, err, "client certificate and key must be set together")
+
+		// the context is unchanged
+		inspected, err := context.Inspect("test")
+		require.NoError(t, err)
+		require.Nil(t, inspected.TLS())
+	})
+
+	t.Run("error/not-found", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.Update("context-not-found", context.WithHost("tcp://127.0.0.1:1234"))
+		require.ErrorIs(t, err, context.ErrDockerContextNotFound)
+	})
+
+	t.Run("error/default", func(t *testing.T) {
+		_, err := context.Update(context.DefaultContextName)
+		require.Error(t, err)
+	})
+}
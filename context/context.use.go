@@ -0,0 +1,35 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/docker/go-sdk/config"
+)
+
+// Use sets the given context as the current context, saving it to the Docker config file,
+// like `docker context use` does. The context must exist, unless it's the default context.
+func Use(name string) error {
+	if name != DefaultContextName {
+		if _, err := Inspect(name); err != nil {
+			return fmt.Errorf("inspect context: %w", err)
+		}
+	}
+
+	return setCurrent(name)
+}
+
+// setCurrent sets the current context in the Docker config file.
+func setCurrent(name string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	cfg.CurrentContext = name
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/context"
+)
+
+func TestUse(t *testing.T) {
+	context.SetupTestDockerContexts(t, 1, 3)
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, context.Use("context2"))
+
+		current, err := context.Current()
+		require.NoError(t, err)
+		require.Equal(t, "context2", current)
+
+		ctx, err := context.Inspect("context2")
+		require.NoError(t, err)
+		require.NoError(t, ctx.Delete())
+
+		// the deleted context is no longer the current one
+		current, err = context.Current()
+		require.NoError(t, err)
+		require.Equal(t, context.DefaultContextName, current)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		require.NoError(t, context.Use("context1"))
+		require.NoError(t, context.Use(context.DefaultContextName))
+
+		current, err := context.Current()
+		require.NoError(t, err)
+		require.Equal(t, context.DefaultContextName, current)
+	})
+
+	t.Run("error/not-found", func(t *testing.T) {
+		require.NoError(t, context.Use("context1"))
+
+		err := context.Use("context-not-found")
+		require.ErrorIs(t, err, context.ErrDockerContextNotFound)
+
+		// the current context is unchanged
+		current, err := context.Current()
+		require.NoError(t, err)
+		require.Equal(t, "context1", current)
+	})
+}
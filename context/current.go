@@ -35,45 +35,24 @@ func Current() (string, error) {
 	return DefaultContextName, nil
 }
 
-// CurrentDockerHost returns the Docker host from the current Docker context.
-// For that, it traverses the directory structure of the Docker configuration directory,
-// looking for the current context and its Docker endpoint.
+// CurrentDockerHost returns the Docker host discovered from the [DefaultHostSources], by precedence:
+// the DOCKER_HOST environment variable, the docker endpoint of the current context, unless it's
+// the default context, the socket of Rootless Docker, or Podman, in the XDG_RUNTIME_DIR directory,
+// the well-known sockets of Docker Desktop, of the Docker daemon, of Colima, of Rancher Desktop
+// and of Podman, and finally the [DefaultDockerHost].
 //
-// If the Rootless Docker socket is found, using the XDG_RUNTIME_DIR environment variable,
-// it returns the path to the socket.
-//
-// If the current context is the default context, it returns the value of the
-// DOCKER_HOST environment variable.
+// Use [DiscoverDockerHost] to know which source the Docker host comes from, and [Diagnostics]
+// to know why the other sources were rejected.
 //
 // It validates that the Docker host is a valid URL and that the schema is
-// either unix, npipe (on Windows) or tcp.
+// either unix, npipe (on Windows), tcp or ssh.
 func CurrentDockerHost() (string, error) {
-	rootlessSocketPath, err := rootlessSocketPathFromEnv()
-	if err == nil {
-		return parseURL(rootlessSocketPath)
-	}
-
-	current, err := Current()
-	if err != nil {
-		return "", fmt.Errorf("current context: %w", err)
-	}
-
-	if current == DefaultContextName {
-		dockerHost := os.Getenv(EnvOverrideHost)
-		if dockerHost != "" {
-			return parseURL(dockerHost)
-		}
-
-		return parseURL(DefaultDockerHost)
-	}
-
-	ctx, err := Inspect(current)
+	discovery, err := DiscoverDockerHost()
 	if err != nil {
-		return "", fmt.Errorf("inspect context: %w", err)
+		return "", err
 	}
 
-	// Inspect already validates that the docker endpoint is set
-	return parseURL(ctx.Endpoints["docker"].Host)
+	return discovery.Host, nil
 }
 
 // getContextFromEnv returns the context name from the environment variables.
@@ -102,6 +81,10 @@ func parseURL(s string) (string, error) {
 	case TCPSchema:
 		// return the original URL, as it is a valid TCP URL
 		return s, nil
+	case SSHSchema:
+		// return the original URL, as it is a valid SSH URL,
+		// which the client dials through an SSH connection
+		return s, nil
 	default:
 		return "", ErrInvalidSchema
 	}
@@ -19,9 +19,15 @@ func TestParseURL(t *testing.T) {
 		require.Equal(t, "tcp://localhost:2375", path)
 	})
 
+	t.Run("success/ssh", func(t *testing.T) {
+		path, err := parseURL("ssh://user@buildbox:2222")
+		require.NoError(t, err)
+		require.Equal(t, "ssh://user@buildbox:2222", path)
+	})
+
 	t.Run("error/invalid-schema", func(t *testing.T) {
 		_, err := parseURL("http://localhost:2375")
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrInvalidSchema)
 	})
 
 	t.Run("error/invalid-url", func(t *testing.T) {
@@ -0,0 +1,269 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/go-sdk/config"
+)
+
+// ErrHostNotFound is returned by a [HostSource] which has no docker host, e.g. because the
+// environment variable is not set or the socket does not exist: the discovery continues
+// with the next source of the chain.
+var ErrHostNotFound = errors.New("docker host not found")
+
+// The names of the sources of the default discovery chain, by precedence.
+const (
+	HostSourceEnv            = "env"
+	HostSourceContext        = "context"
+	HostSourceRootless       = "rootless"
+	HostSourceDockerDesktop  = "docker-desktop"
+	HostSourceVarRun         = "var-run"
+	HostSourceColima         = "colima"
+	HostSourceRancherDesktop = "rancher-desktop"
+	HostSourcePodman         = "podman"
+	HostSourceDefault        = "default"
+)
+
+// HostSource is a source of the docker host, in the discovery chain of [CurrentDockerHost].
+type HostSource struct {
+	// Name identifies the source in the discovery reports.
+	Name string
+
+	// Lookup returns the docker host from the source. It returns an error wrapping
+	// [ErrHostNotFound], with the reason why, if the source has no docker host.
+	// Any other error stops the discovery.
+	Lookup func() (string, error)
+}
+
+// HostCandidate is the result of the lookup of a source of the discovery chain.
+type HostCandidate struct {
+	// Source is the name of the source.
+	Source string
+
+	// Host is the docker host found in the source, if any.
+	Host string
+
+	// Err is the reason why the source has no docker host, or the error of its lookup.
+	Err error
+}
+
+// HostDiscovery is the report of the discovery of the docker host.
+type HostDiscovery struct {
+	// Host is the docker host discovered.
+	Host string
+
+	// Source is the name of the source of the docker host.
+	Source string
+
+	// Candidates are the results of the lookups of the sources, in order of precedence.
+	Candidates []HostCandidate
+}
+
+// String returns the report of the discovery, with one line per source: the selected one,
+// the ones rejected with the reason why, and the ones found with a lower precedence.
+func (d *HostDiscovery) String() string {
+	var sb strings.Builder
+
+	switch failed := d.failed(); {
+	case d.Source != "":
+		fmt.Fprintf(&sb, "docker host: %s (from %s)\n", d.Host, d.Source)
+	case failed != nil:
+		fmt.Fprintf(&sb, "docker host: error from %s: %v\n", failed.Source, failed.Err)
+	default:
+		sb.WriteString("docker host: not found\n")
+	}
+
+	for _, c := range d.Candidates {
+		switch {
+		case c.Source == d.Source && c.Err == nil:
+			fmt.Fprintf(&sb, "  %s: selected: %s\n", c.Source, c.Host)
+		case c.Err == nil:
+			fmt.Fprintf(&sb, "  %s: found, lower precedence: %s\n", c.Source, c.Host)
+		case errors.Is(c.Err, ErrHostNotFound):
+			fmt.Fprintf(&sb, "  %s: rejected: %v\n", c.Source, c.Err)
+		default:
+			fmt.Fprintf(&sb, "  %s: error: %v\n", c.Source, c.Err)
+		}
+	}
+
+	return sb.String()
+}
+
+// failed returns the candidate whose lookup failed, stopping the discovery, if any.
+func (d *HostDiscovery) failed() *HostCandidate {
+	for i, c := range d.Candidates {
+		if c.Err != nil && !errors.Is(c.Err, ErrHostNotFound) {
+			return &d.Candidates[i]
+		}
+	}
+	return nil
+}
+
+// DefaultHostSources returns the default discovery chain of the docker host, by precedence:
+//   - env: the DOCKER_HOST environment variable.
+//   - context: the docker endpoint of the current context, unless it's the default context.
+//   - rootless: the socket of rootless Docker, or of rootless Podman, in $XDG_RUNTIME_DIR.
+//   - docker-desktop: the socket of Docker Desktop, in ~/.docker/run.
+//   - var-run: the /var/run/docker.sock socket.
+//   - colima, rancher-desktop and podman: the sockets of these Docker-compatible runtimes.
+//   - default: the [DefaultDockerHost], which is always found.
+//
+// The socket sources only apply on Linux and macOS.
+func DefaultHostSources() []HostSource {
+	sources := []HostSource{
+		{Name: HostSourceEnv, Lookup: envHost},
+		{Name: HostSourceContext, Lookup: contextHost},
+		{Name: HostSourceRootless, Lookup: rootlessHost},
+	}
+
+	sources = append(sources, socketSources()...)
+
+	return append(sources, HostSource{Name: HostSourceDefault, Lookup: func() (string, error) {
+		return DefaultDockerHost, nil
+	}})
+}
+
+// DiscoverDockerHost returns the docker host from the first source of the given discovery
+// chain having one, or from the [DefaultHostSources] if no source is given, e.g. to add
+// or reorder sources:
+//
+//	sources := append([]context.HostSource{{Name: "ci", Lookup: ciDockerHost}}, context.DefaultHostSources()...)
+//	discovery, err := context.DiscoverDockerHost(sources...)
+//
+// The sources after the selected one are not looked up. It validates that the Docker host
+// is a valid URL and that the schema is either unix, npipe (on Windows), tcp or ssh.
+func DiscoverDockerHost(sources ...HostSource) (*HostDiscovery, error) {
+	return discover(sources, false)
+}
+
+// Diagnostics returns the report of the discovery of the docker host from the
+// [DefaultHostSources], for troubleshooting, e.g. to be printed by support scripts.
+// Unlike [DiscoverDockerHost], all the sources are looked up, so that the report
+// also lists the docker hosts found with a lower precedence, and the errors do not
+// stop the discovery.
+func Diagnostics() string {
+	discovery, _ := discover(DefaultHostSources(), true)
+	return discovery.String()
+}
+
+// discover looks up the given sources, by precedence, until a docker host is found,
+// or all of them if lookupAll is true.
+func discover(sources []HostSource, lookupAll bool) (*HostDiscovery, error) {
+	if len(sources) == 0 {
+		sources = DefaultHostSources()
+	}
+
+	discovery := &HostDiscovery{}
+	var discoveryErr error
+
+	for _, source := range sources {
+		if discovery.Source != "" && !lookupAll {
+			break
+		}
+
+		host, err := source.Lookup()
+		if err == nil {
+			if _, parseErr := parseURL(host); parseErr != nil {
+				host, err = "", fmt.Errorf("parse docker host %q: %w", host, parseErr)
+			}
+		}
+
+		discovery.Candidates = append(discovery.Candidates, HostCandidate{Source: source.Name, Host: host, Err: err})
+
+		switch {
+		case err == nil:
+			if discovery.Source == "" && discoveryErr == nil {
+				discovery.Host, discovery.Source = host, source.Name
+			}
+		case errors.Is(err, ErrHostNotFound):
+		default:
+			if discovery.Source == "" && discoveryErr == nil {
+				discoveryErr = fmt.Errorf("%s: %w", source.Name, err)
+			}
+			if !lookupAll {
+				return discovery, discoveryErr
+			}
+		}
+	}
+
+	if discoveryErr != nil {
+		return discovery, discoveryErr
+	}
+
+	if discovery.Source == "" {
+		return discovery, ErrHostNotFound
+	}
+
+	return discovery, nil
+}
+
+// envHost returns the docker host from the DOCKER_HOST environment variable.
+func envHost() (string, error) {
+	if host := os.Getenv(EnvOverrideHost); host != "" {
+		return host, nil
+	}
+
+	return "", fmt.Errorf("%w: %s is not set", ErrHostNotFound, EnvOverrideHost)
+}
+
+// contextHost returns the docker host from the docker endpoint of the current context.
+func contextHost() (string, error) {
+	current, err := Current()
+	if err != nil {
+		// without a Docker config file, there is no current context
+		if _, pathErr := config.Filepath(); pathErr != nil {
+			return "", fmt.Errorf("%w: %w", ErrHostNotFound, pathErr)
+		}
+		return "", fmt.Errorf("current context: %w", err)
+	}
+
+	if current == DefaultContextName {
+		return "", fmt.Errorf("%w: the current context is the default context", ErrHostNotFound)
+	}
+
+	ctx, err := Inspect(current)
+	if err != nil {
+		return "", fmt.Errorf("inspect context: %w", err)
+	}
+
+	// Inspect already validates that the docker endpoint is set
+	return ctx.Endpoints[DockerEndpoint].Host, nil
+}
+
+// rootlessHost returns the docker host of the rootless Docker, or Podman, socket in $XDG_RUNTIME_DIR.
+func rootlessHost() (string, error) {
+	host, err := rootlessSocketPathFromEnv()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrHostNotFound, err)
+	}
+
+	return host, nil
+}
+
+// socketSource returns a source for the first existing socket of the given paths,
+// where a leading "~/" stands for the home directory of the user.
+func socketSource(name string, paths ...string) HostSource {
+	return HostSource{Name: name, Lookup: func() (string, error) {
+		candidates := make([]string, 0, len(paths))
+		for _, p := range paths {
+			if rel, ok := strings.CutPrefix(p, "~/"); ok {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return "", fmt.Errorf("%w: home dir: %w", ErrHostNotFound, err)
+				}
+				p = filepath.Join(home, rel)
+			}
+
+			if fileExists(p) {
+				return DefaultSchema + p, nil
+			}
+			candidates = append(candidates, p)
+		}
+
+		return "", fmt.Errorf("%w: %s not found", ErrHostNotFound, strings.Join(candidates, ", "))
+	}}
+}
@@ -0,0 +1,171 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestSocket creates a synthetic socket file at the given path.
+func writeTestSocket(t *testing.T, path string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("synthetic socket"), 0o755))
+}
+
+// testHostSource returns a source with a fixed lookup result.
+func testHostSource(name, host string, err error) HostSource {
+	return HostSource{Name: name, Lookup: func() (string, error) {
+		return host, err
+	}}
+}
+
+func TestDiscoverDockerHost(t *testing.T) {
+	t.Run("env", func(t *testing.T) {
+		SetupTestDockerContexts(t, 1, 3) // current context is context1
+		t.Setenv(EnvOverrideHost, "tcp://127.0.0.1:123")
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:123", discovery.Host)
+		require.Equal(t, HostSourceEnv, discovery.Source)
+		require.Len(t, discovery.Candidates, 1)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		SetupTestDockerContexts(t, 1, 3) // current context is context1
+		t.Setenv(EnvOverrideHost, "")
+
+		// the current context takes precedence over the rootless socket
+		xdgDir := t.TempDir()
+		t.Setenv("XDG_RUNTIME_DIR", xdgDir)
+		writeTestSocket(t, filepath.Join(xdgDir, "docker.sock"))
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:1", discovery.Host)
+		require.Equal(t, HostSourceContext, discovery.Source)
+		require.Len(t, discovery.Candidates, 2)
+		require.ErrorIs(t, discovery.Candidates[0].Err, ErrHostNotFound)
+	})
+
+	t.Run("context/no-host", func(t *testing.T) {
+		SetupTestDockerContexts(t, 4, 3) // current context is context4, without host
+		t.Setenv(EnvOverrideHost, "")
+		t.Setenv(EnvOverrideContext, "context4")
+
+		_, err := DiscoverDockerHost()
+		require.ErrorIs(t, err, ErrDockerHostNotSet)
+	})
+
+	t.Run("rootless", func(t *testing.T) {
+		SetupTestDockerContexts(t, 1, 3)
+		t.Setenv(EnvOverrideHost, "")
+		t.Setenv(EnvOverrideContext, DefaultContextName)
+
+		xdgDir := t.TempDir()
+		t.Setenv("XDG_RUNTIME_DIR", xdgDir)
+		writeTestSocket(t, filepath.Join(xdgDir, "docker.sock"))
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(t, err)
+		require.Equal(t, DefaultSchema+filepath.Join(xdgDir, "docker.sock"), discovery.Host)
+		require.Equal(t, HostSourceRootless, discovery.Source)
+	})
+
+	t.Run("docker-desktop", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the socket sources only apply on Linux and macOS")
+		}
+
+		SetupTestDockerContexts(t, 1, 3)
+		t.Setenv(EnvOverrideHost, "")
+		t.Setenv(EnvOverrideContext, DefaultContextName)
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		writeTestSocket(t, filepath.Join(home, ".docker", "run", "docker.sock"))
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(t, err)
+		require.Equal(t, DefaultSchema+filepath.Join(home, ".docker", "run", "docker.sock"), discovery.Host)
+		require.Equal(t, HostSourceDockerDesktop, discovery.Source)
+	})
+
+	t.Run("custom-chain", func(t *testing.T) {
+		discovery, err := DiscoverDockerHost(
+			testHostSource("first", "", ErrHostNotFound),
+			testHostSource("second", "tcp://127.0.0.1:2", nil),
+			testHostSource("third", "tcp://127.0.0.1:3", nil),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "tcp://127.0.0.1:2", discovery.Host)
+		require.Equal(t, "second", discovery.Source)
+		require.Len(t, discovery.Candidates, 2)
+	})
+
+	t.Run("custom-chain/not-found", func(t *testing.T) {
+		_, err := DiscoverDockerHost(testHostSource("first", "", ErrHostNotFound))
+		require.ErrorIs(t, err, ErrHostNotFound)
+	})
+
+	t.Run("custom-chain/error", func(t *testing.T) {
+		errLookup := errors.New("lookup failed")
+
+		discovery, err := DiscoverDockerHost(
+			testHostSource("first", "", errLookup),
+			testHostSource("second", "tcp://127.0.0.1:2", nil),
+		)
+		require.ErrorIs(t, err, errLookup)
+		require.Empty(t, discovery.Source)
+		require.Len(t, discovery.Candidates, 1)
+	})
+
+	t.Run("custom-chain/invalid-schema", func(t *testing.T) {
+		_, err := DiscoverDockerHost(testHostSource("first", "http://127.0.0.1:2375", nil))
+		require.ErrorIs(t, err, ErrInvalidSchema)
+	})
+}
+
+func TestSocketSource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home) // Windows support
+
+	source := socketSource(HostSourceColima, "~/.colima/default/docker.sock", "~/.colima/docker.sock")
+
+	_, err := source.Lookup()
+	require.ErrorIs(t, err, ErrHostNotFound)
+	require.ErrorContains(t, err, filepath.Join(home, ".colima", "docker.sock"))
+
+	writeTestSocket(t, filepath.Join(home, ".colima", "docker.sock"))
+
+	host, err := source.Lookup()
+	require.NoError(t, err)
+	require.Equal(t, DefaultSchema+filepath.Join(home, ".colima", "docker.sock"), host)
+}
+
+func TestDiagnostics(t *testing.T) {
+	SetupTestDockerContexts(t, 1, 3) // current context is context1
+	t.Setenv(EnvOverrideHost, "")
+
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", xdgDir)
+	writeTestSocket(t, filepath.Join(xdgDir, "docker.sock"))
+
+	report := Diagnostics()
+	require.Contains(t, report, "docker host: tcp://127.0.0.1:1 (from context)\n")
+	require.Contains(t, report, "  env: rejected: docker host not found: DOCKER_HOST is not set\n")
+	require.Contains(t, report, "  context: selected: tcp://127.0.0.1:1\n")
+	require.Contains(t, report, "  rootless: found, lower precedence: "+DefaultSchema+filepath.Join(xdgDir, "docker.sock")+"\n")
+	require.Contains(t, report, "  default: found, lower precedence: "+DefaultDockerHost+"\n")
+
+	discovery := &HostDiscovery{Candidates: []HostCandidate{{Source: "first", Err: errors.New("lookup failed")}}}
+	require.Equal(t, "docker host: error from first: lookup failed\n  first: error: lookup failed\n", discovery.String())
+}
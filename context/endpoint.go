@@ -0,0 +1,213 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+const (
+	// DockerEndpoint is the name of the endpoint of a context to connect to the docker daemon.
+	DockerEndpoint = "docker"
+
+	// KubernetesEndpoint is the name of the endpoint of a context to connect to a Kubernetes cluster,
+	// as created by the legacy `docker context create --kubernetes`.
+	KubernetesEndpoint = "kubernetes"
+)
+
+// Endpoint represents an endpoint of a context, e.g. its docker endpoint.
+// Host and SkipTLSVerify are the fields shared by all the endpoints: the other fields,
+// e.g. the ones of the kubernetes endpoint or of the endpoints of plugins, are preserved
+// when the context is saved, and can be read with [Context.Endpoint] or [Endpoint.Decode].
+type Endpoint struct {
+	// Host is the host of the endpoint
+	Host string `json:",omitempty"`
+
+	// SkipTLSVerify is the flag to skip TLS verification
+	SkipTLSVerify bool
+
+	// additionalFields holds the raw JSON of the fields other than Host and SkipTLSVerify.
+	// These are marshaled/unmarshaled at the same level as Host, as stored by the Docker CLI.
+	additionalFields map[string]json.RawMessage
+
+	// skipTLSVerifyOmitted is true if the endpoint was unmarshaled without SkipTLSVerify,
+	// e.g. the endpoint of a plugin, so that it's not added when the endpoint is marshaled.
+	skipTLSVerifyOmitted bool
+}
+
+// MarshalJSON implements custom JSON marshaling for Endpoint
+func (ep *Endpoint) MarshalJSON() ([]byte, error) {
+	result := make(map[string]any, len(ep.additionalFields)+2)
+
+	for key, value := range ep.additionalFields {
+		result[key] = value
+	}
+
+	if ep.Host != "" {
+		result["Host"] = ep.Host
+	}
+	if ep.SkipTLSVerify || !ep.skipTLSVerifyOmitted {
+		result["SkipTLSVerify"] = ep.SkipTLSVerify
+	}
+
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Endpoint
+func (ep *Endpoint) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var known struct {
+		Host          string
+		SkipTLSVerify bool
+	}
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	ep.Host = known.Host
+	ep.SkipTLSVerify = known.SkipTLSVerify
+	_, hasSkipTLSVerify := raw["SkipTLSVerify"]
+	ep.skipTLSVerifyOmitted = !hasSkipTLSVerify
+
+	delete(raw, "Host")
+	delete(raw, "SkipTLSVerify")
+	ep.additionalFields = raw
+
+	return nil
+}
+
+// Field returns the raw JSON of an additional field of the endpoint
+func (ep *Endpoint) Field(key string) (json.RawMessage, bool) {
+	value, exists := ep.additionalFields[key]
+	return value, exists
+}
+
+// Fields returns a copy of all additional fields of the endpoint
+func (ep *Endpoint) Fields() map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage, len(ep.additionalFields))
+
+	maps.Copy(result, ep.additionalFields)
+
+	return result
+}
+
+// Decode decodes all the fields of the endpoint into v, e.g. a pointer to a struct
+// describing the endpoint of a plugin.
+func (ep *Endpoint) Decode(v any) error {
+	data, err := json.Marshal(ep)
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// KubernetesEndpointMeta is the typed data of the kubernetes endpoint of a context.
+// The kubeconfig-specific fields not listed here, e.g. the auth provider, are available
+// with [Endpoint.Field].
+type KubernetesEndpointMeta struct {
+	// Host is the address of the Kubernetes API server
+	Host string `json:",omitempty"`
+
+	// SkipTLSVerify is the flag to skip TLS verification
+	SkipTLSVerify bool
+
+	// DefaultNamespace is the namespace used when none is set
+	DefaultNamespace string `json:",omitempty"`
+}
+
+// EndpointDecoder decodes the JSON of an endpoint of a context into its typed data.
+type EndpointDecoder func(data json.RawMessage) (any, error)
+
+// endpointDecoders is the registry of the endpoint decoders, keyed by endpoint name.
+var endpointDecoders = struct {
+	sync.RWMutex
+	decoders map[string]EndpointDecoder
+}{
+	decoders: map[string]EndpointDecoder{
+		KubernetesEndpoint: JSONEndpointDecoder[KubernetesEndpointMeta](),
+	},
+}
+
+// RegisterEndpointDecoder registers the decoder of the endpoints with the given name,
+// replacing the decoder already registered for that name, if any.
+//
+// E.g. for the endpoint of a plugin:
+//
+//	type pluginEndpoint struct {
+//		Host    string
+//		Project string
+//	}
+//
+//	err := context.RegisterEndpointDecoder("my-plugin", context.JSONEndpointDecoder[pluginEndpoint]())
+func RegisterEndpointDecoder(name string, decoder EndpointDecoder) error {
+	if name == "" {
+		return errors.New("endpoint name is required")
+	}
+	if decoder == nil {
+		return errors.New("endpoint decoder is nil")
+	}
+
+	endpointDecoders.Lock()
+	defer endpointDecoders.Unlock()
+
+	endpointDecoders.decoders[name] = decoder
+
+	return nil
+}
+
+// JSONEndpointDecoder returns an endpoint decoder unmarshaling the endpoints into a *T.
+func JSONEndpointDecoder[T any]() EndpointDecoder {
+	return func(data json.RawMessage) (any, error) {
+		v := new(T)
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// endpointDecoder returns the decoder registered for the given endpoint name, if any.
+func endpointDecoder(name string) (EndpointDecoder, bool) {
+	endpointDecoders.RLock()
+	defer endpointDecoders.RUnlock()
+
+	decoder, ok := endpointDecoders.decoders[name]
+	return decoder, ok
+}
+
+// Endpoint returns the typed data of the endpoint of the context with the given name,
+// as returned by the decoder registered for it with [RegisterEndpointDecoder]:
+// a *[KubernetesEndpointMeta] for the kubernetes endpoint. If no decoder is registered
+// for the endpoint, e.g. for the docker endpoint, it returns the *[Endpoint] itself.
+//
+// It returns [ErrEndpointNotFound] if the context has no endpoint with the given name.
+func (ctx *Context) Endpoint(name string) (any, error) {
+	ep, ok := ctx.Endpoints[name]
+	if !ok || ep == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEndpointNotFound, name)
+	}
+
+	decoder, ok := endpointDecoder(name)
+	if !ok {
+		return ep, nil
+	}
+
+	data, err := json.Marshal(ep)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	v, err := decoder(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode endpoint %s: %w", name, err)
+	}
+
+	return v, nil
+}
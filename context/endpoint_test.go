@@ -0,0 +1,123 @@
+package context
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testEndpointsMeta = `{
+	"Name": "orchestrated",
+	"Metadata": {"Description": "with kubernetes", "StackOrchestrator": "kubernetes"},
+	"Endpoints": {
+		"docker": {"Host": "tcp://127.0.0.1:2375", "SkipTLSVerify": false},
+		"kubernetes": {
+			"Host": "https://127.0.0.1:6443",
+			"SkipTLSVerify": true,
+			"DefaultNamespace": "apps",
+			"AuthProvider": {"name": "oidc", "config": {"client-id": "docker"}}
+		},
+		"my-plugin": {"Project": "sdk", "Replicas": 3}
+	}
+}`
+
+func TestEndpoint_roundTrip(t *testing.T) {
+	var ctx Context
+	require.NoError(t, json.Unmarshal([]byte(testEndpointsMeta), &ctx))
+
+	kube := ctx.Endpoints[KubernetesEndpoint]
+	require.Equal(t, "https://127.0.0.1:6443", kube.Host)
+	require.True(t, kube.SkipTLSVerify)
+
+	namespace, ok := kube.Field("DefaultNamespace")
+	require.True(t, ok)
+	require.JSONEq(t, `"apps"`, string(namespace))
+	require.Len(t, kube.Fields(), 2)
+
+	tmpDir := t.TempDir()
+	s := &store{root: tmpDir}
+	require.NoError(t, s.add(&ctx))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ctx.encodedName, metaFile))
+	require.NoError(t, err)
+	require.JSONEq(t, testEndpointsMeta, string(data))
+}
+
+func TestContext_Endpoint(t *testing.T) {
+	var ctx Context
+	require.NoError(t, json.Unmarshal([]byte(testEndpointsMeta), &ctx))
+
+	t.Run("docker", func(t *testing.T) {
+		v, err := ctx.Endpoint(DockerEndpoint)
+		require.NoError(t, err)
+		require.Equal(t, ctx.Endpoints[DockerEndpoint], v)
+	})
+
+	t.Run("kubernetes", func(t *testing.T) {
+		v, err := ctx.Endpoint(KubernetesEndpoint)
+		require.NoError(t, err)
+		require.Equal(t, &KubernetesEndpointMeta{
+			Host:             "https://127.0.0.1:6443",
+			SkipTLSVerify:    true,
+			DefaultNamespace: "apps",
+		}, v)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		type pluginEndpoint struct {
+			Project  string
+			Replicas int
+		}
+
+		require.NoError(t, RegisterEndpointDecoder("my-plugin", JSONEndpointDecoder[pluginEndpoint]()))
+		t.Cleanup(func() {
+			endpointDecoders.Lock()
+			delete(endpointDecoders.decoders, "my-plugin")
+			endpointDecoders.Unlock()
+		})
+
+		v, err := ctx.Endpoint("my-plugin")
+		require.NoError(t, err)
+		require.Equal(t, &pluginEndpoint{Project: "sdk", Replicas: 3}, v)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		var v struct {
+			Project string
+		}
+		require.NoError(t, ctx.Endpoints["my-plugin"].Decode(&v))
+		require.Equal(t, "sdk", v.Project)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		v, err := ctx.Endpoint("missing")
+		require.ErrorIs(t, err, ErrEndpointNotFound)
+		require.Nil(t, v)
+	})
+
+	t.Run("register/invalid", func(t *testing.T) {
+		require.Error(t, RegisterEndpointDecoder("", JSONEndpointDecoder[KubernetesEndpointMeta]()))
+		require.Error(t, RegisterEndpointDecoder("my-plugin", nil))
+	})
+}
+
+func TestLoad(t *testing.T) {
+	SetupTestDockerContexts(t, 1, 1) // context3 has a "foo" endpoint only
+
+	_, err := Inspect("context3")
+	require.ErrorIs(t, err, ErrDockerHostNotSet)
+
+	ctx, err := Load("context3")
+	require.NoError(t, err)
+	require.Equal(t, "Docker Go SDK 3", ctx.Metadata.Description)
+
+	v, err := ctx.Endpoint("foo")
+	require.NoError(t, err)
+	require.IsType(t, &Endpoint{}, v)
+
+	_, err = Load("context-not-found")
+	require.ErrorIs(t, err, ErrDockerContextNotFound)
+}
@@ -8,4 +8,7 @@ var (
 
 	// ErrDockerContextNotFound is returned when the Docker context is not found.
 	ErrDockerContextNotFound = errors.New("docker context not found")
+
+	// ErrEndpointNotFound is returned when the Docker context has no endpoint with the given name.
+	ErrEndpointNotFound = errors.New("endpoint not found in Docker context")
 )
@@ -10,3 +10,18 @@ func init() {
 	// DefaultDockerHost is the default host to connect to the Docker socket on Linux
 	DefaultDockerHost = DefaultSchema + "/var/run/docker.sock"
 }
+
+// socketSources returns the sources of the discovery chain for the well-known sockets
+// of Docker and of the Docker-compatible runtimes on Linux and macOS, by precedence.
+func socketSources() []HostSource {
+	return []HostSource{
+		// Docker Desktop, on Linux and macOS
+		socketSource(HostSourceDockerDesktop, "~/.docker/run/docker.sock"),
+		socketSource(HostSourceVarRun, "/var/run/docker.sock"),
+		// the default Colima profile, with the location used before Colima v0.4
+		socketSource(HostSourceColima, "~/.colima/default/docker.sock", "~/.colima/docker.sock"),
+		socketSource(HostSourceRancherDesktop, "~/.rd/docker.sock"),
+		// rootful Podman on Linux, and the Podman machine on macOS
+		socketSource(HostSourcePodman, "/run/podman/podman.sock", "~/.local/share/containers/podman/machine/podman.sock"),
+	}
+}
@@ -10,3 +10,9 @@ func init() {
 	// DefaultDockerHost is the default host to connect to the Docker socket on Windows
 	DefaultDockerHost = DefaultSchema + "//./pipe/docker_engine"
 }
+
+// socketSources returns no sources on Windows, where the Docker daemon is reached
+// through the named pipe of the DefaultDockerHost.
+func socketSources() []HostSource {
+	return nil
+}
@@ -6,10 +6,24 @@ type contextOptions struct {
 	description      string
 	additionalFields map[string]any
 	skipTLSVerify    bool
+	tls              TLSData
 	current          bool
 }
 
-// CreateContextOption is a function that can be used to create a context.
+// tlsData returns the TLS material of the options, validated, or nil if there is none.
+func (o *contextOptions) tlsData() (*TLSData, error) {
+	if o.tls.isEmpty() {
+		return nil, nil
+	}
+
+	if _, err := o.tls.clientConfig(false); err != nil {
+		return nil, err
+	}
+
+	return &o.tls, nil
+}
+
+// CreateContextOption is a function that can be used to create or update a context.
 type CreateContextOption func(*contextOptions) error
 
 // WithHost sets the host for the context.
@@ -44,6 +58,33 @@ func WithSkipTLSVerify() CreateContextOption {
 	}
 }
 
+// WithTLSCA sets the PEM-encoded certificate of the authority that signed the certificate
+// of the docker daemon. It's written to the TLS store of the context.
+func WithTLSCA(ca []byte) CreateContextOption {
+	return func(c *contextOptions) error {
+		c.tls.CA = ca
+		return nil
+	}
+}
+
+// WithTLSCert sets the PEM-encoded certificate of the client, used to authenticate to the
+// docker daemon. It requires the key set with [WithTLSKey]. It's written to the TLS store of the context.
+func WithTLSCert(cert []byte) CreateContextOption {
+	return func(c *contextOptions) error {
+		c.tls.Cert = cert
+		return nil
+	}
+}
+
+// WithTLSKey sets the PEM-encoded private key of the certificate of the client set with [WithTLSCert].
+// It's written to the TLS store of the context, only readable by the owner.
+func WithTLSKey(key []byte) CreateContextOption {
+	return func(c *contextOptions) error {
+		c.tls.Key = key
+		return nil
+	}
+}
+
 // AsCurrent sets the context as the current context.
 func AsCurrent() CreateContextOption {
 	return func(c *contextOptions) error {
@@ -7,19 +7,28 @@ import (
 )
 
 var (
-	ErrRootlessDockerNotFoundXDGRuntimeDir = errors.New("docker.sock not found in $XDG_RUNTIME_DIR")
+	ErrRootlessDockerNotFoundXDGRuntimeDir = errors.New("docker.sock or podman/podman.sock not found in $XDG_RUNTIME_DIR")
 	ErrXDGRuntimeDirNotSet                 = errors.New("$XDG_RUNTIME_DIR is not set")
-	ErrInvalidSchema                       = errors.New("URL schema is not " + DefaultSchema + " or tcp")
+	ErrInvalidSchema                       = errors.New("URL schema is not " + DefaultSchema + ", tcp or ssh")
 )
 
-// rootlessSocketPathFromEnv returns the path to the rootless Docker socket from the XDG_RUNTIME_DIR environment variable.
-// It should include the Docker socket schema (unix://, npipe:// or tcp://) in the returned path.
+// rootlessSocketPaths are the paths of the rootless sockets in the XDG_RUNTIME_DIR directory, by precedence:
+// the socket of rootless Docker, and the socket of the Docker-compatible API of rootless Podman.
+var rootlessSocketPaths = []string{
+	"docker.sock",
+	filepath.Join("podman", "podman.sock"),
+}
+
+// rootlessSocketPathFromEnv returns the path to the rootless Docker or Podman socket from the XDG_RUNTIME_DIR
+// environment variable. It should include the Docker socket schema (unix://, npipe:// or tcp://) in the returned path.
 func rootlessSocketPathFromEnv() (string, error) {
 	xdgRuntimeDir, exists := os.LookupEnv("XDG_RUNTIME_DIR")
 	if exists && xdgRuntimeDir != "" {
-		f := filepath.Join(xdgRuntimeDir, "docker.sock")
-		if fileExists(f) {
-			return DefaultSchema + f, nil
+		for _, p := range rootlessSocketPaths {
+			f := filepath.Join(xdgRuntimeDir, p)
+			if fileExists(f) {
+				return DefaultSchema + f, nil
+			}
 		}
 
 		return "", ErrRootlessDockerNotFoundXDGRuntimeDir
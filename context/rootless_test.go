@@ -21,6 +21,27 @@ func TestRootlessSocketPathFromEnv(t *testing.T) {
 		require.Equal(t, DefaultSchema+filepath.Join(tmpDir, "docker.sock"), path)
 	})
 
+	t.Run("success/podman", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("XDG_RUNTIME_DIR", tmpDir)
+
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "podman"), 0o755))
+		err := os.WriteFile(filepath.Join(tmpDir, "podman", "podman.sock"), []byte("synthetic podman socket"), 0o755)
+		require.NoError(t, err)
+
+		path, err := rootlessSocketPathFromEnv()
+		require.NoError(t, err)
+		require.Equal(t, DefaultSchema+filepath.Join(tmpDir, "podman", "podman.sock"), path)
+
+		// the rootless Docker socket takes precedence
+		err = os.WriteFile(filepath.Join(tmpDir, "docker.sock"), []byte("synthetic docker socket"), 0o755)
+		require.NoError(t, err)
+
+		path, err = rootlessSocketPathFromEnv()
+		require.NoError(t, err)
+		require.Equal(t, DefaultSchema+filepath.Join(tmpDir, "docker.sock"), path)
+	})
+
 	t.Run("env-var-not-set", func(t *testing.T) {
 		t.Setenv("XDG_RUNTIME_DIR", "")
 		path, err := rootlessSocketPathFromEnv()
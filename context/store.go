@@ -28,7 +28,10 @@ type Context struct {
 	Metadata *Metadata `json:"Metadata,omitempty"`
 
 	// Endpoints is the list of endpoints for the context
-	Endpoints map[string]*endpoint `json:"Endpoints,omitempty"`
+	Endpoints map[string]*Endpoint `json:"Endpoints,omitempty"`
+
+	// tls is the TLS material of the docker endpoint, stored apart from the metadata
+	tls *TLSData `json:"-"`
 }
 
 // store manages Docker context metadata files
@@ -116,42 +119,59 @@ func (dc *Metadata) Fields() map[string]any {
 	return result
 }
 
-// endpoint represents a Docker endpoint configuration
-type endpoint struct {
-	// Host is the host of the endpoint
-	Host string `json:",omitempty"`
+// inspect inspects a context by name, requiring its docker endpoint to have a host.
+func (s *store) inspect(ctxName string) (Context, error) {
+	ctx, err := s.find(ctxName)
+	if err != nil {
+		return Context{}, err
+	}
 
-	// SkipTLSVerify is the flag to skip TLS verification
-	SkipTLSVerify bool
+	ep, ok := ctx.Endpoints[DockerEndpoint]
+	if !ok || ep == nil || ep.Host == "" {
+		return Context{}, ErrDockerHostNotSet
+	}
+
+	return s.resolve(ctx)
 }
 
-// inspect inspects a context by name
-func (s *store) inspect(ctxName string) (Context, error) {
+// get returns a context by name, whatever its endpoints.
+func (s *store) get(ctxName string) (Context, error) {
+	ctx, err := s.find(ctxName)
+	if err != nil {
+		return Context{}, err
+	}
+
+	return s.resolve(ctx)
+}
+
+// find finds a context by name in the store.
+func (s *store) find(ctxName string) (*Context, error) {
 	contexts, err := s.list()
 	if err != nil {
-		return Context{}, fmt.Errorf("list contexts: %w", err)
+		return nil, fmt.Errorf("list contexts: %w", err)
 	}
 
 	for _, ctx := range contexts {
 		if ctx.Name == ctxName {
-			ep, ok := ctx.Endpoints["docker"]
-			if !ok || ep == nil || ep.Host == "" {
-				return Context{}, ErrDockerHostNotSet
-			}
-
-			cfg, err := config.Load()
-			if err != nil {
-				return Context{}, fmt.Errorf("load config: %w", err)
-			}
-			ctx.isCurrent = cfg.CurrentContext == ctx.Name
+			return ctx, nil
+		}
+	}
 
-			ctx.encodedName = digest.FromString(ctx.Name).Encoded()
+	return nil, ErrDockerContextNotFound
+}
 
-			return *ctx, nil
-		}
+// resolve returns the given context found in the store, with its encoded name,
+// and whether it's the current context.
+func (s *store) resolve(ctx *Context) (Context, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return Context{}, fmt.Errorf("load config: %w", err)
 	}
+	ctx.isCurrent = cfg.CurrentContext == ctx.Name
+
+	ctx.encodedName = digest.FromString(ctx.Name).Encoded()
 
-	return Context{}, ErrDockerContextNotFound
+	return *ctx, nil
 }
 
 // add adds a context to the store, creating the directory if it doesn't exist.
@@ -165,6 +185,15 @@ func (s *store) add(ctx *Context) error {
 		return fmt.Errorf("context already exists: %s", ctx.Name)
 	}
 
+	return s.save(ctx)
+}
+
+// save saves the metadata of a context to the store, replacing the existing one.
+func (s *store) save(ctx *Context) error {
+	if ctx.encodedName == "" {
+		ctx.encodedName = digest.FromString(ctx.Name).Encoded()
+	}
+
 	err := os.MkdirAll(filepath.Join(s.root, ctx.encodedName), 0o755)
 	if err != nil {
 		return fmt.Errorf("mkdir: %w", err)
@@ -14,7 +14,7 @@ func TestExtractDockerHost(t *testing.T) {
 	t.Run("context-found-with-host", func(t *testing.T) {
 		host := requireDockerHost(t, "test-context", Context{
 			Name: "test-context",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
 		})
@@ -24,7 +24,7 @@ func TestExtractDockerHost(t *testing.T) {
 	t.Run("context-found-without-host", func(t *testing.T) {
 		requireDockerHostError(t, "test-context", Context{
 			Name: "test-context",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {},
 			},
 		}, ErrDockerHostNotSet)
@@ -33,7 +33,7 @@ func TestExtractDockerHost(t *testing.T) {
 	t.Run("context-not-found", func(t *testing.T) {
 		requireDockerHostError(t, "missing", Context{
 			Name: "other-context",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
 		}, ErrDockerContextNotFound)
@@ -42,7 +42,7 @@ func TestExtractDockerHost(t *testing.T) {
 	t.Run("nested-context-found", func(t *testing.T) {
 		host := requireDockerHostInPath(t, "nested-context", "parent/nested-context", Context{
 			Name: "nested-context",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
 		})
@@ -65,7 +65,7 @@ func TestStore_add(t *testing.T) {
 					},
 				},
 			},
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://localhost:2375"},
 			},
 		}
@@ -110,7 +110,7 @@ func TestStore_Inspect(t *testing.T) {
 		Metadata: &Metadata{
 			Description: "test context",
 		},
-		Endpoints: map[string]*endpoint{
+		Endpoints: map[string]*Endpoint{
 			"docker": {
 				Host: "tcp://localhost:2375",
 			},
@@ -147,7 +147,7 @@ func TestStore_Inspect(t *testing.T) {
 		setupTestContext(t, tmpDir, "fields", Context{
 			Name:     "ctx-with-fields",
 			Metadata: dockerCtx,
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {
 					Host: "tcp://localhost:2375",
 				},
@@ -188,7 +188,7 @@ func TestStore_List(t *testing.T) {
 		want := Context{
 			Name:     "test",
 			Metadata: dockerCtx,
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {
 					Host:          "tcp://localhost:2375",
 					SkipTLSVerify: true,
@@ -244,7 +244,7 @@ func TestStore_load(t *testing.T) {
 		want := Context{
 			Name:     "test",
 			Metadata: dockerCtx,
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {
 					Host:          "tcp://localhost:2375",
 					SkipTLSVerify: true,
@@ -326,7 +326,7 @@ func TestStore_load(t *testing.T) {
 
 		meta := Context{
 			Name: "test",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://localhost:2375"},
 			},
 		}
@@ -394,7 +394,7 @@ func TestStore_load(t *testing.T) {
 		// Only name and docker endpoint, no context metadata
 		meta := Context{
 			Name: "test",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://localhost:2375"},
 			},
 		}
@@ -417,13 +417,13 @@ func TestStore_list(t *testing.T) {
 		contexts := map[string]Context{
 			"context1": {
 				Name: "context1",
-				Endpoints: map[string]*endpoint{
+				Endpoints: map[string]*Endpoint{
 					"docker": {Host: "tcp://1.2.3.4:2375"},
 				},
 			},
 			"nested/context2": {
 				Name: "context2",
-				Endpoints: map[string]*endpoint{
+				Endpoints: map[string]*Endpoint{
 					"docker": {Host: "unix:///var/run/docker.sock"},
 				},
 			},
@@ -473,7 +473,7 @@ func TestStore_list(t *testing.T) {
 		// Setup one valid context
 		validMeta := Context{
 			Name: "valid",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
 		}
@@ -512,7 +512,7 @@ func TestStore_list(t *testing.T) {
 
 		meta := Context{
 			Name: "test",
-			Endpoints: map[string]*endpoint{
+			Endpoints: map[string]*Endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
 		}
@@ -0,0 +1,227 @@
+package context
+
+// The layout of the TLS store has been extracted from https://github.com/docker/cli,
+// more especifically from https://github.com/docker/cli/blob/master/cli/context/store/tlsstore.go
+// and https://github.com/docker/cli/blob/master/cli/context/docker/load.go
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/go-sdk/config"
+)
+
+const (
+	// tlsDir is the name of the directory containing the TLS material of the contexts
+	tlsDir = "tls"
+
+	// the names of the files containing the TLS material of an endpoint
+	tlsCAFile   = "ca.pem"
+	tlsCertFile = "cert.pem"
+	tlsKeyFile  = "key.pem"
+)
+
+// TLSData is the TLS material of an endpoint of a context, stored in PEM format in the
+// contexts/tls/<digest>/<endpoint> directory of the Docker configuration directory.
+type TLSData struct {
+	// CA is the certificate of the authority that signed the certificate of the docker daemon.
+	CA []byte
+
+	// Cert is the certificate of the client, used to authenticate to the docker daemon.
+	Cert []byte
+
+	// Key is the private key of the certificate of the client.
+	Key []byte
+}
+
+// isEmpty returns true if the TLS data has no material.
+func (d *TLSData) isEmpty() bool {
+	return d == nil || (len(d.CA) == 0 && len(d.Cert) == 0 && len(d.Key) == 0)
+}
+
+// clientConfig returns the TLS configuration to connect to the docker daemon with the TLS data.
+func (d *TLSData) clientConfig(skipTLSVerify bool) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: skipTLSVerify,
+	}
+
+	if d == nil {
+		return cfg, nil
+	}
+
+	if len(d.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(d.CA) {
+			return nil, errors.New("invalid CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (len(d.Cert) > 0) != (len(d.Key) > 0) {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+
+	if len(d.Cert) > 0 {
+		if block, _ := pem.Decode(d.Key); block == nil {
+			return nil, errors.New("invalid client key: no PEM data found")
+		}
+
+		cert, err := tls.X509KeyPair(d.Cert, d.Key)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// TLS returns the TLS material of the docker endpoint of the context,
+// or nil if the context has no TLS material.
+func (ctx *Context) TLS() *TLSData {
+	return ctx.tls
+}
+
+// TLSConfig returns the TLS configuration to connect to the docker endpoint of the context,
+// built from its TLS material and the SkipTLSVerify flag of the endpoint.
+// It returns nil if the context has no TLS material and verifies the TLS certificates,
+// i.e. when the docker daemon is not reached through TLS.
+func (ctx *Context) TLSConfig() (*tls.Config, error) {
+	ep := ctx.Endpoints[DockerEndpoint]
+	skipTLSVerify := ep != nil && ep.SkipTLSVerify
+
+	if ctx.tls.isEmpty() && !skipTLSVerify {
+		return nil, nil
+	}
+
+	return ctx.tls.clientConfig(skipTLSVerify)
+}
+
+// tlsStore manages the TLS material of the Docker contexts
+type tlsStore struct {
+	root string
+}
+
+// endpointDir returns the directory containing the TLS material of the given endpoint of a context.
+func (s *tlsStore) endpointDir(encodedName, endpointName string) string {
+	return filepath.Join(s.root, encodedName, endpointName)
+}
+
+// load loads the TLS material of the given endpoint of a context.
+// It returns nil if the endpoint has no TLS material.
+func (s *tlsStore) load(encodedName, endpointName string) (*TLSData, error) {
+	var data TLSData
+	for file, dst := range map[string]*[]byte{tlsCAFile: &data.CA, tlsCertFile: &data.Cert, tlsKeyFile: &data.Key} {
+		content, err := os.ReadFile(filepath.Join(s.endpointDir(encodedName, endpointName), file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", file, err)
+		}
+		*dst = content
+	}
+
+	if data.isEmpty() {
+		return nil, nil
+	}
+	return &data, nil
+}
+
+// save saves the TLS material of the given endpoint of a context.
+func (s *tlsStore) save(encodedName, endpointName string, data *TLSData) error {
+	if data.isEmpty() {
+		return nil
+	}
+
+	for file, content := range map[string][]byte{tlsCAFile: data.CA, tlsCertFile: data.Cert, tlsKeyFile: data.Key} {
+		if len(content) == 0 {
+			continue
+		}
+		if err := s.writeFile(encodedName, endpointName, file, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFile writes a file of TLS material of the given endpoint of a context.
+// The private key is only readable by the owner.
+func (s *tlsStore) writeFile(encodedName, endpointName, file string, content []byte) error {
+	dir := s.endpointDir(encodedName, endpointName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+
+	perm := os.FileMode(0o644)
+	if file == tlsKeyFile {
+		perm = 0o600
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, file), content, perm); err != nil {
+		return fmt.Errorf("write %s: %w", file, err)
+	}
+	return nil
+}
+
+// readFile reads a file of TLS material of the given endpoint of a context.
+func (s *tlsStore) readFile(encodedName, endpointName, file string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.endpointDir(encodedName, endpointName), file))
+}
+
+// listFiles lists the files of TLS material of a context, by endpoint.
+func (s *tlsStore) listFiles(encodedName string) (map[string][]string, error) {
+	endpoints, err := os.ReadDir(filepath.Join(s.root, encodedName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	files := make(map[string][]string)
+	for _, ep := range endpoints {
+		if !ep.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(s.endpointDir(encodedName, ep.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files[ep.Name()] = append(files[ep.Name()], entry.Name())
+			}
+		}
+	}
+	return files, nil
+}
+
+// deleteEndpoint deletes the TLS material of the given endpoint of a context.
+func (s *tlsStore) deleteEndpoint(encodedName, endpointName string) error {
+	return os.RemoveAll(s.endpointDir(encodedName, endpointName))
+}
+
+// delete deletes the TLS material of all the endpoints of a context.
+func (s *tlsStore) delete(encodedName string) error {
+	return os.RemoveAll(filepath.Join(s.root, encodedName))
+}
+
+// tlsRoot returns the root directory of the TLS material of the Docker contexts.
+func tlsRoot() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("docker config dir: %w", err)
+	}
+
+	return filepath.Join(dir, contextsDir, tlsDir), nil
+}
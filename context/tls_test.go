@@ -0,0 +1,151 @@
+package context_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/config"
+	"github.com/docker/go-sdk/context"
+)
+
+// newTestCertificates returns a self-signed PEM-encoded CA, and a client certificate signed by it with its key.
+func newTestCertificates(t *testing.T) (ca, cert, key []byte) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNew_tls(t *testing.T) {
+	ca, cert, key := newTestCertificates(t)
+
+	t.Run("mutual-tls", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		ctx, err := context.New("tls", context.WithHost("tcp://127.0.0.1:2376"),
+			context.WithTLSCA(ca), context.WithTLSCert(cert), context.WithTLSKey(key))
+		require.NoError(t, err)
+		require.Equal(t, &context.TLSData{CA: ca, Cert: cert, Key: key}, ctx.TLS())
+
+		// the material is written to the TLS store, with the layout of the docker CLI
+		dir, err := config.Dir()
+		require.NoError(t, err)
+		endpointDir := filepath.Join(dir, "contexts", "tls", digest.FromString("tls").Encoded(), "docker")
+
+		for file, content := range map[string][]byte{"ca.pem": ca, "cert.pem": cert, "key.pem": key} {
+			got, err := os.ReadFile(filepath.Join(endpointDir, file))
+			require.NoError(t, err)
+			require.Equal(t, content, got)
+		}
+
+		inspected, err := context.Inspect("tls")
+		require.NoError(t, err)
+		require.Equal(t, ctx.TLS(), inspected.TLS())
+
+		tlsConfig, err := inspected.TLSConfig()
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.Len(t, tlsConfig.Certificates, 1)
+		require.False(t, tlsConfig.InsecureSkipVerify)
+
+		require.NoError(t, inspected.Delete())
+		require.NoDirExists(t, filepath.Dir(endpointDir))
+	})
+
+	t.Run("ca-only", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		ctx, err := context.New("tls", context.WithHost("tcp://127.0.0.1:2376"), context.WithTLSCA(ca))
+		require.NoError(t, err)
+
+		tlsConfig, err := ctx.TLSConfig()
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.Empty(t, tlsConfig.Certificates)
+	})
+
+	t.Run("no-tls", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		ctx, err := context.New("plain", context.WithHost("tcp://127.0.0.1:2375"))
+		require.NoError(t, err)
+		require.Nil(t, ctx.TLS())
+
+		tlsConfig, err := ctx.TLSConfig()
+		require.NoError(t, err)
+		require.Nil(t, tlsConfig)
+	})
+
+	t.Run("skip-tls-verify", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		ctx, err := context.New("insecure", context.WithHost("tcp://127.0.0.1:2376"), context.WithSkipTLSVerify())
+		require.NoError(t, err)
+
+		tlsConfig, err := ctx.TLSConfig()
+		require.NoError(t, err)
+		require.True(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("error/cert-without-key", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.New("tls", context.WithHost("tcp://127.0.0.1:2376"), context.WithTLSCert(cert))
+		require.ErrorContains(t, err, "client certificate and key must be set together")
+
+		_, err = context.Inspect("tls")
+		require.ErrorIs(t, err, context.ErrDockerContextNotFound)
+	})
+
+	t.Run("error/invalid-ca", func(t *testing.T) {
+		context.SetupTestDockerContexts(t, 1, 3)
+
+		_, err := context.New("tls", context.WithHost("tcp://127.0.0.1:2376"), context.WithTLSCA([]byte("not a certificate")))
+		require.ErrorContains(t, err, "invalid CA certificate")
+	})
+}
@@ -0,0 +1,249 @@
+package context
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/go-sdk/config"
+)
+
+// DefaultWatchInterval is the default interval between two polls of the Docker configuration by a [Watcher].
+const DefaultWatchInterval = time.Second
+
+// EventType is the type of a change of the Docker contexts.
+type EventType string
+
+const (
+	// EventCurrentChanged is emitted when the current context, or its docker host, changes,
+	// e.g. after `docker context use`, or after updating the docker endpoint of the current context.
+	EventCurrentChanged EventType = "current-changed"
+
+	// EventStoreChanged is emitted when a context is added, updated or removed.
+	EventStoreChanged EventType = "store-changed"
+)
+
+// Event is a change of the Docker contexts, observed by a [Watcher].
+type Event struct {
+	// Type is the type of the change.
+	Type EventType
+
+	// Context is the name of the current context after the change.
+	Context string
+
+	// Host is the docker host of the current context after the change, as returned by [CurrentDockerHost].
+	Host string
+
+	// Err is the error resolving the current context or its docker host after the change, if any.
+	Err error
+}
+
+// watchOptions is the options for watching the contexts.
+type watchOptions struct {
+	interval time.Duration
+}
+
+// WatchOption is a function that can be used to configure a [Watcher].
+type WatchOption func(*watchOptions) error
+
+// WithWatchInterval sets the interval between two polls of the Docker configuration.
+// If not set, [DefaultWatchInterval] is used.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) error {
+		if interval <= 0 {
+			return errors.New("watch interval must be positive")
+		}
+		o.interval = interval
+		return nil
+	}
+}
+
+// Watcher observes the Docker config file and the contexts metadata store, polling them,
+// and emits an [Event] for each change of the current context and of the contexts.
+type Watcher struct {
+	interval time.Duration
+	events   chan Event
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	// last is the state of the contexts at the previous poll.
+	last watchState
+}
+
+// watchState is the state of the Docker contexts observed by a watcher.
+type watchState struct {
+	// configDigest and storeDigest are the digests of the content of the Docker
+	// config file and of the contexts metadata store.
+	configDigest [sha256.Size]byte
+	storeDigest  [sha256.Size]byte
+
+	// resolved is true once the current context and its docker host are resolved.
+	resolved bool
+
+	current string
+	host    string
+	err     error
+}
+
+// NewWatcher returns a watcher of the Docker contexts, which starts observing them right away.
+// It must be closed with [Watcher.Close] once it's not needed anymore.
+//
+// E.g. to follow the switches of the current context done with `docker context use`:
+//
+//	w, err := context.NewWatcher()
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Close()
+//
+//	for event := range w.Events() {
+//		if event.Type == context.EventCurrentChanged {
+//			fmt.Printf("current docker host: %s\n", event.Host)
+//		}
+//	}
+func NewWatcher(opts ...WatchOption) (*Watcher, error) {
+	options := &watchOptions{interval: DefaultWatchInterval}
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &Watcher{
+		interval: options.interval,
+		events:   make(chan Event, 16),
+		done:     make(chan struct{}),
+	}
+	w.last = w.poll(watchState{})
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+// Events returns the channel of the changes observed by the watcher.
+// It's closed when the watcher is closed.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Close stops the watcher and closes its events channel.
+func (w *Watcher) Close() error {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		close(w.events)
+	})
+	return nil
+}
+
+// run polls the Docker configuration until the watcher is closed.
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		state := w.poll(w.last)
+
+		var events []Event
+		if state.current != w.last.current || state.host != w.last.host || errorString(state.err) != errorString(w.last.err) {
+			events = append(events, Event{Type: EventCurrentChanged, Context: state.current, Host: state.host, Err: state.err})
+		}
+		if state.storeDigest != w.last.storeDigest {
+			events = append(events, Event{Type: EventStoreChanged, Context: state.current, Host: state.host, Err: state.err})
+		}
+		w.last = state
+
+		for _, event := range events {
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// poll returns the current state of the Docker contexts. The current context and its
+// docker host are only resolved again if the config file or the store changed.
+func (w *Watcher) poll(last watchState) watchState {
+	state := last
+
+	if dir, err := config.Dir(); err == nil {
+		state.configDigest = digestFiles([]string{filepath.Join(dir, config.FileName)})
+	}
+
+	if root, err := metaRoot(); err == nil {
+		state.storeDigest = digestFiles(metaFiles(root))
+	}
+
+	if last.resolved && state.configDigest == last.configDigest && state.storeDigest == last.storeDigest {
+		return state
+	}
+
+	state.resolved = true
+	state.current, state.err = Current()
+	if state.err == nil {
+		state.host, state.err = CurrentDockerHost()
+	}
+	if state.err != nil {
+		state.host = ""
+	}
+
+	return state
+}
+
+// metaFiles returns the paths of the metadata files of the contexts in the store, in lexical order.
+func metaFiles(root string) []string {
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && d.Name() == metaFile {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// digestFiles returns the digest of the paths and contents of the given files.
+// The missing files are digested as empty.
+func digestFiles(paths []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, path := range paths {
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		if content, err := os.ReadFile(path); err == nil {
+			h.Write(content)
+		}
+		h.Write([]byte{0})
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// errorString returns the message of the error, or an empty string if it's nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,89 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/context"
+)
+
+// nextEvent returns the next event of the watcher, failing the test if none is emitted in time.
+func nextEvent(t *testing.T, w *context.Watcher) context.Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-w.Events():
+		require.True(t, ok, "events channel closed")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no event emitted")
+		return context.Event{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	context.SetupTestDockerContexts(t, 1, 3)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	w, err := context.NewWatcher(context.WithWatchInterval(10 * time.Millisecond))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, w.Close())
+	}()
+
+	t.Run("use", func(t *testing.T) {
+		require.NoError(t, context.Use("context2"))
+
+		event := nextEvent(t, w)
+		require.Equal(t, context.EventCurrentChanged, event.Type)
+		require.Equal(t, "context2", event.Context)
+		require.Equal(t, "tcp://127.0.0.1:2", event.Host)
+		require.NoError(t, event.Err)
+	})
+
+	t.Run("new", func(t *testing.T) {
+		_, err := context.New("watched", context.WithHost("tcp://127.0.0.1:1234"))
+		require.NoError(t, err)
+
+		event := nextEvent(t, w)
+		require.Equal(t, context.EventStoreChanged, event.Type)
+		require.Equal(t, "context2", event.Context)
+	})
+
+	t.Run("update-current", func(t *testing.T) {
+		_, err := context.Update("watched", context.AsCurrent())
+		require.NoError(t, err)
+
+		event := nextEvent(t, w)
+		require.Equal(t, context.EventCurrentChanged, event.Type)
+		require.Equal(t, "watched", event.Context)
+		require.Equal(t, "tcp://127.0.0.1:1234", event.Host)
+
+		_, err = context.Update("watched", context.WithHost("tcp://127.0.0.1:2376"))
+		require.NoError(t, err)
+
+		// the docker host of the current context changed, as well as the store
+		event = nextEvent(t, w)
+		require.Equal(t, context.EventCurrentChanged, event.Type)
+		require.Equal(t, "tcp://127.0.0.1:2376", event.Host)
+
+		event = nextEvent(t, w)
+		require.Equal(t, context.EventStoreChanged, event.Type)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		require.NoError(t, w.Close())
+
+		_, ok := <-w.Events()
+		require.False(t, ok)
+	})
+}
+
+func TestNewWatcher_invalidInterval(t *testing.T) {
+	_, err := context.NewWatcher(context.WithWatchInterval(0))
+	require.Error(t, err)
+}
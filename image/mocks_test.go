@@ -10,10 +10,25 @@ import (
 	"github.com/moby/moby/client"
 )
 
+// mockCli is embedded by the mocks simulating the failures of the pulls and builds, and the
+// labels of the images of a session, which the fake Docker API of the clienttest package
+// doesn't cover.
+type mockCli struct {
+	client.APIClient
+}
+
+func (m *mockCli) Ping(_ context.Context, _ client.PingOptions) (client.PingResult, error) {
+	return client.PingResult{}, nil
+}
+
+func (m *mockCli) Close() error {
+	return nil
+}
+
 // errMockCli is a mock implementation of client.APIClient, which is handy for simulating
 // error returns in retry scenarios.
 type errMockCli struct {
-	client.APIClient
+	mockCli
 
 	err             error
 	imageBuildCount int
@@ -21,10 +36,6 @@ type errMockCli struct {
 	lastPullOptions client.ImagePullOptions
 }
 
-func (f *errMockCli) Ping(_ context.Context, _ client.PingOptions) (client.PingResult, error) {
-	return client.PingResult{}, nil
-}
-
 func (f *errMockCli) ImageBuild(_ context.Context, _ io.Reader, _ client.ImageBuildOptions) (client.ImageBuildResult, error) {
 	f.imageBuildCount++
 
@@ -47,10 +58,6 @@ func (f *errMockCli) ImagePull(_ context.Context, _ string, opts client.ImagePul
 	return errMockImagePullResponse{ReadCloser: io.NopCloser(bytes.NewBufferString(mockPullOutput))}, f.err
 }
 
-func (f *errMockCli) Close() error {
-	return nil
-}
-
 type errMockImagePullResponse struct {
 	io.ReadCloser
 }
@@ -129,6 +129,21 @@ func WithRemoveOptions(options dockerclient.ImageRemoveOptions) RemoveOption {
 	}
 }
 
+// ListOption is a function that configures the list options.
+type ListOption func(*listOptions) error
+
+type listOptions struct {
+	client client.SDKClient
+}
+
+// WithListClient sets the list client used to list the images.
+func WithListClient(listClient client.SDKClient) ListOption {
+	return func(opts *listOptions) error {
+		opts.client = listClient
+		return nil
+	}
+}
+
 // SaveOption is a function that configures the save options.
 type SaveOption func(*saveOptions) error
 
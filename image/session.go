@@ -0,0 +1,75 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moby/moby/api/types/image"
+	dockerclient "github.com/moby/moby/client"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// ListBySession returns the images built by the client with the given session ID.
+// See [client.SDKClient.SessionID].
+func ListBySession(ctx context.Context, sessionID string, opts ...ListOption) ([]image.Summary, error) {
+	listOpts := &listOptions{}
+	for _, opt := range opts {
+		if err := opt(listOpts); err != nil {
+			return nil, fmt.Errorf("apply list option: %w", err)
+		}
+	}
+
+	if listOpts.client == nil {
+		sdk, err := client.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listOpts.client = sdk
+	}
+
+	resp, err := listOpts.client.ImageList(ctx, dockerclient.ImageListOptions{
+		Filters: client.SessionFilters(sessionID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+
+	return resp.Items, nil
+}
+
+// RemoveBySession removes the images built by the client with the given session ID,
+// using the given remove options. It tries to remove all of them, returning the errors
+// of the ones that could not be removed.
+func RemoveBySession(ctx context.Context, sessionID string, opts ...RemoveOption) error {
+	removeOpts := &removeOptions{}
+	for _, opt := range opts {
+		if err := opt(removeOpts); err != nil {
+			return fmt.Errorf("apply remove option: %w", err)
+		}
+	}
+
+	if removeOpts.client == nil {
+		sdk, err := client.New(ctx)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, WithRemoveClient(sdk))
+		removeOpts.client = sdk
+	}
+
+	images, err := ListBySession(ctx, sessionID, WithListClient(removeOpts.client))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, img := range images {
+		if _, err := Remove(ctx, img.ID, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("image %s: %w", img.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/image"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// sessionMockCli is a mock implementation of client.APIClient, which returns
+// a single image for the session in the label filter, and records the removals.
+type sessionMockCli struct {
+	mockCli
+
+	removed []string
+}
+
+func (m *sessionMockCli) ImageList(_ context.Context, options dockerclient.ImageListOptions) (dockerclient.ImageListResult, error) {
+	if !options.Filters["label"][client.LabelSessionID+"=session-1"] {
+		return dockerclient.ImageListResult{}, nil
+	}
+	return dockerclient.ImageListResult{Items: []image.Summary{{ID: "sha256:abc"}}}, nil
+}
+
+func (m *sessionMockCli) ImageRemove(_ context.Context, id string, _ dockerclient.ImageRemoveOptions) (dockerclient.ImageRemoveResult, error) {
+	m.removed = append(m.removed, id)
+	return dockerclient.ImageRemoveResult{}, nil
+}
+
+func TestListBySession(t *testing.T) {
+	m := &sessionMockCli{}
+	sdk, err := client.New(context.Background(), client.WithDockerAPI(m))
+	require.NoError(t, err)
+
+	images, err := ListBySession(context.Background(), "session-1", WithListClient(sdk))
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.Equal(t, "sha256:abc", images[0].ID)
+
+	images, err = ListBySession(context.Background(), "session-2", WithListClient(sdk))
+	require.NoError(t, err)
+	require.Empty(t, images)
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, RemoveBySession(context.Background(), "session-1", WithRemoveClient(sdk)))
+		require.Equal(t, []string{"sha256:abc"}, m.removed)
+	})
+}
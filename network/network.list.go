@@ -20,6 +20,9 @@ const (
 	filterByName = "name"
 )
 
+// errNoNetworks is returned when no networks match the list options.
+var errNoNetworks = errors.New("no networks found")
+
 type listOptions struct {
 	client  client.SDKClient
 	filters dockerclient.Filters
@@ -103,7 +106,7 @@ func list(ctx context.Context, opts ...ListOptions) ([]network.Summary, error) {
 	}
 
 	if len(list.Items) == 0 {
-		return nws, errors.New("no networks found")
+		return nws, errNoNetworks
 	}
 
 	nws = append(nws, list.Items...)
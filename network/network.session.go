@@ -0,0 +1,69 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moby/moby/api/types/network"
+	dockerclient "github.com/moby/moby/client"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// withSessionFilter adds the filter of the given session to the filters used to list the networks.
+func withSessionFilter(sessionID string) ListOptions {
+	return func(opts *listOptions) error {
+		opts.filters = opts.filters.Clone().Add("label", client.LabelSessionID+"="+sessionID)
+		return nil
+	}
+}
+
+// ListBySession returns the networks created by the client with the given session ID.
+// See [client.SDKClient.SessionID]. It returns an empty list if there are no networks
+// for the session.
+func ListBySession(ctx context.Context, sessionID string, opts ...ListOptions) ([]network.Summary, error) {
+	nws, err := list(ctx, append(opts, withSessionFilter(sessionID))...)
+	if err != nil {
+		if errors.Is(err, errNoNetworks) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return nws, nil
+}
+
+// TerminateBySession removes the networks created by the client with the given session ID.
+// It tries to remove all of them, returning the errors of the ones that could not be removed.
+func TerminateBySession(ctx context.Context, sessionID string, opts ...ListOptions) error {
+	listOpts := &listOptions{}
+	for _, opt := range opts {
+		if err := opt(listOpts); err != nil {
+			return err
+		}
+	}
+
+	if listOpts.client == nil {
+		sdk, err := client.New(ctx)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, WithListClient(sdk))
+		listOpts.client = sdk
+	}
+
+	nws, err := ListBySession(ctx, sessionID, opts...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, nw := range nws {
+		if _, err := listOpts.client.NetworkRemove(ctx, nw.ID, dockerclient.NetworkRemoveOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("terminate network %s: %w", nw.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
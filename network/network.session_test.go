@@ -0,0 +1,39 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/network"
+)
+
+func TestListBySession(t *testing.T) {
+	sdk, err := client.New(context.Background())
+	require.NoError(t, err)
+
+	nw, err := network.New(context.Background(), network.WithClient(sdk))
+	network.Cleanup(t, nw)
+	require.NoError(t, err)
+
+	nws, err := network.ListBySession(context.Background(), sdk.SessionID(), network.WithListClient(sdk))
+	require.NoError(t, err)
+	require.Len(t, nws, 1)
+	require.Equal(t, nw.ID(), nws[0].ID)
+
+	t.Run("other-session", func(t *testing.T) {
+		nws, err := network.ListBySession(context.Background(), "other-session", network.WithListClient(sdk))
+		require.NoError(t, err)
+		require.Empty(t, nws)
+	})
+
+	t.Run("terminate", func(t *testing.T) {
+		require.NoError(t, network.TerminateBySession(context.Background(), sdk.SessionID(), network.WithListClient(sdk)))
+
+		nws, err := network.ListBySession(context.Background(), sdk.SessionID(), network.WithListClient(sdk))
+		require.NoError(t, err)
+		require.Empty(t, nws)
+	})
+}
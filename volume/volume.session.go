@@ -0,0 +1,42 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/go-sdk/client"
+)
+
+// withSessionFilter adds the filter of the given session to the filters used to find the volumes.
+func withSessionFilter(sessionID string) FindOptions {
+	return func(opts *findOptions) error {
+		opts.filters = opts.filters.Clone().Add("label", client.LabelSessionID+"="+sessionID)
+		return nil
+	}
+}
+
+// ListBySession lists the volumes created by the client with the given session ID.
+// See [client.SDKClient.SessionID].
+func ListBySession(ctx context.Context, sessionID string, opts ...FindOptions) ([]Volume, error) {
+	return List(ctx, append(opts, withSessionFilter(sessionID))...)
+}
+
+// TerminateBySession forcibly terminates the volumes created by the client with the given
+// session ID. It tries to terminate all of them, returning the errors of the ones that
+// could not be terminated.
+func TerminateBySession(ctx context.Context, sessionID string, opts ...FindOptions) error {
+	volumes, err := ListBySession(ctx, sessionID, opts...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, v := range volumes {
+		if err := v.Terminate(ctx, WithForce()); err != nil {
+			errs = append(errs, fmt.Errorf("terminate volume %s: %w", v.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,39 @@
+package volume_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/volume"
+)
+
+func TestListBySession(t *testing.T) {
+	sdk, err := client.New(context.Background())
+	require.NoError(t, err)
+
+	v, err := volume.New(context.Background(), volume.WithClient(sdk))
+	volume.Cleanup(t, v)
+	require.NoError(t, err)
+
+	vols, err := volume.ListBySession(context.Background(), sdk.SessionID(), volume.WithFindClient(sdk))
+	require.NoError(t, err)
+	require.Len(t, vols, 1)
+	require.Equal(t, v.Name, vols[0].Name)
+
+	t.Run("other-session", func(t *testing.T) {
+		vols, err := volume.ListBySession(context.Background(), "other-session", volume.WithFindClient(sdk))
+		require.NoError(t, err)
+		require.Empty(t, vols)
+	})
+
+	t.Run("terminate", func(t *testing.T) {
+		require.NoError(t, volume.TerminateBySession(context.Background(), sdk.SessionID(), volume.WithFindClient(sdk)))
+
+		vols, err := volume.ListBySession(context.Background(), sdk.SessionID(), volume.WithFindClient(sdk))
+		require.NoError(t, err)
+		require.Empty(t, vols)
+	})
+}